@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/cli"
+	"github.com/rezmoss/sbomlyze/internal/output"
+	"github.com/rezmoss/sbomlyze/internal/pager"
+	"github.com/rezmoss/sbomlyze/internal/policy"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// runDirectoryDiff pairs files by name across two directories and diffs each pair.
+func runDirectoryDiff(dir1, dir2 string, opts cli.Options, textOpts output.TextOptions, parseOpts *cli.ParseOptions, failConds []policy.FailCondition, minSeverity policy.Severity) {
+	if opts.Format != "text" && opts.Format != "text-wide" && opts.Format != "json" {
+		fmt.Fprintf(os.Stderr, "err: directory mode supports text, text-wide and json output, got %s\n", opts.Format)
+		os.Exit(cli.ExitError)
+	}
+
+	names1, err := listFiles(dir1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "err: read dir %s: %v\n", dir1, err)
+		os.Exit(cli.ExitError)
+	}
+	names2, err := listFiles(dir2)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "err: read dir %s: %v\n", dir2, err)
+		os.Exit(cli.ExitError)
+	}
+
+	var pol *policy.Policy
+	if len(opts.PolicyFiles) > 0 {
+		p := loadPolicies(opts.PolicyFiles)
+		pol = &p
+	}
+	deepThreshold := resolveDeepDepThreshold(opts.DeepDepThreshold, pol)
+
+	paired, onlyBefore, onlyAfter := analysis.PairFiles(names1, names2)
+	dir := analysis.DirDiffResult{OnlyBefore: onlyBefore, OnlyAfter: onlyAfter}
+	var violations []policy.Violation
+	hasDiff := false
+
+	for _, name := range paired {
+		path1, path2 := filepath.Join(dir1, name), filepath.Join(dir2, name)
+		parsed := parseBoth(path1, path2, parseOpts)
+		for i, path := range []string{path1, path2} {
+			if parsed[i].err != nil {
+				fmt.Fprintf(os.Stderr, "err: parse %s: %v\n", path, parsed[i].err)
+				os.Exit(cli.ExitError)
+			}
+		}
+
+		failOnUnknownLicenses(opts, parseOpts.Warnings)
+		warnMixedFormats(parseOpts, path1, path2, parsed[0].info, parsed[1].info)
+		comps1, comps2 := sbom.NormalizeComponents(parsed[0].comps), sbom.NormalizeComponents(parsed[1].comps)
+		warnDroppedConflicts(parseOpts, path1, comps1)
+		warnDroppedConflicts(parseOpts, path2, comps2)
+		result := analysis.DiffComponents(comps1, comps2)
+		analysis.ApplyDeepDepThreshold(&result, deepThreshold)
+		scoreRisk(&result, comps1, pol, opts)
+		dir.Files = append(dir.Files, analysis.FileDiff{Name: name, Diff: result})
+		if hasChanges(result, opts) {
+			hasDiff = true
+		}
+
+		var fileViolations []policy.Violation
+		if pol != nil {
+			fileViolations = policy.EvaluateWithContext(*pol, result, policy.EvalContext{BeforeSuppliers: policy.SupplierSet(comps1)})
+		}
+		fileViolations = append(fileViolations, policy.EvaluateFailOn(failConds, result)...)
+		for _, v := range policy.AtLeast(fileViolations, minSeverity) {
+			v.Message = name + ": " + v.Message
+			violations = append(violations, v)
+		}
+	}
+	dir.Rollup = analysis.ComputeDirRollup(dir.Files)
+
+	p := pager.Start(opts.NoPager)
+
+	if opts.Format == "json" {
+		out := struct {
+			analysis.DirDiffResult
+			Violations []policy.Violation `json:"violations,omitempty"`
+			Warnings   []cli.ParseWarning `json:"warnings,omitempty"`
+		}{
+			DirDiffResult: dir,
+			Violations:    violations,
+			Warnings:      parseOpts.Warnings,
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			p.Stop()
+			fmt.Fprintf(os.Stderr, "err: encode JSON: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+	} else {
+		output.PrintDirectoryDiff(dir, opts.Summary, textOpts)
+		output.PrintViolations(violations, textOpts)
+		cli.PrintWarnings(parseOpts.Warnings)
+	}
+
+	p.Stop()
+	exitForDiff(hasDiff, failConds, violations)
+}
+
+// listFiles returns the names of regular files directly inside dir.
+func listFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rezmoss/sbomlyze/internal/cli"
+)
+
+// checkoutRevisions writes path as of rev1 and rev2 to a temp dir, exiting
+// on error. The copies are named <file>@<rev> so output tells them apart;
+// the caller removes dir.
+func checkoutRevisions(rev1, rev2, path string) (dir string, files []string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		fmt.Fprintf(os.Stderr, "err: --git needs git on PATH: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+	dir, err := os.MkdirTemp("", "sbomlyze-git-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "err: create temp dir: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+	for _, rev := range []string{rev1, rev2} {
+		data, err := gitShow(rev, path)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			fmt.Fprintf(os.Stderr, "err: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+		name := filepath.Join(dir, filepath.Base(path)+"@"+strings.ReplaceAll(rev, "/", "_"))
+		if err := os.WriteFile(name, data, 0o600); err != nil {
+			_ = os.RemoveAll(dir)
+			fmt.Fprintf(os.Stderr, "err: write %s: %v\n", name, err)
+			os.Exit(cli.ExitError)
+		}
+		files = append(files, name)
+	}
+	return dir, files
+}
+
+// removeGitDir removes the copies checkoutRevisions made, if any.
+func removeGitDir(dir string) {
+	if dir != "" {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// gitShow returns path as of rev. A relative path is taken from the
+// working directory, as elsewhere on the command line, not the repo root.
+func gitShow(rev, path string) ([]byte, error) {
+	spec := filepath.ToSlash(filepath.Clean(path))
+	if !filepath.IsAbs(path) && !strings.HasPrefix(spec, "../") {
+		spec = "./" + spec
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "show", rev+":"+spec)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return nil, fmt.Errorf("git show %s:%s: %s", rev, path, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("git show %s:%s: %w", rev, path, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/cli"
+	"github.com/rezmoss/sbomlyze/internal/output"
+	"github.com/rezmoss/sbomlyze/internal/pager"
+	"github.com/rezmoss/sbomlyze/internal/policy"
+)
+
+// printLicenseDiff prints the --diff-licenses report and exits. Only the
+// license rules of a policy apply: it exits 2 if one fails, else 1 if any
+// license changed or a component was added.
+func printLicenseDiff(opts cli.Options, textOpts output.TextOptions, d analysis.LicenseDiff, violations []policy.Violation, warnings []cli.ParseWarning) {
+	violations = policy.LicenseViolations(violations)
+	p := pager.Start(opts.NoPager)
+
+	switch opts.Format {
+	case "json":
+		out := struct {
+			analysis.LicenseDiff
+			Violations []policy.Violation `json:"violations,omitempty"`
+			Warnings   []cli.ParseWarning `json:"warnings,omitempty"`
+		}{
+			LicenseDiff: d,
+			Violations:  violations,
+			Warnings:    warnings,
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			p.Stop()
+			fmt.Fprintf(os.Stderr, "err: encode JSON: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+
+	case "markdown", "md":
+		fmt.Println(output.GenerateLicenseMarkdown(d, violations, textOpts))
+
+	default: // text
+		output.PrintLicenseDiff(d, textOpts)
+		output.PrintViolations(violations, textOpts)
+		cli.PrintWarnings(warnings)
+	}
+
+	p.Stop()
+
+	exitForDiff(d.HasChanges(), nil, violations)
+}
@@ -3,8 +3,14 @@ package main
 import (
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/rezmoss/sbomlyze/internal/analysis"
 	"github.com/rezmoss/sbomlyze/internal/cli"
@@ -14,6 +20,7 @@ import (
 	"github.com/rezmoss/sbomlyze/internal/policy"
 	"github.com/rezmoss/sbomlyze/internal/progress"
 	"github.com/rezmoss/sbomlyze/internal/sbom"
+	"github.com/rezmoss/sbomlyze/internal/state"
 	"github.com/rezmoss/sbomlyze/internal/tui"
 	"github.com/rezmoss/sbomlyze/internal/version"
 	"github.com/rezmoss/sbomlyze/internal/web"
@@ -23,30 +30,56 @@ func main() {
 	for _, arg := range os.Args[1:] {
 		if arg == "--version" || arg == "-v" {
 			fmt.Println(version.Info())
-			os.Exit(0)
+			os.Exit(cli.ExitOK)
 		}
 		if arg == "--help" || arg == "-h" {
 			cli.PrintUsage()
-			os.Exit(0)
+			os.Exit(cli.ExitOK)
 		}
 	}
 
 	if len(os.Args) < 2 {
 		cli.PrintUsage()
-		os.Exit(1)
+		os.Exit(cli.ExitError)
 	}
 
 	opts := cli.ParseArgs(os.Args)
 
+	// https://no-color.org: any non-empty NO_COLOR disables color
+	if opts.NoColor || os.Getenv("NO_COLOR") != "" {
+		opts.NoColor = true
+		os.Setenv("NO_COLOR", "1") // picked up by lipgloss in the TUI
+	}
+	textOpts := output.TextOptions{
+		NoColor:     opts.NoColor,
+		Wide:        opts.Format == "text-wide",
+		GroupByType: opts.GroupByType,
+	}
+	if opts.MaxItems != "" {
+		textOpts.MaxItems = positiveIntFlag("--max-items", opts.MaxItems)
+	}
+
 	if opts.WebServer {
 		port := opts.WebPort
 		if port == 0 {
 			port = 8080
 		}
+		serverOpts := web.ServerOptions{Port: port}
+		if opts.MaxUploadMB != "" {
+			serverOpts.MaxUploadSize = int64(positiveIntFlag("--max-upload-mb", opts.MaxUploadMB)) << 20
+		}
+		if opts.UploadTimeout != "" {
+			d, err := time.ParseDuration(opts.UploadTimeout)
+			if err != nil || d <= 0 {
+				fmt.Fprintf(os.Stderr, "err: --upload-timeout must be a positive duration such as 30s, got %q\n", opts.UploadTimeout)
+				os.Exit(cli.ExitError)
+			}
+			serverOpts.UploadTimeout = d
+		}
 		fmt.Printf("Starting sbomlyze web server at http://localhost:%d\n", port)
-		if err := web.Serve(port); err != nil {
+		if err := web.Serve(serverOpts); err != nil {
 			fmt.Fprintf(os.Stderr, "err: %v\n", err)
-			os.Exit(1)
+			os.Exit(cli.ExitError)
 		}
 		return
 	}
@@ -54,21 +87,21 @@ func main() {
 	if opts.Convert {
 		if len(opts.Files) == 0 {
 			fmt.Fprintf(os.Stderr, "err: no input for convert\n")
-			os.Exit(1)
+			os.Exit(cli.ExitError)
 		}
 		if opts.TargetFormat == "" {
 			fmt.Fprintf(os.Stderr, "err: --to flag required\n")
-			os.Exit(1)
+			os.Exit(cli.ExitError)
 		}
 		targetFmt, err := convert.ParseFormat(opts.TargetFormat)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "err: %v\n", err)
-			os.Exit(1)
+			os.Exit(cli.ExitError)
 		}
-		comps, info, err := sbom.ParseFileWithInfo(opts.Files[0])
+		comps, info, err := sbom.ParseFileWithOptions(opts.Files[0], sbom.ReadOptions{})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "err: parse %s: %v\n", opts.Files[0], err)
-			os.Exit(1)
+			os.Exit(cli.ExitError)
 		}
 		comps = sbom.NormalizeComponents(comps)
 
@@ -77,7 +110,7 @@ func main() {
 			w, err = os.Create(opts.OutputFile)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "err: create output: %v\n", err)
-				os.Exit(1)
+				os.Exit(cli.ExitError)
 			}
 			defer func() { _ = w.Close() }()
 		} else {
@@ -85,40 +118,155 @@ func main() {
 		}
 		if err := convert.Convert(w, comps, info, targetFmt); err != nil {
 			fmt.Fprintf(os.Stderr, "err: convert: %v\n", err)
-			os.Exit(1)
+			os.Exit(cli.ExitError)
 		}
 		return
 	}
 
+	if opts.FilesFrom != "" {
+		paths, err := cli.ReadFileList(opts.FilesFrom)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "err: --files-from: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+		opts.Files = append(opts.Files, paths...)
+	}
+
+	// --git diffs copies of the file taken from each revision
+	var gitDir string
+	if opts.Git {
+		if len(opts.GitArgs) != 3 || len(opts.Files) > 0 {
+			fmt.Fprintf(os.Stderr, "err: --git takes <rev1> <rev2> <path> and no other files\n")
+			os.Exit(cli.ExitError)
+		}
+		gitDir, opts.Files = checkoutRevisions(opts.GitArgs[0], opts.GitArgs[1], opts.GitArgs[2])
+		defer removeGitDir(gitDir)
+	}
+
 	if len(opts.Files) == 0 {
 		fmt.Fprintf(os.Stderr, "err: no input files\n")
-		os.Exit(1)
+		os.Exit(cli.ExitError)
 	}
 
-	parseOpts := cli.ParseOptions{Strict: opts.Strict}
+	if opts.Validate {
+		if len(opts.Files) != 1 {
+			fmt.Fprintf(os.Stderr, "err: --validate takes exactly one file\n")
+			os.Exit(cli.ExitError)
+		}
+		runValidate(opts.Files[0], opts)
+		return
+	}
 
-	if len(opts.Files) == 1 {
-		spin := progress.New(opts.JSONOutput || opts.Interactive)
+	if (len(opts.Include) > 0 || len(opts.Exclude) > 0) && !opts.List {
+		fmt.Fprintf(os.Stderr, "err: --include and --exclude need --list\n")
+		os.Exit(cli.ExitError)
+	}
+	if opts.List && ((len(opts.Files) != 1 && !opts.Merge) || opts.StatePath != "") {
+		fmt.Fprintf(os.Stderr, "err: --list takes one SBOM, or several with --merge\n")
+		os.Exit(cli.ExitError)
+	}
+	var baseline *analysis.HealthBaseline
+	if opts.BaselineStats != "" {
+		if (len(opts.Files) != 1 && !opts.Merge) || opts.StatePath != "" || opts.List {
+			fmt.Fprintf(os.Stderr, "err: --baseline-stats takes one SBOM, or several with --merge\n")
+			os.Exit(cli.ExitError)
+		}
+		data, err := os.ReadFile(opts.BaselineStats)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "err: read baseline: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+		b, err := analysis.ParseHealthBaseline(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "err: parse baseline %s: %v\n", opts.BaselineStats, err)
+			os.Exit(cli.ExitError)
+		}
+		baseline = &b
+	}
+	if opts.BeforeOnly || opts.AfterOnly {
+		switch {
+		case opts.BeforeOnly && opts.AfterOnly:
+			fmt.Fprintf(os.Stderr, "err: --before-only and --after-only cannot be combined\n")
+			os.Exit(cli.ExitError)
+		case len(opts.Files) != 2 || opts.Merge || opts.StatePath != "" || isDir(opts.Files[0]) || isDir(opts.Files[1]):
+			fmt.Fprintf(os.Stderr, "err: --before-only and --after-only take two SBOM files\n")
+			os.Exit(cli.ExitError)
+		}
+	}
+
+	parseOpts := cli.ParseOptions{Strict: opts.Strict, KeepRaw: opts.Interactive, DropInvalid: opts.DropInvalid}
+
+	// with --state the one file is diffed against the latest snapshot
+	var store *state.Store
+	if opts.StatePath != "" {
+		if len(opts.Files) != 1 || opts.Merge {
+			fmt.Fprintf(os.Stderr, "err: --state takes exactly one file\n")
+			os.Exit(cli.ExitError)
+		}
+		store = loadState(opts.StatePath)
+		if _, ok := store.Latest(); !ok {
+			comps, info, err := parseFileWithOptionsAndInfo(opts.Files[0], &parseOpts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "err: parse %s: %v\n", opts.Files[0], err)
+				os.Exit(cli.ExitError)
+			}
+			failOnUnknownLicenses(opts, parseOpts.Warnings)
+			snap := saveSnapshot(store, opts, opts.Files[0], comps, info)
+			fmt.Fprintf(os.Stderr, "state: no snapshot in %s yet; recorded %q (%d components)\n", opts.StatePath, snap.Label, len(comps))
+			return
+		}
+	}
+
+	if (len(opts.Files) == 1 && store == nil) || opts.Merge {
+		spin := progress.New(opts.JSONOutput || opts.Format == "jsonl" || opts.Format == "table" || opts.Format == "badge" || opts.Interactive || opts.NoColor)
+		timer := progress.NewTimer(opts.Timing)
 
 		spin.Start("Parsing...")
-		comps, sbomInfo, err := parseFileWithOptionsAndInfo(opts.Files[0], &parseOpts)
+		var comps []sbom.Component
+		var sbomInfo sbom.SBOMInfo
+		var err error
+		if opts.Merge {
+			comps, sbomInfo, err = parseMerged(opts.Files, &parseOpts)
+		} else if comps, sbomInfo, err = parseFileWithOptionsAndInfo(opts.Files[0], &parseOpts); err != nil {
+			err = fmt.Errorf("%s: %w", opts.Files[0], err)
+		}
 		if err != nil {
 			spin.Stop()
-			fmt.Fprintf(os.Stderr, "err: parse %s: %v\n", opts.Files[0], err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "err: parse %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+		if opts.Merge {
+			spin.Done(fmt.Sprintf("Merged %d files into %d components", len(opts.Files), len(comps)))
+		} else {
+			spin.Done(fmt.Sprintf("Parsed %d components", len(comps)))
 		}
-		spin.Done(fmt.Sprintf("Parsed %d components", len(comps)))
+		failOnUnknownLicenses(opts, parseOpts.Warnings)
+		timer.Phase("parse")
 
 		spin.Start("Analyzing...")
 		comps = sbom.NormalizeComponents(comps)
+		if opts.Explain {
+			sbom.ExplainIDs(comps)
+		}
+		if opts.List {
+			spin.Stop()
+			printComponentList(opts, policy.FilterPackages(comps, opts.Include, opts.Exclude), parseOpts.Warnings)
+			return
+		}
 		stats := analysis.ComputeStats(comps)
 		findings := analysis.ComputeSingleFindings(stats, sbomInfo, comps)
+		var checks []analysis.HealthCheck
+		if baseline != nil {
+			checks = analysis.CheckHealth(stats, *baseline)
+		}
 		spin.Done("Done")
+		timer.Phase("analysis")
+		timer.Total()
 
 		if opts.Interactive {
 			if err := tui.Run(comps, stats, sbomInfo); err != nil {
 				fmt.Fprintf(os.Stderr, "err: interactive mode: %v\n", err)
-				os.Exit(1)
+				os.Exit(cli.ExitError)
 			}
 			return
 		}
@@ -133,79 +281,227 @@ func main() {
 				Findings analysis.KeyFindings  `json:"findings"`
 				Stats    analysis.Stats        `json:"stats"`
 				Warnings []cli.ParseWarning    `json:"warnings,omitempty"`
+				Identities []output.IdentityNote `json:"identities,omitempty"`
+				Baseline []analysis.HealthCheck `json:"baseline_checks,omitempty"`
 			}{
 				Info:     sbomInfo,
 				Findings: findings,
 				Stats:    stats,
 				Warnings: parseOpts.Warnings,
+				Baseline: checks,
+			}
+			if opts.Explain {
+				out.Identities = output.IdentityNotes(comps)
 			}
 			enc := json.NewEncoder(os.Stdout)
 			enc.SetIndent("", "  ")
 			if err := enc.Encode(out); err != nil {
 				p.Stop()
 				fmt.Fprintf(os.Stderr, "err: encode JSON: %v\n", err)
-				os.Exit(1)
+				os.Exit(cli.ExitError)
+			}
+		case "jsonl":
+			cli.PrintWarningsTo(os.Stderr, parseOpts.Warnings)
+			if err := output.WriteJSONL(os.Stdout, comps); err != nil {
+				p.Stop()
+				fmt.Fprintf(os.Stderr, "err: encode JSONL: %v\n", err)
+				os.Exit(cli.ExitError)
 			}
 		case "html":
 			fmt.Println(output.GenerateHTMLStats(stats, sbomInfo, findings))
+		case "table":
+			cli.PrintWarningsTo(os.Stderr, parseOpts.Warnings)
+			analysis.PrintStatsTable(stats)
+		case "badge":
+			writeBadge(p, output.NewStatsBadge(stats), parseOpts.Warnings)
 		default:
 			output.PrintSingleScanContext(sbomInfo)
-			output.PrintKeyFindings(findings)
+			output.PrintKeyFindings(findings, textOpts)
 			analysis.PrintStats(stats)
+			if opts.Explain {
+				output.PrintIdentityBasis(comps, textOpts)
+			}
+			if baseline != nil {
+				output.PrintHealthChecks(checks, textOpts)
+			}
 			cli.PrintWarnings(parseOpts.Warnings)
 		}
+		if analysis.HasRegression(checks) {
+			p.Stop()
+			if opts.Format != "" && opts.Format != "text" && opts.Format != "json" {
+				// the other formats have no place for the checks
+				for _, c := range checks {
+					if c.Regressed {
+						fmt.Fprintf(os.Stderr, "baseline: %s %.1f%% is below the %s %.1f%%\n", c.Metric, c.Current, c.Kind, c.Expected)
+					}
+				}
+			}
+			os.Exit(cli.ExitPolicy)
+		}
 		return
 	}
 
-	file1, file2 := opts.Files[0], opts.Files[1]
-	spin := progress.New(opts.Format != "" && opts.Format != "text")
+	var file1, file2 string
+	var prev state.Snapshot
+	if store != nil {
+		prev, _ = store.Latest()
+		file1, file2 = opts.StatePath+"#"+prev.Label, opts.Files[0]
+	} else {
+		file1, file2 = opts.Files[0], opts.Files[1]
+	}
 
-	spin.Start("Parsing first...")
-	comps1, info1, err := parseFileWithOptionsAndInfo(file1, &parseOpts)
-	if err != nil {
-		spin.Stop()
-		fmt.Fprintf(os.Stderr, "err: parse %s: %v\n", file1, err)
-		os.Exit(1)
+	var failConds []policy.FailCondition
+	if opts.FailOn != "" {
+		conds, err := policy.ParseFailOn(opts.FailOn)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "err: parse --fail-on: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+		failConds = conds
+	}
+	minSeverity := policy.SeverityWarning
+	if opts.MinSeverity != "" {
+		sev, err := policy.ParseSeverity(opts.MinSeverity)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "err: parse --min-severity: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+		minSeverity = sev
 	}
-	spin.Done(fmt.Sprintf("Parsed %d components", len(comps1)))
 
-	spin.Start("Parsing second...")
-	comps2, info2, err := parseFileWithOptionsAndInfo(file2, &parseOpts)
-	if err != nil {
-		spin.Stop()
-		fmt.Fprintf(os.Stderr, "err: parse %s: %v\n", file2, err)
-		os.Exit(1)
+	if err := analysis.ValidateCategories(opts.Only); err != nil {
+		fmt.Fprintf(os.Stderr, "err: parse --only: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+	if opts.DiffLicenses {
+		switch {
+		case opts.DepsOnly || len(opts.Only) > 0 || opts.FailOn != "":
+			fmt.Fprintf(os.Stderr, "err: --diff-licenses cannot be combined with --only, --diff-deps-only or --fail-on\n")
+			os.Exit(cli.ExitError)
+		case opts.Format != "" && opts.Format != "text" && opts.Format != "json" && opts.Format != "markdown" && opts.Format != "md":
+			fmt.Fprintf(os.Stderr, "err: --diff-licenses supports text, markdown and json output\n")
+			os.Exit(cli.ExitError)
+		}
+	}
+	if opts.DepsOnly {
+		if len(opts.Only) > 0 {
+			fmt.Fprintf(os.Stderr, "err: --diff-deps-only cannot be combined with --only\n")
+			os.Exit(cli.ExitError)
+		}
+		opts.Only = []string{analysis.CategoryDeps}
+	}
+
+	if isDir(file1) && isDir(file2) {
+		runDirectoryDiff(file1, file2, opts, textOpts, &parseOpts, failConds, minSeverity)
+		return
 	}
-	spin.Done(fmt.Sprintf("Parsed %d components", len(comps2)))
+
+	var pol *policy.Policy
+	if len(opts.PolicyFiles) > 0 {
+		p := loadPolicies(opts.PolicyFiles)
+		pol = &p
+	}
+	deepThreshold := resolveDeepDepThreshold(opts.DeepDepThreshold, pol)
+	spin := progress.New((opts.Format != "" && opts.Format != "text" && opts.Format != "text-wide") || opts.NoColor)
+	timer := progress.NewTimer(opts.Timing)
+
+	spin.Start("Parsing...")
+	var parsed [2]parsedSBOM
+	if store != nil {
+		parsed[0] = parsedSBOM{comps: prev.Components, info: prev.Info}
+		parsed[1].comps, parsed[1].info, parsed[1].err = parseFileWithOptionsAndInfo(file2, &parseOpts)
+	} else {
+		parsed = parseBoth(file1, file2, &parseOpts)
+	}
+	for i, path := range []string{file1, file2} {
+		if parsed[i].err != nil {
+			spin.Stop()
+			removeGitDir(gitDir)
+			fmt.Fprintf(os.Stderr, "err: parse %s: %v\n", path, parsed[i].err)
+			os.Exit(cli.ExitError)
+		}
+	}
+	comps1, info1 := parsed[0].comps, parsed[0].info
+	comps2, info2 := parsed[1].comps, parsed[1].info
+	warnMixedFormats(&parseOpts, file1, file2, info1, info2)
+	spin.Done(fmt.Sprintf("Parsed %d + %d components", len(comps1), len(comps2)))
+	failOnUnknownLicenses(opts, parseOpts.Warnings)
+	timer.Phase("parse")
 
 	spin.Start("Comparing...")
 	comps1 = sbom.NormalizeComponents(comps1)
 	comps2 = sbom.NormalizeComponents(comps2)
+	if opts.Explain {
+		sbom.ExplainIDs(comps1)
+		sbom.ExplainIDs(comps2)
+	}
+	warnDroppedConflicts(&parseOpts, file1, comps1)
+	warnDroppedConflicts(&parseOpts, file2, comps2)
+	if opts.BeforeOnly || opts.AfterOnly {
+		spin.Stop()
+		if opts.BeforeOnly {
+			dumpComponents(comps1, parseOpts.Warnings)
+		} else {
+			dumpComponents(comps2, parseOpts.Warnings)
+		}
+		return
+	}
+
+	// the diff includes the dependency reachability walk; the overview
+	// stats reuse its graphs
+	graph1, graph2 := analysis.NewGraph(comps1), analysis.NewGraph(comps2)
+	result := analysis.DiffComponentsWithGraphs(comps1, comps2, graph1, graph2)
+	analysis.ApplyDeepDepThreshold(&result, deepThreshold)
+	scoreRisk(&result, comps1, pol, opts)
+	timer.Phase("diff")
 
-	overview := analysis.ComputeDiffOverview(file1, file2, comps1, comps2, info1, info2)
-	result := analysis.DiffComponents(comps1, comps2)
+	overview := analysis.ComputeDiffOverview(file1, file2, comps1, comps2, graph1, graph2, info1, info2)
+	removeGitDir(gitDir) // only the overview's file sizes needed the copies
 	analysis.ComputePackageSamples(&result)
 	findings := analysis.ComputeKeyFindings(result, overview)
+	var statsDelta *analysis.StatsDelta
+	if opts.StatsDelta {
+		d := analysis.ComputeStatsDelta(overview.Before.Stats, overview.After.Stats)
+		statsDelta = &d
+	}
 	spin.Done("Done")
 
 	var violations []policy.Violation
-	if opts.PolicyFile != "" {
-		policyData, err := os.ReadFile(opts.PolicyFile)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "err: read policy: %v\n", err)
-			os.Exit(1)
-		}
-		pol, err := policy.Load(policyData)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "err: parse policy: %v\n", err)
-			os.Exit(1)
+	if pol != nil {
+		violations = policy.EvaluateWithContext(*pol, result, policy.EvalContext{BeforeSuppliers: policy.SupplierSet(comps1)})
+	}
+	violations = append(violations, policy.EvaluateFailOn(failConds, result)...)
+	// --min-severity only hides warnings, so exit codes are unchanged
+	violations = policy.AtLeast(violations, minSeverity)
+	timer.Phase("analysis")
+	if store != nil {
+		saveSnapshot(store, opts, file2, parsed[1].comps, info2)
+	}
+	timer.Total()
+
+	// --only narrows what is shown; exit codes use the full result
+	// (or just the graph with --diff-deps-only)
+	shown := analysis.FilterCategories(result, opts.Only)
+
+	sbomFile := file2
+
+	if opts.CPEList {
+		for _, cpe := range output.CPEList(result) {
+			fmt.Println(cpe)
 		}
-		violations = policy.Evaluate(pol, result)
+		exitForDiff(hasChanges(result, opts), failConds, violations)
+		return
+	}
+
+	if opts.Fingerprint {
+		fmt.Println(result.Fingerprint())
+		exitForDiff(hasChanges(result, opts), failConds, violations)
+		return
 	}
 
-	sbomFile := ""
-	if len(opts.Files) > 1 {
-		sbomFile = opts.Files[1]
+	if opts.DiffLicenses {
+		printLicenseDiff(opts, textOpts, analysis.ComputeLicenseDiff(comps1, result), violations, parseOpts.Warnings)
+		return
 	}
 
 	p := pager.Start(opts.NoPager)
@@ -213,26 +509,65 @@ func main() {
 	switch opts.Format {
 	case "json":
 		out := struct {
-			Overview   analysis.DiffOverview `json:"overview"`
-			Findings   analysis.KeyFindings  `json:"findings"`
-			Diff       analysis.DiffResult   `json:"diff"`
-			Violations []policy.Violation    `json:"violations,omitempty"`
-			Warnings   []cli.ParseWarning    `json:"warnings,omitempty"`
+			Overview    analysis.DiffOverview `json:"overview"`
+			Findings    analysis.KeyFindings  `json:"findings"`
+			StatsDelta  *analysis.StatsDelta  `json:"stats_delta,omitempty"`
+			Diff        analysis.DiffResult   `json:"diff"`
+			Summary     analysis.DiffStats    `json:"summary"`
+			Fingerprint string                `json:"fingerprint"`
+			Violations  []policy.Violation    `json:"violations,omitempty"`
+			Warnings    []cli.ParseWarning    `json:"warnings,omitempty"`
 		}{
-			Overview:   overview,
-			Findings:   findings,
-			Diff:       result,
-			Violations: violations,
-			Warnings:   parseOpts.Warnings,
+			Overview:    overview,
+			Findings:    findings,
+			StatsDelta:  statsDelta,
+			Diff:        shown,
+			Summary:     result.Summary(),
+			Fingerprint: result.Fingerprint(),
+			Violations:  violations,
+			Warnings:    parseOpts.Warnings,
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		if err := enc.Encode(out); err != nil {
 			p.Stop()
 			fmt.Fprintf(os.Stderr, "err: encode JSON: %v\n", err)
-			os.Exit(1)
+			os.Exit(cli.ExitError)
+		}
+
+	case "ndjson-events":
+		cli.PrintWarningsTo(os.Stderr, parseOpts.Warnings)
+		if err := output.WriteDiffEvents(os.Stdout, shown, result.Summary(), violations); err != nil {
+			p.Stop()
+			fmt.Fprintf(os.Stderr, "err: encode NDJSON: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+
+	case "summary-json":
+		cli.PrintWarningsTo(os.Stderr, parseOpts.Warnings)
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(output.NewDiffSummary(result.Summary(), violations)); err != nil {
+			p.Stop()
+			fmt.Fprintf(os.Stderr, "err: encode JSON: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+
+	case "prometheus":
+		cli.PrintWarningsTo(os.Stderr, parseOpts.Warnings)
+		if err := output.WritePrometheus(os.Stdout, result.Summary(), len(comps2), sbomFile); err != nil {
+			p.Stop()
+			fmt.Fprintf(os.Stderr, "err: write metrics: %v\n", err)
+			os.Exit(cli.ExitError)
 		}
 
+	case "diffstat":
+		cli.PrintWarningsTo(os.Stderr, parseOpts.Warnings)
+		fmt.Println(output.Diffstat(result.Summary()))
+
+	case "badge":
+		writeBadge(p, output.NewDiffBadge(hasChanges(result, opts), result.Summary(), violations), parseOpts.Warnings)
+
 	case "sarif":
 		sarif := output.GenerateSARIF(result, violations, sbomFile)
 		enc := json.NewEncoder(os.Stdout)
@@ -240,7 +575,7 @@ func main() {
 		if err := enc.Encode(sarif); err != nil {
 			p.Stop()
 			fmt.Fprintf(os.Stderr, "err: encode SARIF: %v\n", err)
-			os.Exit(1)
+			os.Exit(cli.ExitError)
 		}
 
 	case "junit":
@@ -249,15 +584,29 @@ func main() {
 		if err != nil {
 			p.Stop()
 			fmt.Fprintf(os.Stderr, "err: encode JUnit: %v\n", err)
-			os.Exit(1)
+			os.Exit(cli.ExitError)
 		}
 		fmt.Println(xml.Header + string(out))
 
 	case "markdown", "md":
-		fmt.Println(output.GenerateMarkdownWithOverview(result, violations, overview, findings))
+		fmt.Println(output.GenerateMarkdownWithOverview(shown, violations, overview, findings, textOpts))
 
 	case "html":
-		fmt.Println(output.GenerateHTML(result, violations, overview, findings))
+		fmt.Println(output.GenerateHTML(shown, violations, overview, findings))
+
+	case "cyclonedx", "cdx":
+		if err := convert.WriteCycloneDXDiff(os.Stdout, result, info2); err != nil {
+			p.Stop()
+			fmt.Fprintf(os.Stderr, "err: encode CycloneDX: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+
+	case "spdx-diff":
+		if err := convert.WriteSPDXDiff(os.Stdout, result, info2); err != nil {
+			p.Stop()
+			fmt.Fprintf(os.Stderr, "err: encode SPDX: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
 
 	case "patch":
 		patch := output.GenerateJSONPatch(result)
@@ -265,37 +614,281 @@ func main() {
 		if err != nil {
 			p.Stop()
 			fmt.Fprintf(os.Stderr, "err: encode patch: %v\n", err)
-			os.Exit(1)
+			os.Exit(cli.ExitError)
 		}
 		fmt.Println(string(out))
 
 	default: // text
-		output.PrintDiffOverview(overview)
-		output.PrintScanContext(overview)
-		output.PrintKeyFindings(findings)
-		output.PrintPackageSamples(result.AddedByType, result.RemovedByType)
-		output.PrintTextDiff(result)
-		output.PrintViolations(violations)
+		if opts.SortRisk {
+			textOpts.RiskScores = analysis.RiskScoreByID(result.RiskScores)
+		}
+		switch {
+		case opts.Summary:
+			output.PrintTextSummary(result, textOpts)
+		case len(opts.Only) > 0:
+			output.PrintTextDiff(shown, textOpts)
+		default:
+			output.PrintDiffOverview(overview)
+			output.PrintScanContext(overview)
+			if statsDelta != nil {
+				output.PrintStatsDelta(*statsDelta)
+			}
+			output.PrintKeyFindings(findings, textOpts)
+			output.PrintPackageSamples(result.AddedByType, result.RemovedByType)
+			output.PrintTextDiff(result, textOpts)
+		}
+		output.PrintViolations(violations, textOpts)
 		cli.PrintWarnings(parseOpts.Warnings)
 	}
 
 	p.Stop()
 
-	hasDiff := len(result.Added) > 0 || len(result.Removed) > 0 || len(result.Changed) > 0
-	hasPolicyErrors := policy.HasErrors(violations)
-	if hasDiff || hasPolicyErrors {
-		os.Exit(1)
+	exitForDiff(hasChanges(result, opts), failConds, violations)
+}
+
+// hasChanges reports whether the diff counts as a difference for the exit
+// code. With --diff-deps-only only dependency graph changes count; with
+// --ignore-version-changes routine version bumps do not, and with
+// --ignore-metadata-drift neither do metadata-only changes.
+func hasChanges(result analysis.DiffResult, opts cli.Options) bool {
+	if opts.DepsOnly {
+		return result.Dependencies != nil && !result.Dependencies.IsEmpty()
+	}
+	if len(result.Added) > 0 || len(result.Removed) > 0 {
+		return true
+	}
+	return slices.ContainsFunc(result.Changed, func(c analysis.ChangedComponent) bool {
+		if opts.IgnoreVersions && c.IsRoutineVersionChange() {
+			return false
+		}
+		if opts.IgnoreMetadata && c.Drift != nil && c.Drift.Type == analysis.DriftTypeMetadata {
+			return false
+		}
+		return true
+	})
+}
+
+// exitForDiff exits 2 on policy errors, else 1 on any difference.
+// --fail-on replaces the default "any difference" rule.
+func exitForDiff(hasDiff bool, failConds []policy.FailCondition, violations []policy.Violation) {
+	if policy.HasErrors(violations) {
+		os.Exit(cli.ExitPolicy)
+	}
+	if hasDiff && len(failConds) == 0 {
+		os.Exit(cli.ExitDiff)
+	}
+}
+
+// printComponentList prints the --list inventory. stdout is the list only;
+// warnings go to stderr.
+func printComponentList(opts cli.Options, comps []sbom.Component, warnings []cli.ParseWarning) {
+	cli.PrintWarningsTo(os.Stderr, warnings)
+	entries := output.ListEntries(comps)
+	var err error
+	if opts.Format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(entries)
+	} else {
+		err = output.WriteComponentList(os.Stdout, entries)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "err: write list: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+}
+
+// dumpComponents prints one side's normalized components as JSON, for
+// --before-only and --after-only. Warnings go to stderr.
+func dumpComponents(comps []sbom.Component, warnings []cli.ParseWarning) {
+	cli.PrintWarningsTo(os.Stderr, warnings)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(comps); err != nil {
+		fmt.Fprintf(os.Stderr, "err: encode JSON: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+}
+
+// writeBadge writes b as shields.io endpoint JSON, exiting on error.
+// stdout is the badge only; warnings go to stderr.
+func writeBadge(p *pager.Pager, b output.Badge, warnings []cli.ParseWarning) {
+	cli.PrintWarningsTo(os.Stderr, warnings)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(b); err != nil {
+		p.Stop()
+		fmt.Fprintf(os.Stderr, "err: encode badge: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+}
+
+// loadPolicies loads each policy file and merges them.
+func loadPolicies(paths []string) policy.Policy {
+	pols := make([]policy.Policy, 0, len(paths))
+	for _, path := range paths {
+		policyData, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "err: read policy: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+		pol, err := policy.Load(policyData)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "err: parse policy %s: %v\n", path, err)
+			os.Exit(cli.ExitError)
+		}
+		pols = append(pols, pol)
 	}
+	return policy.Merge(pols...)
+}
+
+// parseMerged parses each file and merges the components into one
+// inventory for --merge.
+func parseMerged(paths []string, opts *cli.ParseOptions) ([]sbom.Component, sbom.SBOMInfo, error) {
+	lists := make([][]sbom.Component, 0, len(paths))
+	infos := make([]sbom.SBOMInfo, 0, len(paths))
+	for _, path := range paths {
+		comps, info, err := parseFileWithOptionsAndInfo(path, opts)
+		if err != nil {
+			return nil, sbom.SBOMInfo{}, fmt.Errorf("%s: %w", path, err)
+		}
+		lists = append(lists, sbom.NormalizeComponents(comps))
+		infos = append(infos, info)
+	}
+	return analysis.MergeComponents(lists...), analysis.MergeInfo(infos...), nil
+}
+
+// failOnUnknownLicenses exits with an error listing every unknown SPDX
+// license ID when --strict-licenses is set.
+func failOnUnknownLicenses(opts cli.Options, warnings []cli.ParseWarning) {
+	if !opts.StrictLicenses {
+		return
+	}
+	failed := false
+	for _, w := range warnings {
+		if w.Code == sbom.IssueUnknownLicenseID {
+			fmt.Fprintf(os.Stderr, "err: [%s] %s\n", w.File, w.Message)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(cli.ExitError)
+	}
+}
+
+// warnMixedFormats warns when the two sides of a diff are different
+// formats, whose tools fill in licenses differently.
+func warnMixedFormats(opts *cli.ParseOptions, file1, file2 string, info1, info2 sbom.SBOMInfo) {
+	if info1.Format == "" || info2.Format == "" || info1.Format == info2.Format {
+		return
+	}
+	opts.AddWarning(cli.WarnMixedFormats, file2,
+		fmt.Sprintf("comparing %s with %s; tools populate fields differently, so metadata changes may be over-reported",
+			info1.Format, info2.Format), "format")
+}
+
+// warnDroppedConflicts warns about same-ID entries the diff discards
+// although their licenses or hashes differ from the one it keeps.
+func warnDroppedConflicts(opts *cli.ParseOptions, path string, comps []sbom.Component) {
+	for _, c := range analysis.DetectDroppedConflicts(comps) {
+		opts.AddWarning(cli.WarnConflictingDuplicate, path,
+			fmt.Sprintf("%s@%s is listed again with different metadata (%s); only the first entry is diffed",
+				c.Kept.Name, c.Kept.Version, strings.Join(c.Changes, ", ")), "id")
+	}
+}
+
+// scoreRisk computes the risk scores of result with the policy's weights
+// and, with --sort-risk, orders the listings by them.
+func scoreRisk(result *analysis.DiffResult, before []sbom.Component, pol *policy.Policy, opts cli.Options) {
+	weights := analysis.DefaultRiskWeights()
+	if pol != nil {
+		weights = weights.WithOverrides(pol.RiskWeights)
+	}
+	analysis.ComputeRiskScores(result, before, weights)
+	if opts.SortRisk {
+		analysis.SortByRisk(result)
+	}
+}
+
+// resolveDeepDepThreshold returns the --deep-dep-threshold value, falling
+// back to the policy's. 0 keeps the default.
+func resolveDeepDepThreshold(flag string, pol *policy.Policy) int {
+	if flag != "" {
+		return positiveIntFlag("--deep-dep-threshold", flag)
+	}
+	if pol != nil {
+		return pol.DeepDepThreshold
+	}
+	return 0
+}
+
+// positiveIntFlag parses the value of a flag that takes a count of at
+// least 1, exiting with an error otherwise.
+func positiveIntFlag(name, value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		fmt.Fprintf(os.Stderr, "err: %s must be a positive integer, got %q\n", name, value)
+		os.Exit(cli.ExitError)
+	}
+	return n
+}
+
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+// parsedSBOM is one result of parseBoth.
+type parsedSBOM struct {
+	comps []sbom.Component
+	info  sbom.SBOMInfo
+	err   error
+}
+
+// parseBoth parses two files concurrently. Each parse collects warnings on
+// its own, and they are appended to opts in argument order, so the result
+// does not depend on which parse finishes first.
+func parseBoth(file1, file2 string, opts *cli.ParseOptions) [2]parsedSBOM {
+	var out [2]parsedSBOM
+	var local [2]cli.ParseOptions
+	var wg sync.WaitGroup
+	for i, path := range []string{file1, file2} {
+		local[i] = cli.ParseOptions{Strict: opts.Strict, KeepRaw: opts.KeepRaw, DropInvalid: opts.DropInvalid}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i].comps, out[i].info, out[i].err = parseFileWithOptionsAndInfo(path, &local[i])
+		}()
+	}
+	wg.Wait()
+	for i := range local {
+		opts.Warnings = append(opts.Warnings, local[i].Warnings...)
+	}
+	return out
 }
 
 func parseFileWithOptionsAndInfo(path string, opts *cli.ParseOptions) ([]sbom.Component, sbom.SBOMInfo, error) {
-	comps, info, err := sbom.ParseFileWithInfo(path)
+	comps, info, err := sbom.ParseFileWithOptions(path, sbom.ReadOptions{KeepRaw: opts.KeepRaw})
 	if err != nil {
 		if opts.Strict {
 			return nil, sbom.SBOMInfo{}, err
 		}
-		opts.AddWarning(path, err.Error(), "")
+		code := cli.WarnParseError
+		if errors.Is(err, sbom.ErrUnknownFormat) {
+			code = cli.WarnUnknownFormat
+		}
+		opts.AddWarning(code, path, err.Error(), "")
 		return []sbom.Component{}, sbom.SBOMInfo{}, nil
 	}
+	if opts.DropInvalid {
+		kept := sbom.DropPlaceholderNames(comps)
+		if n := len(comps) - len(kept); n > 0 {
+			opts.AddWarning(cli.WarnDroppedInvalid, path, fmt.Sprintf("dropped %d components with empty or placeholder names", n), "name")
+		}
+		comps = kept
+	}
+	for _, issue := range append(info.ParseIssues, sbom.Validate(comps)...) {
+		opts.AddWarning(issue.Code, path, issue.Message, issue.Field)
+	}
 	return comps, info, nil
 }
@@ -4,11 +4,21 @@ import (
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/cli"
+	"github.com/rezmoss/sbomlyze/internal/output"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+	"github.com/rezmoss/sbomlyze/internal/state"
+	spdxjson "github.com/spdx/tools-golang/json"
 )
 
 var binaryPath string
@@ -36,7 +46,19 @@ func TestMain(m *testing.M) {
 }
 
 func runCLI(args ...string) (stdout, stderr string, exitCode int) {
+	return runCLIWithEnv(nil, args...)
+}
+
+// runCLIWithEnv runs the binary with extra environment variables.
+// NO_COLOR is cleared from the inherited environment so output stays stable.
+func runCLIWithEnv(env []string, args ...string) (stdout, stderr string, exitCode int) {
 	cmd := exec.Command(binaryPath, args...)
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "NO_COLOR=") {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	cmd.Env = append(cmd.Env, env...)
 	var outBuf, errBuf bytes.Buffer
 	cmd.Stdout = &outBuf
 	cmd.Stderr = &errBuf
@@ -112,8 +134,8 @@ func TestHelpFlag(t *testing.T) {
 func TestNoArgsShowsHelp(t *testing.T) {
 	_, stderr, exitCode := runCLI()
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1 for no args, got %d", exitCode)
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3 for no args, got %d", exitCode)
 	}
 	if !strings.Contains(stderr, "Usage:") {
 		t.Errorf("expected usage message in stderr")
@@ -154,6 +176,354 @@ func TestStatsModeJSON(t *testing.T) {
 	}
 }
 
+func TestStatsModeCycloneDXLicenseName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "licenses.json")
+	data := `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"components":[
+		{"type":"library","name":"a","version":"1.0","licenses":[{"license":{"id":"Apache-2.0"}}]},
+		{"type":"library","name":"b","version":"1.0","licenses":[{"license":{"name":"Apache 2.0"}}]},
+		{"type":"library","name":"c","version":"1.0","licenses":[{"expression":"MIT OR Apache-2.0"}]}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, exitCode := runCLI(path, "--json")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", exitCode, stderr)
+	}
+	var out struct {
+		Stats analysis.Stats `json:"stats"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if out.Stats.ByLicense["Apache-2.0"] != 2 {
+		t.Errorf("expected license name to normalize to Apache-2.0, got %v", out.Stats.ByLicense)
+	}
+	if out.Stats.ByLicense["MIT OR Apache-2.0"] != 1 {
+		t.Errorf("expected license expression to be counted, got %v", out.Stats.ByLicense)
+	}
+	if out.Stats.WithoutLicense != 0 {
+		t.Errorf("expected no components without license, got %d", out.Stats.WithoutLicense)
+	}
+}
+
+func TestStatsModePURLVersionMismatchWarning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mismatch.json")
+	data := `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"components":[
+		{"type":"library","name":"lodash","version":"4.17.20","purl":"pkg:npm/lodash@4.17.21"},
+		{"type":"library","name":"express","version":"4.18.0","purl":"pkg:npm/express@4.18.0"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, exitCode := runCLI(path, "--json")
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", exitCode)
+	}
+
+	var out struct {
+		Warnings []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Field   string `json:"field"`
+		} `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if len(out.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %+v", out.Warnings)
+	}
+	if out.Warnings[0].Code != "version_mismatch" || out.Warnings[0].Field != "version" || !strings.Contains(out.Warnings[0].Message, "lodash") {
+		t.Errorf("unexpected warning %+v", out.Warnings[0])
+	}
+}
+
+func TestMergeMode(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.json")
+	web := filepath.Join(dir, "web.json")
+	files := map[string]string{
+		api: `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"components":[
+			{"type":"library","name":"lodash","version":"4.17.21","purl":"pkg:npm/lodash@4.17.21"},
+			{"type":"library","name":"express","version":"4.18.0","purl":"pkg:npm/express@4.18.0"}]}`,
+		web: `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"components":[
+			{"type":"library","name":"lodash","version":"4.17.21","purl":"pkg:npm/lodash@4.17.21"},
+			{"type":"library","name":"react","version":"18.2.0","purl":"pkg:npm/react@18.2.0"}]}`,
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, stderr, exitCode := runCLI(api, web, "--merge", "--json")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", exitCode, stderr)
+	}
+	var out struct {
+		Stats analysis.Stats `json:"stats"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	// lodash is in both files and counted once
+	if out.Stats.TotalComponents != 3 {
+		t.Errorf("expected 3 components, got %d", out.Stats.TotalComponents)
+	}
+	if len(out.Stats.Duplicates) != 0 {
+		t.Errorf("expected no duplicates, got %+v", out.Stats.Duplicates)
+	}
+}
+
+func TestStatsModeSyftDependencyDirection(t *testing.T) {
+	// in real Syft output, musl is a dependency of most other packages
+	stdout, stderr, exitCode := runCLI(testdataPath("real-syft-alpine.json"), "--json")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", exitCode, stderr)
+	}
+	var out struct {
+		Stats analysis.Stats `json:"stats"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if len(out.Stats.MostDependedOn) == 0 || !strings.Contains(out.Stats.MostDependedOn[0].ID, "/musl") {
+		t.Errorf("expected musl to be the most depended-on package, got %+v", out.Stats.MostDependedOn)
+	}
+}
+
+func TestFilesFrom(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.json")
+	web := filepath.Join(dir, "web.json")
+	files := map[string]string{
+		api: `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"components":[
+			{"type":"library","name":"express","version":"4.18.0","purl":"pkg:npm/express@4.18.0"}]}`,
+		web: `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"components":[
+			{"type":"library","name":"react","version":"18.2.0","purl":"pkg:npm/react@18.2.0"}]}`,
+		filepath.Join(dir, "list.txt"): "# services\n" + api + "\n\n" + web + "\n",
+		filepath.Join(dir, "empty.txt"): "# nothing\n",
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI("--files-from", filepath.Join(dir, "list.txt"), "--merge", "--json")
+		if exitCode != 0 {
+			t.Fatalf("expected exit code 0, got %d\nstderr: %s", exitCode, stderr)
+		}
+		var out struct {
+			Stats analysis.Stats `json:"stats"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+		if out.Stats.TotalComponents != 2 {
+			t.Errorf("expected a component from each listed file, got %d", out.Stats.TotalComponents)
+		}
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI("--files-from", filepath.Join(dir, "list.txt"), "--no-color")
+		if exitCode != cli.ExitDiff {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+		}
+		if !strings.Contains(stdout, "Added (1)") || !strings.Contains(stdout, "Removed (1)") {
+			t.Errorf("expected the two listed files to be diffed, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		_, stderr, exitCode := runCLI("--files-from", filepath.Join(dir, "empty.txt"), "--merge")
+		if exitCode != cli.ExitError || !strings.Contains(stderr, "lists no files") {
+			t.Errorf("expected exit %d with an empty-list error, got %d: %s", cli.ExitError, exitCode, stderr)
+		}
+	})
+}
+
+func TestParseBothWarningOrder(t *testing.T) {
+	// a large file finishes after a small one; warnings must still follow
+	// argument order
+	dir := t.TempDir()
+	writeMismatch := func(name string, extra int) string {
+		comps := []map[string]string{{"type": "library", "name": name, "version": "1.0.0", "purl": "pkg:npm/" + name + "@2.0.0"}}
+		for i := 0; i < extra; i++ {
+			n := fmt.Sprintf("pkg-%d", i)
+			comps = append(comps, map[string]string{"type": "library", "name": n, "version": "1.0.0", "purl": "pkg:npm/" + n + "@1.0.0"})
+		}
+		data, err := json.Marshal(map[string]any{"bomFormat": "CycloneDX", "specVersion": "1.4", "version": 1, "components": comps})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	large := writeMismatch("large", 20000)
+	small := writeMismatch("small", 0)
+
+	for _, order := range [][2]string{{large, small}, {small, large}} {
+		t.Run(filepath.Base(order[0]), func(t *testing.T) {
+			opts := cli.ParseOptions{}
+			parsed := parseBoth(order[0], order[1], &opts)
+			for i := range parsed {
+				if parsed[i].err != nil {
+					t.Fatalf("parse %s: %v", order[i], parsed[i].err)
+				}
+			}
+			if len(opts.Warnings) != 2 {
+				t.Fatalf("expected 2 warnings, got %+v", opts.Warnings)
+			}
+			for i, w := range opts.Warnings {
+				if w.File != order[i] {
+					t.Errorf("warning %d from %s, want %s", i, w.File, order[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateMode(t *testing.T) {
+	t.Run("dangling dependency", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-dangling-dependency.json"), "--validate")
+		if exitCode != 1 {
+			t.Errorf("expected exit code 1, got %d\nstderr: %s", exitCode, stderr)
+		}
+		if !strings.Contains(stdout, `references unknown bom-ref "pkg:npm/debug@2.6.9"`) {
+			t.Errorf("expected dangling reference error, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("clean file", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-nested.json"), "--validate", "--json")
+		if exitCode != 0 {
+			t.Errorf("expected exit code 0, got %d\nstderr: %s", exitCode, stderr)
+		}
+		var out struct {
+			Errors   []cli.ParseWarning `json:"errors"`
+			Warnings []cli.ParseWarning `json:"warnings"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("failed to parse JSON: %v\n%s", err, stdout)
+		}
+		if len(out.Errors) != 0 || len(out.Warnings) != 0 {
+			t.Errorf("expected no issues, got %+v", out)
+		}
+	})
+
+	t.Run("exact duplicate warns", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dup.json")
+		data := `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"components":[
+			{"type":"library","name":"zlib","version":"1.3.1","purl":"pkg:apk/alpine/zlib@1.3.1"},
+			{"type":"library","name":"zlib","version":"1.3.1","purl":"pkg:apk/alpine/zlib@1.3.1"}]}`
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		stdout, _, exitCode := runCLI(path, "--validate")
+		if exitCode != 0 {
+			t.Errorf("expected exit code 0 for warnings only, got %d", exitCode)
+		}
+		if !strings.Contains(stdout, "zlib@1.3.1 is listed 2 times") {
+			t.Errorf("expected exact duplicate warning, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("rejects two files", func(t *testing.T) {
+		_, _, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--validate")
+		if exitCode != 3 {
+			t.Errorf("expected exit code 3, got %d", exitCode)
+		}
+	})
+}
+
+func TestDropInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "placeholders.json")
+	data := `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"components":[
+		{"type":"library","name":"lodash","version":"4.17.21"},
+		{"type":"library","name":"NOASSERTION","version":"1.0"},
+		{"type":"library","name":"","version":"2.0"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantTotal int
+		wantWarn  string
+	}{
+		{"warns by default", nil, 3, "placeholder name"},
+		{"drops with flag", []string{"--drop-invalid"}, 1, "dropped 2 components"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, _, _ := runCLI(append([]string{path, "--json"}, tt.args...)...)
+			var out struct {
+				Stats struct {
+					TotalComponents int `json:"total_components"`
+				} `json:"stats"`
+				Warnings []struct {
+					Message string `json:"message"`
+				} `json:"warnings"`
+			}
+			if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+				t.Fatalf("failed to parse JSON: %v", err)
+			}
+			if out.Stats.TotalComponents != tt.wantTotal {
+				t.Errorf("expected %d components, got %d", tt.wantTotal, out.Stats.TotalComponents)
+			}
+			found := false
+			for _, w := range out.Warnings {
+				if strings.Contains(w.Message, tt.wantWarn) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected warning containing %q, got %+v", tt.wantWarn, out.Warnings)
+			}
+		})
+	}
+}
+
+func TestStatsModeJSONL(t *testing.T) {
+	for _, name := range []string{"syft-sample.json", "cyclonedx-before.json", "spdx-sample.json"} {
+		t.Run(name, func(t *testing.T) {
+			statsOut, _, _ := runCLI(testdataPath(name), "--json")
+			var stats struct {
+				Stats struct {
+					TotalComponents int `json:"total_components"`
+				} `json:"stats"`
+			}
+			if err := json.Unmarshal([]byte(statsOut), &stats); err != nil {
+				t.Fatalf("failed to parse stats JSON: %v", err)
+			}
+
+			stdout, _, exitCode := runCLI(testdataPath(name), "--format", "jsonl")
+			if exitCode != 0 {
+				t.Errorf("expected exit code 0, got %d", exitCode)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+			if len(lines) != stats.Stats.TotalComponents {
+				t.Fatalf("expected %d lines, got %d", stats.Stats.TotalComponents, len(lines))
+			}
+			for i, line := range lines {
+				var comp map[string]interface{}
+				if err := json.Unmarshal([]byte(line), &comp); err != nil {
+					t.Errorf("line %d is not valid JSON: %v", i+1, err)
+				}
+			}
+		})
+	}
+}
+
 func TestStatsModeWithDifferentFormats(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -216,6 +586,61 @@ func TestDiffModeText(t *testing.T) {
 	}
 }
 
+func TestDiffModeSummary(t *testing.T) {
+	stdout, _, exitCode := runCLI(
+		testdataPath("cyclonedx-before.json"),
+		testdataPath("cyclonedx-after.json"),
+		"--summary",
+	)
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1 (differences found), got %d", exitCode)
+	}
+
+	for _, want := range []string{"Added:   1", "Removed: 1", "Changed: 1"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in summary output, got:\n%s", want, stdout)
+		}
+	}
+	for _, name := range []string{"new-package", "old-package"} {
+		if strings.Contains(stdout, name) {
+			t.Errorf("expected component %q to be suppressed in summary output", name)
+		}
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	tests := []struct {
+		name string
+		env  []string
+		args []string
+	}{
+		{"flag", nil, []string{"--no-color"}},
+		{"env", []string{"NO_COLOR=1"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{
+				testdataPath("cyclonedx-before.json"),
+				testdataPath("cyclonedx-integrity-drift.json"),
+				"--policy", testdataPath("test-policy.json"),
+			}, tt.args...)
+			stdout, _, _ := runCLIWithEnv(tt.env, args...)
+
+			if strings.ContainsRune(stdout, 0x1b) {
+				t.Errorf("expected no ANSI escape bytes in output")
+			}
+			if strings.Contains(stdout, "⚠️") || strings.Contains(stdout, "📊") {
+				t.Errorf("expected emoji replaced by ASCII markers, got:\n%s", stdout)
+			}
+			if !strings.Contains(stdout, "! Integrity drift:") {
+				t.Errorf("expected ASCII integrity marker, got:\n%s", stdout)
+			}
+		})
+	}
+}
+
 func TestDiffModeJSON(t *testing.T) {
 	stdout, _, exitCode := runCLI(
 		testdataPath("cyclonedx-before.json"),
@@ -248,105 +673,685 @@ func TestDiffModeJSON(t *testing.T) {
 	}
 }
 
-func TestDiffNoDifferences(t *testing.T) {
-	stdout, _, exitCode := runCLI(
-		testdataPath("cyclonedx-before.json"),
+func TestDiffModeCycloneDX(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(
 		testdataPath("cyclonedx-before.json"),
+		testdataPath("cyclonedx-after.json"),
+		"--format", "cyclonedx",
 	)
 
-	if exitCode != 0 {
-		t.Errorf("expected exit code 0 (no differences), got %d", exitCode)
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d (stderr: %s)", exitCode, stderr)
 	}
-	if !strings.Contains(stdout, "No differences found") {
-		t.Errorf("expected 'No differences found' message")
+
+	var bom cdx.BOM
+	if err := json.Unmarshal([]byte(stdout), &bom); err != nil {
+		t.Fatalf("failed to parse CycloneDX: %v", err)
+	}
+	if bom.Components == nil || len(*bom.Components) != 2 {
+		t.Errorf("expected 2 components (1 added + 1 changed), got %v", bom.Components)
 	}
 }
 
-func TestFormatSARIF(t *testing.T) {
-	stdout, _, _ := runCLI(
+func TestDiffModeSPDXDiff(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(
 		testdataPath("cyclonedx-before.json"),
 		testdataPath("cyclonedx-after.json"),
-		"--format", "sarif",
+		"--format", "spdx-diff",
 	)
 
-	var sarif struct {
-		Schema  string `json:"$schema"`
-		Version string `json:"version"`
-		Runs    []struct {
-			Tool struct {
-				Driver struct {
-					Name string `json:"name"`
-				} `json:"driver"`
-			} `json:"tool"`
-		} `json:"runs"`
-	}
-	if err := json.Unmarshal([]byte(stdout), &sarif); err != nil {
-		t.Fatalf("failed to parse SARIF: %v", err)
-	}
-	if sarif.Version != "2.1.0" {
-		t.Errorf("expected SARIF version 2.1.0, got %s", sarif.Version)
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d (stderr: %s)", exitCode, stderr)
 	}
-	if len(sarif.Runs) != 1 {
-		t.Fatalf("expected 1 run, got %d", len(sarif.Runs))
+
+	doc, err := spdxjson.Read(strings.NewReader(stdout))
+	if err != nil {
+		t.Fatalf("failed to parse SPDX: %v", err)
 	}
-	if sarif.Runs[0].Tool.Driver.Name != "sbomlyze" {
-		t.Errorf("expected tool name 'sbomlyze', got %s", sarif.Runs[0].Tool.Driver.Name)
+	if len(doc.Packages) != 3 {
+		t.Errorf("expected 3 packages (1 added + 1 removed + 1 changed), got %d", len(doc.Packages))
 	}
 }
 
-func TestFormatJUnit(t *testing.T) {
-	stdout, _, _ := runCLI(
-		testdataPath("cyclonedx-before.json"),
-		testdataPath("cyclonedx-after.json"),
-		"--format", "junit",
-	)
-
-	if !strings.Contains(stdout, "<?xml") {
-		t.Errorf("expected XML header in JUnit output")
+func TestTiming(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		phases []string
+	}{
+		{"single file", []string{testdataPath("syft-sample.json"), "--json"}, []string{"parse", "analysis", "total"}},
+		{"diff", []string{testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--json"},
+			[]string{"parse", "diff", "analysis", "total"}},
 	}
 
-	// Parse XML
-	var junit struct {
-		XMLName xml.Name `xml:"testsuites"`
-		Name    string   `xml:"name,attr"`
-	}
-	if err := xml.Unmarshal([]byte(stdout), &junit); err != nil {
-		t.Fatalf("failed to parse JUnit XML: %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, _ := runCLI(append(tt.args, "--timing")...)
+			for _, phase := range tt.phases {
+				if !strings.Contains(stderr, "timing: "+phase) {
+					t.Errorf("expected %q phase on stderr, got:\n%s", phase, stderr)
+				}
+			}
+			if strings.Contains(stdout, "timing:") {
+				t.Error("timing lines must not go to stdout")
+			}
+			var out map[string]interface{}
+			if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+				t.Errorf("stdout is not valid JSON: %v", err)
+			}
+		})
 	}
-	if junit.Name != "sbomlyze" {
-		t.Errorf("expected testsuites name 'sbomlyze', got %s", junit.Name)
+
+	_, stderr, _ := runCLI(testdataPath("syft-sample.json"), "--json")
+	if strings.Contains(stderr, "timing:") {
+		t.Error("expected no timing output without --timing")
 	}
 }
 
-func TestFormatMarkdown(t *testing.T) {
-	stdout, _, _ := runCLI(
-		testdataPath("cyclonedx-before.json"),
-		testdataPath("cyclonedx-after.json"),
-		"--format", "markdown",
-	)
+func TestOnlyCategories(t *testing.T) {
+	// integrity drift on lodash plus one added component
+	data, err := os.ReadFile(testdataPath("cyclonedx-integrity-drift.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	doc["components"] = append(doc["components"].([]interface{}), map[string]interface{}{
+		"type": "library", "name": "brand-new", "version": "1.0.0", "purl": "pkg:npm/brand-new@1.0.0",
+	})
+	after := filepath.Join(t.TempDir(), "after.json")
+	data, _ = json.Marshal(doc)
+	if err := os.WriteFile(after, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := testdataPath("cyclonedx-before.json")
+
+	t.Run("text", func(t *testing.T) {
+		stdout, _, exitCode := runCLI(before, after, "--only", "integrity", "--no-color")
+		if exitCode != 1 {
+			t.Errorf("expected exit code 1 from the full diff, got %d", exitCode)
+		}
+		if !strings.Contains(stdout, "[INTEGRITY]") {
+			t.Errorf("expected integrity section, got:\n%s", stdout)
+		}
+		for _, hidden := range []string{"Added (", "Removed (", "brand-new", "SBOM Comparison"} {
+			if strings.Contains(stdout, hidden) {
+				t.Errorf("expected %q to be hidden, got:\n%s", hidden, stdout)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		stdout, _, _ := runCLI(before, after, "--only", "added", "--json")
+		var out struct {
+			Diff struct {
+				Added   []interface{} `json:"added"`
+				Changed []interface{} `json:"changed"`
+			} `json:"diff"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+		if len(out.Diff.Added) != 1 || len(out.Diff.Changed) != 0 {
+			t.Errorf("expected only 1 added, got %d added and %d changed", len(out.Diff.Added), len(out.Diff.Changed))
+		}
+	})
 
-	if !strings.Contains(stdout, "## 📦 SBOM Diff Report") {
-		t.Errorf("expected markdown header")
-	}
-	if !strings.Contains(stdout, "### Summary") {
-		t.Errorf("expected Summary section")
-	}
-	if !strings.Contains(stdout, "<details>") {
-		t.Errorf("expected collapsible details sections")
-	}
+	t.Run("unknown category", func(t *testing.T) {
+		_, stderr, exitCode := runCLI(before, after, "--only", "bogus")
+		if exitCode != 3 || !strings.Contains(stderr, "unknown category") {
+			t.Errorf("expected unknown category error, got exit %d, stderr: %s", exitCode, stderr)
+		}
+	})
 }
 
-func TestFormatPatch(t *testing.T) {
-	stdout, _, _ := runCLI(
-		testdataPath("cyclonedx-before.json"),
-		testdataPath("cyclonedx-after.json"),
-		"--format", "patch",
-	)
+func TestDiffDepsOnly(t *testing.T) {
+	dir := t.TempDir()
+	chain := filepath.Join(dir, "chain.json")
+	fanned := filepath.Join(dir, "fanned.json")
+	writeSyftChain(t, chain, [][2]string{{"a", "b"}, {"b", "c"}})
+	writeSyftChain(t, fanned, [][2]string{{"a", "b"}, {"a", "c"}})
+
+	t.Run("graph change", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI(chain, fanned, "--diff-deps-only", "--no-color", "--no-pager")
+		if exitCode != 1 {
+			t.Errorf("expected exit code 1, got %d\nstderr: %s", exitCode, stderr)
+		}
+		for _, want := range []string{"Added dependencies", "Removed dependencies"} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("expected %q in output, got:\n%s", want, stdout)
+			}
+		}
+	})
 
-	var patch []struct {
-		Op   string `json:"op"`
-		Path string `json:"path"`
-	}
+	t.Run("component changes hidden", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI(chain, testdataPath("syft-sample.json"), "--diff-deps-only", "--no-color", "--no-pager")
+		if exitCode != 1 {
+			t.Errorf("expected exit code 1, got %d\nstderr: %s", exitCode, stderr)
+		}
+		if !strings.Contains(stdout, "Removed dependencies") {
+			t.Errorf("expected dependency section, got:\n%s", stdout)
+		}
+		for _, hidden := range []string{"Added (", "Removed (", "Changed (", "SBOM Comparison"} {
+			if strings.Contains(stdout, hidden) {
+				t.Errorf("expected %q to be hidden, got:\n%s", hidden, stdout)
+			}
+		}
+	})
+
+	t.Run("no graph change exits 0", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--diff-deps-only", "--no-pager")
+		if exitCode != 0 {
+			t.Errorf("expected exit code 0, got %d\nstderr: %s", exitCode, stderr)
+		}
+		if !strings.Contains(stdout, "No differences found") {
+			t.Errorf("expected no differences, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("rejects --only", func(t *testing.T) {
+		_, stderr, exitCode := runCLI(chain, fanned, "--diff-deps-only", "--only", "added")
+		if exitCode != 3 {
+			t.Errorf("expected exit code 3, got %d", exitCode)
+		}
+		if !strings.Contains(stderr, "--diff-deps-only") {
+			t.Errorf("expected error about --diff-deps-only, got: %s", stderr)
+		}
+	})
+}
+
+func TestDiffNDJSONEvents(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--format", "ndjson-events")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+
+	seen := make(map[string]int)
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	for i, line := range lines {
+		var ev output.DiffEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i+1, err)
+		}
+		seen[ev.Type]++
+	}
+	for _, typ := range []string{output.EventAdded, output.EventRemoved, output.EventChanged, output.EventSummary} {
+		if seen[typ] == 0 {
+			t.Errorf("expected a %q event, got %v", typ, seen)
+		}
+	}
+	if !strings.Contains(lines[len(lines)-1], `"type":"summary"`) {
+		t.Errorf("expected the summary event last, got %s", lines[len(lines)-1])
+	}
+
+	again, _, _ := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--format", "ndjson-events")
+	if again != stdout {
+		t.Error("expected identical output across runs")
+	}
+}
+
+func TestDiffSummaryJSON(t *testing.T) {
+	pol := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(pol, []byte(`{"max_added": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// every component differs, so max_added is exceeded
+	stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("syft-sample.json"),
+		"--policy", pol, "--format", "summary-json")
+	if exitCode != cli.ExitPolicy {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitPolicy, exitCode, stderr)
+	}
+
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	for _, key := range []string{"added", "removed", "changed", "version_drift", "integrity_drift", "metadata_drift", "downgrades", "deep_deps"} {
+		var n int
+		if err := json.Unmarshal(out[key], &n); err != nil {
+			t.Errorf("%s: expected a number, got %s", key, out[key])
+		}
+		delete(out, key)
+	}
+	var violations output.ViolationCounts
+	if err := json.Unmarshal(out["violations"], &violations); err != nil || violations.Error != 1 {
+		t.Errorf("expected 1 error violation, got %s", out["violations"])
+	}
+	delete(out, "violations")
+	if len(out) != 0 {
+		t.Errorf("expected only counts, got extra keys %v", out)
+	}
+}
+
+func TestBadgeFormat(t *testing.T) {
+	before, after := testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json")
+	tests := []struct {
+		name     string
+		args     []string
+		wantExit int
+		want     output.Badge
+	}{
+		{"single file", []string{after}, cli.ExitOK,
+			output.Badge{SchemaVersion: 1, Label: "sbom", Message: "3 components | 100% licensed", Color: output.BadgeColorGood}},
+		{"clean diff", []string{before, before}, cli.ExitOK,
+			output.Badge{SchemaVersion: 1, Label: "sbom diff", Message: "clean", Color: output.BadgeColorGood}},
+		{"changed diff", []string{before, after}, cli.ExitDiff,
+			output.Badge{SchemaVersion: 1, Label: "sbom diff", Message: "+1 -1 ~1", Color: output.BadgeColorOK}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, exitCode := runCLI(append(tt.args, "--format", "badge")...)
+			if exitCode != tt.wantExit {
+				t.Fatalf("expected exit code %d, got %d\nstderr: %s", tt.wantExit, exitCode, stderr)
+			}
+			var got output.Badge
+			if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+				t.Fatalf("output is not valid JSON: %v\n%s", err, stdout)
+			}
+			if got != tt.want {
+				t.Errorf("badge = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListComponents(t *testing.T) {
+	sbomPath := testdataPath("cyclonedx-after.json")
+
+	t.Run("lists every component", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI(sbomPath, "--list")
+		if exitCode != cli.ExitOK {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitOK, exitCode, stderr)
+		}
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if len(lines) != 4 || !strings.HasPrefix(lines[0], "NAME") {
+			t.Fatalf("expected a header and 3 rows:\n%s", stdout)
+		}
+		for _, want := range []string{"express", "lodash", "new-package"} {
+			if !strings.Contains(stdout, "pkg:npm/"+want+"@") {
+				t.Errorf("expected %s in list:\n%s", want, stdout)
+			}
+		}
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"json", nil, []string{"express", "lodash", "new-package"}},
+		{"components alias", []string{"--components"}, []string{"express", "lodash", "new-package"}},
+		{"include", []string{"--include", "l*"}, []string{"lodash"}},
+		{"exclude", []string{"--exclude", "lodash", "--exclude", "pkg:npm/new-*"}, []string{"express"}},
+		{"nothing left", []string{"--include", "pkg:pypi"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{sbomPath, "--list", "--json"}, tt.args...)
+			stdout, stderr, exitCode := runCLI(args...)
+			if exitCode != cli.ExitOK {
+				t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitOK, exitCode, stderr)
+			}
+			var entries []output.ListEntry
+			if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+				t.Fatalf("output is not a JSON array: %v\n%s", err, stdout)
+			}
+			got := []string{}
+			for _, e := range entries {
+				got = append(got, e.Name)
+				if e.Type != "npm" || e.Version == "" {
+					t.Errorf("unexpected entry %+v", e)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("listed %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	errTests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"two files", []string{sbomPath, sbomPath, "--list"}, "--list takes one SBOM"},
+		{"filter without list", []string{sbomPath, "--include", "lodash"}, "need --list"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, exitCode := runCLI(tt.args...)
+			if exitCode != cli.ExitError || !strings.Contains(stderr, tt.wantErr) {
+				t.Errorf("expected exit %d with %q, got %d: %s", cli.ExitError, tt.wantErr, exitCode, stderr)
+			}
+		})
+	}
+}
+
+func TestIgnoreVersionChanges(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, version, hash, license string) string {
+		t.Helper()
+		doc := fmt.Sprintf(`{"bomFormat":"CycloneDX","specVersion":"1.5","components":[
+			{"type":"library","name":"lodash","version":%q,"purl":"pkg:npm/lodash@%s",
+			 "hashes":[{"alg":"SHA-256","content":%q}],"licenses":[{"license":{"id":%q}}]}]}`,
+			version, version, hash, license)
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base := write("base", "4.17.20", "aaa", "MIT")
+
+	tests := []struct {
+		name     string
+		after    string
+		wantExit int
+	}{
+		{"version bump only", write("bump", "4.17.21", "bbb", "MIT"), 0},
+		{"integrity drift", write("integrity", "4.17.20", "ccc", "MIT"), 1},
+		{"bump with license change", write("relicense", "4.17.21", "bbb", "GPL-3.0-only"), 1},
+		{"suspicious jump", write("jump", "99.0.0", "bbb", "MIT"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, exitCode := runCLI(base, tt.after, "--ignore-version-changes", "--no-color")
+			if exitCode != tt.wantExit {
+				t.Errorf("expected exit code %d, got %d\nstderr: %s", tt.wantExit, exitCode, stderr)
+			}
+			if !strings.Contains(stdout, "Changed (1)") {
+				t.Errorf("expected the change to still be shown, got:\n%s", stdout)
+			}
+		})
+	}
+
+	t.Run("without flag", func(t *testing.T) {
+		_, _, exitCode := runCLI(base, filepath.Join(dir, "bump.json"))
+		if exitCode != cli.ExitDiff {
+			t.Errorf("expected exit code %d, got %d", cli.ExitDiff, exitCode)
+		}
+	})
+}
+
+func TestIgnoreMetadataDrift(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, version, hash, license string) string {
+		t.Helper()
+		doc := fmt.Sprintf(`{"bomFormat":"CycloneDX","specVersion":"1.5","components":[
+			{"type":"library","name":"lodash","version":%q,"purl":"pkg:npm/lodash@%s",
+			 "hashes":[{"alg":"SHA-256","content":%q}],"licenses":[{"license":{"id":%q}}]}]}`,
+			version, version, hash, license)
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base := write("base", "4.17.20", "aaa", "MIT")
+	relicense := write("relicense", "4.17.20", "aaa", "Apache-2.0")
+
+	tests := []struct {
+		name     string
+		after    string
+		flags    []string
+		wantExit int
+	}{
+		{"metadata drift only", relicense, []string{"--ignore-metadata-drift"}, 0},
+		{"without flag", relicense, nil, 1},
+		{"integrity drift", write("integrity", "4.17.20", "ccc", "MIT"), []string{"--ignore-metadata-drift"}, 1},
+		{"version bump", write("bump", "4.17.21", "bbb", "MIT"), []string{"--ignore-metadata-drift"}, 1},
+		{"with ignore-version-changes", filepath.Join(dir, "bump.json"), []string{"--ignore-metadata-drift", "--ignore-version-changes"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{base, tt.after, "--no-color"}, tt.flags...)
+			stdout, stderr, exitCode := runCLI(args...)
+			if exitCode != tt.wantExit {
+				t.Errorf("expected exit code %d, got %d\nstderr: %s", tt.wantExit, exitCode, stderr)
+			}
+			if !strings.Contains(stdout, "Changed (1)") {
+				t.Errorf("expected the change to still be shown, got:\n%s", stdout)
+			}
+		})
+	}
+}
+
+func TestMaxItems(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.json")
+	after := filepath.Join(dir, "after.json")
+	writeSyftChain(t, before, [][2]string{{"a", "b"}})
+	writeSyftChain(t, after, [][2]string{{"a", "b"}, {"c", "d"}, {"e", "f"}})
+
+	stdout, stderr, exitCode := runCLI(before, after, "--max-items", "1", "--no-color")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+	for _, want := range []string{"+ Added (4):", "  ... and 3 more"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, stderr, exitCode := runCLI(before, after, "--max-items", "none")
+		if exitCode != cli.ExitError {
+			t.Errorf("expected exit code %d, got %d", cli.ExitError, exitCode)
+		}
+		if !strings.Contains(stderr, "--max-items") {
+			t.Errorf("expected error about the flag, got %q", stderr)
+		}
+	})
+}
+
+func TestDeepDepThreshold(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.json")
+	after := filepath.Join(dir, "after.json")
+	writeSyftChain(t, before, [][2]string{{"a", "b"}})
+	writeSyftChain(t, after, [][2]string{{"a", "b"}, {"b", "c"}})
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantDeep int
+	}{
+		{"default threshold", nil, 0},
+		{"threshold 2", []string{"--deep-dep-threshold", "2"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{before, after, "--json"}, tt.args...)
+			stdout, stderr, _ := runCLI(args...)
+			var out struct {
+				Diff analysis.DiffResult `json:"diff"`
+			}
+			if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+				t.Fatalf("failed to parse JSON: %v\nstderr: %s", err, stderr)
+			}
+			if out.Diff.Dependencies == nil || out.Diff.Dependencies.DepthSummary == nil {
+				t.Fatalf("expected a depth summary, got %+v", out.Diff.Dependencies)
+			}
+			if ds := out.Diff.Dependencies.DepthSummary; ds.Depth2 != 1 || ds.Deep != tt.wantDeep {
+				t.Errorf("expected depth-2 dep with deep=%d, got %+v", tt.wantDeep, ds)
+			}
+		})
+	}
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, stderr, exitCode := runCLI(before, after, "--deep-dep-threshold", "0")
+		if exitCode != cli.ExitError {
+			t.Errorf("expected exit code %d, got %d", cli.ExitError, exitCode)
+		}
+		if !strings.Contains(stderr, "--deep-dep-threshold") {
+			t.Errorf("expected error about the flag, got %q", stderr)
+		}
+	})
+}
+
+func TestCPEList(t *testing.T) {
+	stdout, _, exitCode := runCLI(
+		testdataPath("spdx-sample.json"),
+		testdataPath("spdx-with-cpes.json"),
+		"--cpe-list",
+	)
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "cpe:") {
+			t.Errorf("expected one CPE per line, got %q", line)
+		}
+		if seen[line] {
+			t.Errorf("duplicate CPE %q", line)
+		}
+		seen[line] = true
+	}
+	if !seen["cpe:haxx:curl"] {
+		t.Errorf("expected cpe:haxx:curl from the added component, got:\n%s", stdout)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	before, after := testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json")
+
+	first, _, exitCode := runCLI(before, after, "--fingerprint")
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	first = strings.TrimSpace(first)
+	if len(first) != 64 {
+		t.Fatalf("expected a sha256 hex digest, got %q", first)
+	}
+
+	second, _, _ := runCLI(before, after, "--fingerprint")
+	if strings.TrimSpace(second) != first {
+		t.Errorf("expected the same fingerprint on a repeat run, got %q and %q", first, second)
+	}
+
+	reversed, _, _ := runCLI(after, before, "--fingerprint")
+	if strings.TrimSpace(reversed) == first {
+		t.Errorf("expected the reversed diff to have a different fingerprint")
+	}
+
+	stdout, _, _ := runCLI(before, after, "--json")
+	var out struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if out.Fingerprint != first {
+		t.Errorf("expected JSON fingerprint %q, got %q", first, out.Fingerprint)
+	}
+}
+
+func TestDiffNoDifferences(t *testing.T) {
+	stdout, _, exitCode := runCLI(
+		testdataPath("cyclonedx-before.json"),
+		testdataPath("cyclonedx-before.json"),
+	)
+
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0 (no differences), got %d", exitCode)
+	}
+	if !strings.Contains(stdout, "No differences found") {
+		t.Errorf("expected 'No differences found' message")
+	}
+}
+
+func TestFormatSARIF(t *testing.T) {
+	stdout, _, _ := runCLI(
+		testdataPath("cyclonedx-before.json"),
+		testdataPath("cyclonedx-after.json"),
+		"--format", "sarif",
+	)
+
+	var sarif struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name string `json:"name"`
+				} `json:"driver"`
+			} `json:"tool"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &sarif); err != nil {
+		t.Fatalf("failed to parse SARIF: %v", err)
+	}
+	if sarif.Version != "2.1.0" {
+		t.Errorf("expected SARIF version 2.1.0, got %s", sarif.Version)
+	}
+	if len(sarif.Runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(sarif.Runs))
+	}
+	if sarif.Runs[0].Tool.Driver.Name != "sbomlyze" {
+		t.Errorf("expected tool name 'sbomlyze', got %s", sarif.Runs[0].Tool.Driver.Name)
+	}
+}
+
+func TestFormatJUnit(t *testing.T) {
+	stdout, _, _ := runCLI(
+		testdataPath("cyclonedx-before.json"),
+		testdataPath("cyclonedx-after.json"),
+		"--format", "junit",
+	)
+
+	if !strings.Contains(stdout, "<?xml") {
+		t.Errorf("expected XML header in JUnit output")
+	}
+
+	// Parse XML
+	var junit struct {
+		XMLName xml.Name `xml:"testsuites"`
+		Name    string   `xml:"name,attr"`
+	}
+	if err := xml.Unmarshal([]byte(stdout), &junit); err != nil {
+		t.Fatalf("failed to parse JUnit XML: %v", err)
+	}
+	if junit.Name != "sbomlyze" {
+		t.Errorf("expected testsuites name 'sbomlyze', got %s", junit.Name)
+	}
+}
+
+func TestFormatMarkdown(t *testing.T) {
+	stdout, _, _ := runCLI(
+		testdataPath("cyclonedx-before.json"),
+		testdataPath("cyclonedx-after.json"),
+		"--format", "markdown",
+	)
+
+	if !strings.Contains(stdout, "## 📦 SBOM Diff Report") {
+		t.Errorf("expected markdown header")
+	}
+	if !strings.Contains(stdout, "### Summary") {
+		t.Errorf("expected Summary section")
+	}
+	if !strings.Contains(stdout, "<details>") {
+		t.Errorf("expected collapsible details sections")
+	}
+}
+
+func TestFormatPatch(t *testing.T) {
+	stdout, _, _ := runCLI(
+		testdataPath("cyclonedx-before.json"),
+		testdataPath("cyclonedx-after.json"),
+		"--format", "patch",
+	)
+
+	var patch []struct {
+		Op   string `json:"op"`
+		Path string `json:"path"`
+	}
 	if err := json.Unmarshal([]byte(stdout), &patch); err != nil {
 		t.Fatalf("failed to parse JSON Patch: %v", err)
 	}
@@ -391,8 +1396,8 @@ func TestPolicyViolation(t *testing.T) {
 		"--policy", testdataPath("strict-test-policy.json"),
 	)
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1, got %d", exitCode)
+	if exitCode != 2 {
+		t.Errorf("expected exit code 2, got %d", exitCode)
 	}
 	if !strings.Contains(stdout, "Policy Errors") {
 		t.Errorf("expected 'Policy Errors' in output, got: %s", stdout)
@@ -410,8 +1415,8 @@ func TestPolicyWithJSON(t *testing.T) {
 		"--json",
 	)
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1, got %d", exitCode)
+	if exitCode != 2 {
+		t.Errorf("expected exit code 2, got %d", exitCode)
 	}
 
 	var result struct {
@@ -424,50 +1429,556 @@ func TestPolicyWithJSON(t *testing.T) {
 	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
 		t.Fatalf("failed to parse JSON: %v\nOutput was: %s", err, stdout)
 	}
-	if len(result.Violations) == 0 {
-		t.Errorf("expected policy violations in output, got: %s", stdout)
+	if len(result.Violations) == 0 {
+		t.Errorf("expected policy violations in output, got: %s", stdout)
+	}
+	if result.Violations[0].Rule != "deny_licenses" {
+		t.Errorf("expected deny_licenses rule, got %s", result.Violations[0].Rule)
+	}
+}
+
+func TestPolicyDenyNewSuppliers(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, components string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		doc := `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[` + components + `]}`
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	lodash := `{"type":"library","name":"lodash","version":"4.17.21","purl":"pkg:npm/lodash@4.17.21","supplier":{"name":"OpenJS Foundation"}}`
+	before := write("before.json", lodash)
+	pol := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(pol, []byte(`{"deny_new_suppliers": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		added    string
+		wantExit int
+	}{
+		{"known supplier", `{"type":"library","name":"express","version":"4.18.2","purl":"pkg:npm/express@4.18.2","supplier":{"name":"OpenJS Foundation"}}`, cli.ExitDiff},
+		{"new supplier", `{"type":"library","name":"evil","version":"1.0.0","purl":"pkg:npm/evil@1.0.0","supplier":{"name":"Unknown Corp"}}`, cli.ExitPolicy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := write(strings.ReplaceAll(tt.name, " ", "-")+".json", lodash+","+tt.added)
+			stdout, stderr, exitCode := runCLI(before, after, "--policy", pol, "--no-color")
+			if exitCode != tt.wantExit {
+				t.Errorf("expected exit code %d, got %d\nstdout: %s\nstderr: %s", tt.wantExit, exitCode, stdout, stderr)
+			}
+			if got := strings.Contains(stdout, `evil: new supplier "Unknown Corp"`); got != (tt.wantExit == cli.ExitPolicy) {
+				t.Errorf("unexpected violation output:\n%s", stdout)
+			}
+		})
+	}
+}
+
+func TestMultiplePolicyFiles(t *testing.T) {
+	team := filepath.Join(t.TempDir(), "team-policy.json")
+	if err := os.WriteFile(team, []byte(`{"deny_licenses": ["Apache-2.0"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, _ := runCLI(
+		testdataPath("cyclonedx-before.json"),
+		testdataPath("cyclonedx-after.json"),
+		"--policy", testdataPath("test-policy.json"),
+		"--policy", team,
+		"--json",
+	)
+
+	var result struct {
+		Violations []struct {
+			Rule    string `json:"rule"`
+			Message string `json:"message"`
+		} `json:"violations"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "deny_licenses" ||
+		!strings.Contains(result.Violations[0].Message, "Apache-2.0") {
+		t.Errorf("expected deny_licenses violation from the second policy, got %+v", result.Violations)
+	}
+}
+
+func TestStrictModeWithInvalidFile(t *testing.T) {
+	_, stderr, exitCode := runCLI(
+		testdataPath("invalid.json"),
+		"--strict",
+	)
+
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3 for invalid file in strict mode, got %d", exitCode)
+	}
+	if !strings.Contains(stderr, "err") {
+		t.Errorf("expected error message in stderr")
+	}
+}
+
+func TestTolerantModeWithInvalidFile(t *testing.T) {
+	stdout, _, exitCode := runCLI(
+		testdataPath("invalid.json"),
+		"--tolerant",
+	)
+
+	if exitCode != 0 {
+		t.Errorf("expected exit code 0 in tolerant mode, got %d", exitCode)
+	}
+	if !strings.Contains(stdout, "Total Components: 0") {
+		t.Errorf("expected 0 components for invalid file in tolerant mode")
+	}
+}
+
+func TestTolerantModeShowsWarnings(t *testing.T) {
+	stdout, _, _ := runCLI(
+		testdataPath("invalid.json"),
+		"--tolerant",
+	)
+
+	if !strings.Contains(stdout, "Parse Warnings") {
+		t.Errorf("expected parse warnings in tolerant mode output")
+	}
+}
+
+func TestTolerantModeWarningCodes(t *testing.T) {
+	// recognized as CycloneDX, but components has the wrong type
+	broken := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(broken, []byte(`{"bomFormat":"CycloneDX","specVersion":"1.4","components":"oops"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode string
+	}{
+		{"unrecognized document", testdataPath("invalid.json"), cli.WarnUnknownFormat},
+		{"malformed document", broken, cli.WarnParseError},
+		{"dropped dependency edge", testdataPath("cyclonedx-dangling-dependency.json"), "dangling_dependency"},
+		{"unreadable artifacts", testdataPath("syft-count-mismatch.json"), "component_count_mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, _, exitCode := runCLI(tt.path, "--tolerant", "--json")
+			if exitCode != 0 {
+				t.Fatalf("expected exit code 0, got %d", exitCode)
+			}
+			var out struct {
+				Warnings []cli.ParseWarning `json:"warnings"`
+			}
+			if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+				t.Fatalf("failed to parse JSON: %v", err)
+			}
+			if len(out.Warnings) != 1 || out.Warnings[0].Code != tt.wantCode {
+				t.Errorf("expected one %s warning, got %+v", tt.wantCode, out.Warnings)
+			}
+		})
+	}
+}
+
+func TestConflictingDuplicateWarning(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.json")
+	after := filepath.Join(dir, "after.json")
+	docs := map[string]string{
+		before: `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[
+			{"type":"library","name":"lodash","version":"4.17.21","purl":"pkg:npm/lodash@4.17.21","licenses":[{"license":{"id":"MIT"}}]},
+			{"type":"library","name":"lodash","version":"4.17.21","purl":"pkg:npm/lodash@4.17.21","licenses":[{"license":{"id":"GPL-3.0-only"}}]}]}`,
+		after: `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[
+			{"type":"library","name":"lodash","version":"4.17.21","purl":"pkg:npm/lodash@4.17.21","licenses":[{"license":{"id":"MIT"}}]}]}`,
+	}
+	for path, doc := range docs {
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, _, exitCode := runCLI(before, after, "--json")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", exitCode)
+	}
+	var out struct {
+		Warnings []cli.ParseWarning `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if len(out.Warnings) != 1 {
+		t.Fatalf("expected one warning, got %+v", out.Warnings)
+	}
+	w := out.Warnings[0]
+	if w.Code != cli.WarnConflictingDuplicate || w.File != before {
+		t.Errorf("expected %s warning for %s, got %+v", cli.WarnConflictingDuplicate, before, w)
+	}
+	if !strings.Contains(w.Message, "GPL-3.0-only") {
+		t.Errorf("expected the dropped license in the message, got %q", w.Message)
+	}
+}
+
+func TestMixedFormatsWarning(t *testing.T) {
+	tests := []struct {
+		name  string
+		after string
+		want  bool
+	}{
+		{"cyclonedx vs syft", "syft-sample.json", true},
+		{"cyclonedx vs spdx", "spdx-sample.json", true},
+		{"same format", "cyclonedx-after.json", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, _, _ := runCLI(testdataPath("cyclonedx-before.json"), testdataPath(tt.after), "--json")
+			var out struct {
+				Warnings []cli.ParseWarning `json:"warnings"`
+			}
+			if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+				t.Fatalf("failed to parse JSON: %v", err)
+			}
+			got := slices.ContainsFunc(out.Warnings, func(w cli.ParseWarning) bool {
+				return w.Code == cli.WarnMixedFormats && w.File == testdataPath(tt.after)
+			})
+			if got != tt.want {
+				t.Errorf("mixed_formats warning = %v, want %v (warnings: %+v)", got, tt.want, out.Warnings)
+			}
+		})
+	}
+}
+
+func TestExplainIdentityBasis(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, components string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		doc := `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[` + components + `]}`
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	lodash := `{"type":"library","name":"lodash","version":"4.17.21","purl":"pkg:npm/lodash@4.17.21"}`
+	internal := `{"type":"library","name":"internal-tool","version":"1.0.0"}`
+	before := write("before.json", lodash)
+	after := write("after.json", lodash+","+internal)
+	want := map[string]string{"lodash": "purl", "internal-tool": "name"}
+
+	t.Run("stats json", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI(after, "--json", "--explain")
+		if exitCode != cli.ExitOK {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitOK, exitCode, stderr)
+		}
+		var out struct {
+			Identities []output.IdentityNote `json:"identities"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+		if len(out.Identities) != len(want) {
+			t.Fatalf("expected %d identities, got %+v", len(want), out.Identities)
+		}
+		for _, n := range out.Identities {
+			if n.Basis != want[n.Name] {
+				t.Errorf("%s: basis = %q, want %q", n.Name, n.Basis, want[n.Name])
+			}
+		}
+	})
+
+	t.Run("diff json", func(t *testing.T) {
+		stdout, _, _ := runCLI(before, after, "--json", "--explain")
+		var out struct {
+			Diff struct {
+				Added []sbom.Component `json:"added"`
+			} `json:"diff"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+		if len(out.Diff.Added) != 1 || out.Diff.Added[0].IDBasis != "name" {
+			t.Errorf("expected internal-tool added with basis name, got %+v", out.Diff.Added)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		stdout, _, _ := runCLI(after, "--explain", "--no-color")
+		for _, line := range []string{"id: pkg:npm/lodash (by purl)", "id: internal-tool (by name)"} {
+			if !strings.Contains(stdout, line) {
+				t.Errorf("expected %q in output:\n%s", line, stdout)
+			}
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		stdout, _, _ := runCLI(after, "--json")
+		if strings.Contains(stdout, "identities") {
+			t.Errorf("expected no identities without --explain:\n%s", stdout)
+		}
+	})
+}
+
+func TestStateSnapshots(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "state.json")
+
+	stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), "--state", store, "--label", "build-1")
+	if exitCode != cli.ExitOK {
+		t.Fatalf("first run: expected exit code %d, got %d\nstderr: %s", cli.ExitOK, exitCode, stderr)
+	}
+	if stdout != "" || !strings.Contains(stderr, `recorded "build-1"`) {
+		t.Errorf("first run: unexpected output\nstdout: %s\nstderr: %s", stdout, stderr)
+	}
+
+	stdout, stderr, exitCode = runCLI(testdataPath("cyclonedx-after.json"), "--state", store, "--label", "build-2", "--json")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("second run: expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+	var got struct {
+		Diff analysis.DiffResult `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	want, _, _ := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--json")
+	var wantOut struct {
+		Diff analysis.DiffResult `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(want), &wantOut); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if len(got.Diff.Added) != len(wantOut.Diff.Added) || len(got.Diff.Removed) != len(wantOut.Diff.Removed) || len(got.Diff.Changed) != len(wantOut.Diff.Changed) {
+		t.Errorf("state diff = +%d -%d ~%d, want +%d -%d ~%d",
+			len(got.Diff.Added), len(got.Diff.Removed), len(got.Diff.Changed),
+			len(wantOut.Diff.Added), len(wantOut.Diff.Removed), len(wantOut.Diff.Changed))
+	}
+
+	s, err := state.Load(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Snapshots) != 2 || s.Snapshots[0].Label != "build-1" || s.Snapshots[1].Label != "build-2" {
+		t.Fatalf("expected snapshots build-1, build-2, got %d", len(s.Snapshots))
+	}
+	if s.Snapshots[1].Source != testdataPath("cyclonedx-after.json") || len(s.Snapshots[1].Components) == 0 {
+		t.Errorf("unexpected latest snapshot: source %q, %d components", s.Snapshots[1].Source, len(s.Snapshots[1].Components))
+	}
+
+	// a third run with the same SBOM has nothing new
+	_, _, exitCode = runCLI(testdataPath("cyclonedx-after.json"), "--state", store)
+	if exitCode != cli.ExitOK {
+		t.Errorf("third run: expected exit code %d, got %d", cli.ExitOK, exitCode)
+	}
+}
+
+func TestStateRequiresOneFile(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "state.json")
+	_, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--state", store)
+	if exitCode != cli.ExitError || !strings.Contains(stderr, "--state takes exactly one file") {
+		t.Errorf("expected usage error, got exit %d: %s", exitCode, stderr)
+	}
+}
+
+func TestStrictLicenses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sbom.json")
+	doc := `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[
+		{"type":"library","name":"lodash","version":"4.17.21","licenses":[{"license":{"id":"MIT"}}]},
+		{"type":"library","name":"left-pad","version":"1.3.0","licenses":[{"license":{"id":"Apache-2.O"}}]}]}`
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const msg = `left-pad@1.3.0: unknown SPDX license ID "Apache-2.O"`
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantExit int
+	}{
+		{"warning by default", []string{path, "--no-color"}, cli.ExitOK},
+		{"stats strict", []string{path, "--json", "--strict-licenses"}, cli.ExitError},
+		{"diff strict", []string{testdataPath("cyclonedx-before.json"), path, "--strict-licenses"}, cli.ExitError},
+		{"validate strict", []string{path, "--validate", "--strict-licenses"}, cli.ExitDiff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, exitCode := runCLI(tt.args...)
+			if exitCode != tt.wantExit {
+				t.Fatalf("expected exit code %d, got %d\nstdout: %s\nstderr: %s", tt.wantExit, exitCode, stdout, stderr)
+			}
+			if !strings.Contains(stdout+stderr, msg) {
+				t.Errorf("expected %q in output\nstdout: %s\nstderr: %s", msg, stdout, stderr)
+			}
+		})
+	}
+}
+
+func TestSortRisk(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-integrity-drift.json"), "--sort-risk", "--no-color")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "[INTEGRITY] [risk 50]") {
+		t.Errorf("expected integrity drift scored 50:\n%s", stdout)
+	}
+
+	stdout, _, _ = runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-integrity-drift.json"), "--no-color")
+	if strings.Contains(stdout, "[risk ") {
+		t.Errorf("expected no risk scores without --sort-risk:\n%s", stdout)
+	}
+}
+
+func TestOnlyKeepsRiskScores(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-integrity-drift.json"), "--format", "json", "--only", "changed")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+	var out struct {
+		Diff analysis.DiffResult `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if scores := out.Diff.RiskScores; len(scores) == 0 || scores[0].Score != 50 {
+		t.Errorf("expected the changed component's risk score, got %+v", scores)
+	}
+}
+
+func TestStatsDelta(t *testing.T) {
+	before, after := testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json")
+	stdout, stderr, exitCode := runCLI(before, after, "--stats-delta", "--json")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+	var out struct {
+		StatsDelta *analysis.StatsDelta `json:"stats_delta"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if out.StatsDelta == nil {
+		t.Fatal("expected stats_delta in JSON output")
+	}
+	d := out.StatsDelta
+	if d.TotalComponents != (analysis.CountDelta{Before: 3, After: 3}) {
+		t.Errorf("total_components = %+v", d.TotalComponents)
+	}
+	if d.LicenseCategories.Permissive != (analysis.CountDelta{Before: 2, After: 3, Change: 1}) {
+		t.Errorf("permissive = %+v", d.LicenseCategories.Permissive)
+	}
+	if d.LicenseCategories.Unknown != (analysis.CountDelta{Before: 1, After: 0, Change: -1}) {
+		t.Errorf("unknown = %+v", d.LicenseCategories.Unknown)
 	}
-	if result.Violations[0].Rule != "deny_licenses" {
-		t.Errorf("expected deny_licenses rule, got %s", result.Violations[0].Rule)
+	if c := d.Coverage.License; c.After != 100 || c.Change < 33.3 || c.Change > 33.4 {
+		t.Errorf("license coverage = %+v", c)
 	}
-}
-
-func TestStrictModeWithInvalidFile(t *testing.T) {
-	_, stderr, exitCode := runCLI(
-		testdataPath("invalid.json"),
-		"--strict",
-	)
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1 for invalid file in strict mode, got %d", exitCode)
+	stdout, _, _ = runCLI(before, after, "--stats-delta", "--no-color")
+	if !strings.Contains(stdout, "Stats Delta:") || !strings.Contains(stdout, "+33.3 pp") {
+		t.Errorf("expected Stats Delta section in text output:\n%s", stdout)
 	}
-	if !strings.Contains(stderr, "err") {
-		t.Errorf("expected error message in stderr")
+
+	stdout, _, _ = runCLI(before, after, "--json")
+	if strings.Contains(stdout, "stats_delta") {
+		t.Error("expected no stats_delta without --stats-delta")
 	}
 }
 
-func TestTolerantModeWithInvalidFile(t *testing.T) {
-	stdout, _, exitCode := runCLI(
-		testdataPath("invalid.json"),
-		"--tolerant",
-	)
-
-	if exitCode != 0 {
-		t.Errorf("expected exit code 0 in tolerant mode, got %d", exitCode)
+func TestGitRevisions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
 	}
-	if !strings.Contains(stdout, "Total Components: 0") {
-		t.Errorf("expected 0 components for invalid file in tolerant mode")
+	commit := func(name, msg string) {
+		t.Helper()
+		data, err := os.ReadFile(testdataPath(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(repo, "sboms"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, "sboms", "app.json"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", ".")
+		git("commit", "-q", "-m", msg)
+	}
+	git("init", "-q")
+	commit("cyclonedx-before.json", "first")
+	commit("cyclonedx-after.json", "second")
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		cmd := exec.Command(binaryPath, args...)
+		cmd.Dir = repo
+		var outBuf, errBuf bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &outBuf, &errBuf
+		exitCode := 0
+		if err := cmd.Run(); err != nil {
+			exitErr, ok := err.(*exec.ExitError)
+			if !ok {
+				t.Fatal(err)
+			}
+			exitCode = exitErr.ExitCode()
+		}
+		return outBuf.String(), errBuf.String(), exitCode
 	}
-}
 
-func TestTolerantModeShowsWarnings(t *testing.T) {
-	stdout, _, _ := runCLI(
-		testdataPath("invalid.json"),
-		"--tolerant",
-	)
+	t.Run("diffs the revisions", func(t *testing.T) {
+		stdout, stderr, exitCode := run("--git", "HEAD~1", "HEAD", "sboms/app.json", "--json")
+		if exitCode != cli.ExitDiff {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+		}
+		want, _, _ := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--json")
+		var got, wantOut struct {
+			Diff analysis.DiffResult `json:"diff"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+		if err := json.Unmarshal([]byte(want), &wantOut); err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Diff.Added) != len(wantOut.Diff.Added) || len(got.Diff.Removed) != len(wantOut.Diff.Removed) || len(got.Diff.Changed) != len(wantOut.Diff.Changed) {
+			t.Errorf("git diff = +%d -%d ~%d, want +%d -%d ~%d",
+				len(got.Diff.Added), len(got.Diff.Removed), len(got.Diff.Changed),
+				len(wantOut.Diff.Added), len(wantOut.Diff.Removed), len(wantOut.Diff.Changed))
+		}
+	})
 
-	if !strings.Contains(stdout, "Parse Warnings") {
-		t.Errorf("expected parse warnings in tolerant mode output")
+	t.Run("text names the revisions", func(t *testing.T) {
+		stdout, _, _ := run("--git", "HEAD~1", "HEAD", "sboms/app.json", "--no-color")
+		if !strings.Contains(stdout, "app.json@HEAD~1") || !strings.Contains(stdout, "app.json@HEAD") {
+			t.Errorf("expected revision file names in overview:\n%s", stdout)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"missing path", []string{"--git", "HEAD~1", "HEAD", "sboms/missing.json"}, "git show HEAD~1:sboms/missing.json"},
+		{"unknown revision", []string{"--git", "nope", "HEAD", "sboms/app.json"}, "git show nope:sboms/app.json"},
+		{"too few values", []string{"--git", "HEAD", "sboms/app.json"}, "--git takes <rev1> <rev2> <path>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, exitCode := run(tt.args...)
+			if exitCode != cli.ExitError || !strings.Contains(stderr, tt.wantErr) {
+				t.Errorf("expected exit %d with %q, got %d: %s", cli.ExitError, tt.wantErr, exitCode, stderr)
+			}
+		})
 	}
 }
 
@@ -523,8 +2034,8 @@ func TestVersionDriftDetection(t *testing.T) {
 func TestNonExistentFile(t *testing.T) {
 	_, stderr, exitCode := runCLI("nonexistent.json", "--strict")
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1 for nonexistent file, got %d", exitCode)
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3 for nonexistent file, got %d", exitCode)
 	}
 	if !strings.Contains(stderr, "err") {
 		t.Errorf("expected error message for nonexistent file, got stderr: %s", stderr)
@@ -538,8 +2049,8 @@ func TestInvalidPolicyFile(t *testing.T) {
 		"--policy", testdataPath("malformed.json"),
 	)
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1 for invalid policy, got %d", exitCode)
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3 for invalid policy, got %d", exitCode)
 	}
 	if !strings.Contains(stderr, "err") {
 		t.Errorf("expected error message for invalid policy, got stderr: %s", stderr)
@@ -553,14 +2064,225 @@ func TestNonExistentPolicyFile(t *testing.T) {
 		"--policy", "nonexistent-policy.json",
 	)
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1, got %d", exitCode)
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", exitCode)
 	}
 	if !strings.Contains(stderr, "err") {
 		t.Errorf("expected error message for nonexistent policy file")
 	}
 }
 
+func writeSyftChain(t *testing.T, path string, edges [][2]string) {
+	t.Helper()
+	type artifact struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Type    string `json:"type"`
+		PURL    string `json:"purl"`
+	}
+	type relationship struct {
+		Parent string `json:"parent"`
+		Child  string `json:"child"`
+		Type   string `json:"type"`
+	}
+	doc := struct {
+		Artifacts     []artifact     `json:"artifacts"`
+		Relationships []relationship `json:"artifactRelationships"`
+		Source        map[string]any `json:"source"`
+	}{Source: map[string]any{"type": "directory"}}
+	seen := map[string]bool{}
+	for _, e := range edges {
+		for _, name := range e {
+			if !seen[name] {
+				seen[name] = true
+				doc.Artifacts = append(doc.Artifacts, artifact{
+					ID: name, Name: name, Version: "1.0.0", Type: "npm",
+					PURL: "pkg:npm/" + name + "@1.0.0",
+				})
+			}
+		}
+		// e[0] depends on e[1]: e[1] is a dependency of e[0]
+		doc.Relationships = append(doc.Relationships, relationship{Parent: e[1], Child: e[0], Type: "dependency-of"})
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	before := testdataPath("cyclonedx-before.json")
+	after := testdataPath("cyclonedx-after.json")
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantExit int
+	}{
+		{"no differences", []string{before, before}, cli.ExitOK},
+		{"differences", []string{before, after}, cli.ExitDiff},
+		{"policy errors", []string{before, after, "--policy", testdataPath("strict-test-policy.json")}, cli.ExitPolicy},
+		{"parse error", []string{testdataPath("invalid.json"), after, "--strict"}, cli.ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, exitCode := runCLI(append(tt.args, "--no-pager")...)
+			if exitCode != tt.wantExit {
+				t.Errorf("expected exit code %d, got %d\nstderr: %s", tt.wantExit, exitCode, stderr)
+			}
+		})
+	}
+}
+
+func TestFailOn(t *testing.T) {
+	dir := t.TempDir()
+	shallow := filepath.Join(dir, "shallow.json")
+	deep := filepath.Join(dir, "deep.json")
+	writeSyftChain(t, shallow, [][2]string{{"a", "b"}})
+	writeSyftChain(t, deep, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}})
+
+	before := testdataPath("cyclonedx-before.json")
+	after := testdataPath("cyclonedx-after.json")
+	drift := testdataPath("cyclonedx-integrity-drift.json")
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantExit int
+		wantRule string
+	}{
+		{"integrity drift triggers", []string{before, drift, "--fail-on", "integrity-drift"}, 2, "fail_on:integrity-drift"},
+		{"integrity drift clean", []string{before, after, "--fail-on", "integrity-drift"}, 0, ""},
+		{"added over threshold", []string{before, after, "--fail-on", "added>0"}, 2, "fail_on:added"},
+		{"added under threshold", []string{before, after, "--fail-on", "added>5"}, 0, ""},
+		{"removed over threshold", []string{before, after, "--fail-on", "removed>0"}, 2, "fail_on:removed"},
+		{"removed under threshold", []string{before, after, "--fail-on", "removed>1"}, 0, ""},
+		{"deep deps triggers", []string{shallow, deep, "--fail-on", "deep-deps"}, 2, "fail_on:deep-deps"},
+		{"deep deps clean", []string{deep, shallow, "--fail-on", "deep-deps"}, 0, ""},
+		{"downgrade triggers", []string{after, before, "--fail-on", "downgrade"}, 2, "fail_on:downgrade"},
+		{"upgrade is not downgrade", []string{before, after, "--fail-on", "downgrade"}, 0, ""},
+		{"multiple conditions", []string{before, after, "--fail-on", "integrity-drift,added>0"}, 2, "fail_on:added"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, exitCode := runCLI(append(tt.args, "--no-pager")...)
+			if exitCode != tt.wantExit {
+				t.Errorf("expected exit code %d, got %d\nstdout: %s\nstderr: %s", tt.wantExit, exitCode, stdout, stderr)
+			}
+			if tt.wantRule != "" && !strings.Contains(stdout, tt.wantRule) {
+				t.Errorf("expected %q in output, got:\n%s", tt.wantRule, stdout)
+			}
+		})
+	}
+}
+
+func TestFailOnInvalidCondition(t *testing.T) {
+	_, stderr, exitCode := runCLI(
+		testdataPath("cyclonedx-before.json"),
+		testdataPath("cyclonedx-after.json"),
+		"--fail-on", "bogus",
+	)
+
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", exitCode)
+	}
+	if !strings.Contains(stderr, "err: parse --fail-on") {
+		t.Errorf("expected --fail-on parse error, got stderr: %s", stderr)
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDirectoryMode(t *testing.T) {
+	before, after := t.TempDir(), t.TempDir()
+	copyFile(t, testdataPath("cyclonedx-before.json"), filepath.Join(before, "api.json"))
+	copyFile(t, testdataPath("cyclonedx-after.json"), filepath.Join(after, "api.json"))
+	copyFile(t, testdataPath("syft-sample.json"), filepath.Join(before, "worker.json"))
+	copyFile(t, testdataPath("syft-sample.json"), filepath.Join(after, "worker.json"))
+	copyFile(t, testdataPath("spdx-sample.json"), filepath.Join(before, "legacy.json"))
+	copyFile(t, testdataPath("spdx-sample.json"), filepath.Join(after, "billing.json"))
+
+	t.Run("text", func(t *testing.T) {
+		stdout, _, exitCode := runCLI(before, after, "--no-pager")
+
+		if exitCode != 1 {
+			t.Errorf("expected exit code 1 (differences found), got %d", exitCode)
+		}
+		for _, want := range []string{
+			"Files compared:  2",
+			"Files changed:   1",
+			"=== api.json ===",
+			"=== worker.json ===",
+			"new-package",
+			"only in before (1)",
+			"- legacy.json",
+			"only in after (1)",
+			"+ billing.json",
+		} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("expected %q in output, got:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		stdout, _, _ := runCLI(before, after, "--json")
+
+		var result struct {
+			Rollup struct {
+				FilesCompared int `json:"files_compared"`
+				Added         int `json:"added"`
+			} `json:"rollup"`
+			Files []struct {
+				Name string `json:"name"`
+			} `json:"files"`
+			OnlyBefore []string `json:"only_before"`
+			OnlyAfter  []string `json:"only_after"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+		if result.Rollup.FilesCompared != 2 || result.Rollup.Added != 1 {
+			t.Errorf("unexpected rollup: %+v", result.Rollup)
+		}
+		if len(result.Files) != 2 || result.Files[0].Name != "api.json" {
+			t.Errorf("unexpected files: %+v", result.Files)
+		}
+		if len(result.OnlyBefore) != 1 || len(result.OnlyAfter) != 1 {
+			t.Errorf("expected one unpaired file per side, got %v / %v", result.OnlyBefore, result.OnlyAfter)
+		}
+	})
+
+	t.Run("identical directories", func(t *testing.T) {
+		_, _, exitCode := runCLI(before, before, "--no-pager")
+		if exitCode != 0 {
+			t.Errorf("expected exit code 0, got %d", exitCode)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, stderr, exitCode := runCLI(before, after, "--format", "sarif")
+		if exitCode != 3 || !strings.Contains(stderr, "directory mode supports") {
+			t.Errorf("expected format error, got exit %d stderr %s", exitCode, stderr)
+		}
+	})
+}
+
 func TestFormatFlagShortcut(t *testing.T) {
 	stdout, _, _ := runCLI(
 		testdataPath("cyclonedx-before.json"),
@@ -671,8 +2393,8 @@ func TestConvertNoTargetFormat(t *testing.T) {
 		"convert", testdataPath("cyclonedx-before.json"),
 	)
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1, got %d", exitCode)
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", exitCode)
 	}
 	if !strings.Contains(stderr, "--to") {
 		t.Errorf("expected error about --to flag, got: %s", stderr)
@@ -682,8 +2404,8 @@ func TestConvertNoTargetFormat(t *testing.T) {
 func TestConvertNoInput(t *testing.T) {
 	_, stderr, exitCode := runCLI("convert", "--to", "spdx")
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1, got %d", exitCode)
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", exitCode)
 	}
 	if !strings.Contains(stderr, "no input") {
 		t.Errorf("expected error about no input file, got: %s", stderr)
@@ -695,8 +2417,8 @@ func TestConvertInvalidFormat(t *testing.T) {
 		"convert", testdataPath("cyclonedx-before.json"), "--to", "bogus",
 	)
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1, got %d", exitCode)
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", exitCode)
 	}
 	if !strings.Contains(stderr, "unknown format") {
 		t.Errorf("expected 'unknown format' error, got: %s", stderr)
@@ -735,3 +2457,392 @@ func TestConvertStdoutIsValidJSON(t *testing.T) {
 		t.Fatalf("stdout is not valid JSON: %v", err)
 	}
 }
+
+func TestDiffLicenses(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, components string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		doc := `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[` + components + `]}`
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	comp := func(name, version, license, hash string) string {
+		return `{"type":"library","name":"` + name + `","version":"` + version + `","purl":"pkg:npm/` + name + `@` + version +
+			`","licenses":[{"license":{"id":"` + license + `"}}],"hashes":[{"alg":"SHA-256","content":"` + hash + `"}]}`
+	}
+	before := write("before.json", comp("lodash", "4.17.20", "MIT", "aa")+","+comp("readline", "1.0.0", "MIT", "bb"))
+	// lodash: version and hash only; readline: relicensed; gpl-lib: added
+	after := write("after.json", comp("lodash", "4.17.21", "MIT", "cc")+","+comp("readline", "1.0.0", "GPL-3.0-only", "bb")+","+comp("gpl-lib", "2.0.0", "AGPL-3.0-only", "dd"))
+	versionOnly := write("version-only.json", comp("lodash", "4.17.21", "MIT", "cc")+","+comp("readline", "1.0.0", "MIT", "bb"))
+
+	stdout, stderr, exitCode := runCLI(before, after, "--diff-licenses", "--no-color")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+	for _, want := range []string{"readline 1.0.0", "+GPL-3.0-only -MIT", "gpl-lib 2.0.0: AGPL-3.0-only [copyleft]", "New copyleft licenses (2)"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+	for _, unwanted := range []string{"lodash", "hash", "Drift", "SBOM Comparison"} {
+		if strings.Contains(stdout, unwanted) {
+			t.Errorf("expected no %q in license report:\n%s", unwanted, stdout)
+		}
+	}
+
+	stdout, _, _ = runCLI(before, after, "--diff-licenses", "--json")
+	var out analysis.LicenseDiff
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if len(out.Changed) != 1 || out.Changed[0].Name != "readline" || len(out.Added) != 1 || !slices.Equal(out.NewCopyleft, []string{"AGPL-3.0-only", "GPL-3.0-only"}) {
+		t.Errorf("unexpected license diff %+v", out)
+	}
+
+	stdout, _, exitCode = runCLI(before, versionOnly, "--diff-licenses", "--format", "markdown")
+	if exitCode != cli.ExitOK {
+		t.Errorf("expected exit code %d for version-only changes, got %d", cli.ExitOK, exitCode)
+	}
+	if !strings.Contains(stdout, "No license changes.") || strings.Contains(stdout, "lodash") {
+		t.Errorf("unexpected markdown for version-only changes:\n%s", stdout)
+	}
+
+	// only license rules apply; max_changed would fail on lodash too
+	for _, tt := range []struct {
+		policy   string
+		wantExit int
+	}{
+		{`{"max_changed": 1}`, cli.ExitDiff},
+		{`{"deny_licenses": ["AGPL-3.0-only"], "max_changed": 1}`, cli.ExitPolicy},
+	} {
+		pol := filepath.Join(dir, "policy.json")
+		if err := os.WriteFile(pol, []byte(tt.policy), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		stdout, _, exitCode := runCLI(before, after, "--diff-licenses", "--policy", pol, "--no-color")
+		if exitCode != tt.wantExit || strings.Contains(stdout, "max_changed") {
+			t.Errorf("policy %s: expected exit %d without max_changed, got exit %d:\n%s", tt.policy, tt.wantExit, exitCode, stdout)
+		}
+	}
+
+	if _, stderr, exitCode := runCLI(before, after, "--diff-licenses", "--format", "sarif"); exitCode != cli.ExitError || !strings.Contains(stderr, "--diff-licenses supports") {
+		t.Errorf("expected an error for sarif, got exit %d: %s", exitCode, stderr)
+	}
+}
+
+func TestDiffProperties(t *testing.T) {
+	before, after := testdataPath("cyclonedx-properties-before.json"), testdataPath("cyclonedx-properties-after.json")
+	stdout, stderr, exitCode := runCLI(before, after, "--json")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+	var out struct {
+		Diff analysis.DiffResult `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if len(out.Diff.Changed) != 1 {
+		t.Fatalf("expected 1 changed component, got %+v", out.Diff.Changed)
+	}
+	c := out.Diff.Changed[0]
+	if c.Drift == nil || c.Drift.Type != analysis.DriftTypeMetadata || !slices.Equal(c.Drift.Properties, []string{"build:compiler", "build:runner"}) {
+		t.Errorf("expected metadata drift on build:compiler and build:runner, got %+v", c.Drift)
+	}
+
+	stdout, _, _ = runCLI(before, after, "--no-color")
+	if !strings.Contains(stdout, "property[build:compiler]: gcc-12.2 -> gcc-13.1") {
+		t.Errorf("expected the property change in text output:\n%s", stdout)
+	}
+}
+
+func TestBaselineStats(t *testing.T) {
+	dir := t.TempDir()
+	// cyclonedx-before.json has 66.7% license coverage, cyclonedx-after.json 100%
+	prior, _, _ := runCLI(testdataPath("cyclonedx-before.json"), "--json")
+	baseline := filepath.Join(dir, "baseline.json")
+	if err := os.WriteFile(baseline, []byte(prior), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	afterRun, _, _ := runCLI(testdataPath("cyclonedx-after.json"), "--json")
+	afterBaseline := filepath.Join(dir, "after-baseline.json")
+	if err := os.WriteFile(afterBaseline, []byte(afterRun), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("improvement", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-after.json"), "--baseline-stats", baseline, "--no-color")
+		if exitCode != cli.ExitOK {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitOK, exitCode, stderr)
+		}
+		if !strings.Contains(stdout, "Baseline Check:") || strings.Contains(stdout, "REGRESSED") {
+			t.Errorf("expected a passing baseline check:\n%s", stdout)
+		}
+	})
+
+	t.Run("regression", func(t *testing.T) {
+		stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), "--baseline-stats", afterBaseline, "--json")
+		if exitCode != cli.ExitPolicy {
+			t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitPolicy, exitCode, stderr)
+		}
+		var out struct {
+			Checks []analysis.HealthCheck `json:"baseline_checks"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+		var regressed []string
+		for _, c := range out.Checks {
+			if c.Regressed {
+				regressed = append(regressed, c.Metric)
+			}
+		}
+		if !slices.Equal(regressed, []string{"license_percent"}) {
+			t.Errorf("expected only license_percent to regress, got %+v", out.Checks)
+		}
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		thresholds := filepath.Join(dir, "thresholds.json")
+		if err := os.WriteFile(thresholds, []byte(`{"min_coverage": {"hash_percent": 50}}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, stderr, exitCode := runCLI(testdataPath("cyclonedx-after.json"), "--baseline-stats", thresholds, "--format", "badge")
+		if exitCode != cli.ExitPolicy || !strings.Contains(stderr, "hash_percent 33.3% is below the threshold 50.0%") {
+			t.Errorf("expected the hash threshold to fail, got exit %d: %s", exitCode, stderr)
+		}
+	})
+
+	t.Run("diff mode", func(t *testing.T) {
+		_, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--baseline-stats", baseline)
+		if exitCode != cli.ExitError || !strings.Contains(stderr, "--baseline-stats takes one SBOM") {
+			t.Errorf("expected an error, got exit %d: %s", exitCode, stderr)
+		}
+	})
+}
+
+func TestInlineWaivers(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	before := write("before.json", `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[]}`)
+	pol := write("policy.json", `{"deny_licenses":["GPL-3.0-only"],"allow_inline_waivers":true}`)
+	strict := write("strict.json", `{"deny_licenses":["GPL-3.0-only"]}`)
+	readline := `{"type":"library","name":"readline","version":"8.2","purl":"pkg:apk/alpine/readline@8.2","licenses":[{"license":{"id":"GPL-3.0-only"}}],
+		"properties":[{"name":"sbomlyze:waive","value":"deny_licenses"}]}`
+	bash := `{"type":"library","name":"bash","version":"5.2","purl":"pkg:apk/alpine/bash@5.2","licenses":[{"license":{"id":"GPL-3.0-only"}}]}`
+
+	tests := []struct {
+		name       string
+		policy     string
+		components string
+		wantExit   int
+		want       []string // violation messages
+	}{
+		{"waived component", pol, readline, cli.ExitDiff, nil},
+		{"waived and unwaived", pol, readline + "," + bash, cli.ExitPolicy, []string{"bash: denied license GPL-3.0-only"}},
+		{"waivers not allowed", strict, readline, cli.ExitPolicy, []string{"readline: denied license GPL-3.0-only"}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := write(fmt.Sprintf("after-%d.json", i), `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[`+tt.components+`]}`)
+			stdout, stderr, exitCode := runCLI(before, after, "--policy", tt.policy, "--json")
+			if exitCode != tt.wantExit {
+				t.Fatalf("expected exit code %d, got %d\nstderr: %s", tt.wantExit, exitCode, stderr)
+			}
+			var out struct {
+				Violations []struct {
+					Message string `json:"message"`
+				} `json:"violations"`
+			}
+			if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+				t.Fatalf("failed to parse JSON: %v", err)
+			}
+			var got []string
+			for _, v := range out.Violations {
+				got = append(got, v.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTable(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), "--format", "table")
+	if exitCode != cli.ExitOK {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", exitCode, stderr)
+	}
+	for _, want := range []string{
+		"COVERAGE PERCENT\n",
+		"License  66.7\n",
+		"TYPE COUNT\nnpm  3\n",
+		"LICENSE COUNT\nMIT     2\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in table output:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "SBOM Statistics") || strings.Contains(stdout, "📦") {
+		t.Errorf("expected tables only, got:\n%s", stdout)
+	}
+}
+
+func TestPrometheusFormat(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--format", "prometheus")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+	for _, want := range []string{
+		"# TYPE sbomlyze_added_total gauge\n",
+		`sbomlyze_added_total{sbom="cyclonedx-after.json"} 1` + "\n",
+		`sbomlyze_removed_total{sbom="cyclonedx-after.json"} 1` + "\n",
+		`sbomlyze_changed_total{sbom="cyclonedx-after.json"} 1` + "\n",
+		`sbomlyze_integrity_drift_total{sbom="cyclonedx-after.json"} 0` + "\n",
+		`sbomlyze_components_total{sbom="cyclonedx-after.json"} 3` + "\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		if !strings.HasPrefix(line, "# ") && !strings.HasPrefix(line, "sbomlyze_") {
+			t.Errorf("unexpected line in exposition: %q", line)
+		}
+	}
+}
+
+func TestDiffstatFormat(t *testing.T) {
+	stdout, stderr, exitCode := runCLI(testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json"), "--format", "diffstat")
+	if exitCode != cli.ExitDiff {
+		t.Fatalf("expected exit code %d, got %d\nstderr: %s", cli.ExitDiff, exitCode, stderr)
+	}
+	if want := "2 files, +1 -1 ~1 components, 0 integrity, 0 deep-deps\n"; stdout != want {
+		t.Errorf("expected %q, got %q", want, stdout)
+	}
+}
+
+func TestDumpSide(t *testing.T) {
+	before, after := testdataPath("cyclonedx-before.json"), testdataPath("cyclonedx-after.json")
+	ids := func(t *testing.T, args ...string) []string {
+		t.Helper()
+		stdout, stderr, exitCode := runCLI(args...)
+		if exitCode != cli.ExitOK {
+			t.Fatalf("expected exit code 0, got %d\nstderr: %s", exitCode, stderr)
+		}
+		var comps []sbom.Component
+		if err := json.Unmarshal([]byte(stdout), &comps); err != nil {
+			t.Fatalf("failed to parse JSON: %v\n%s", err, stdout)
+		}
+		var got []string
+		for _, c := range comps {
+			got = append(got, c.ID)
+		}
+		slices.Sort(got)
+		return got
+	}
+
+	for _, tt := range []struct {
+		flag string
+		want []string
+	}{
+		{"--before-only", []string{"pkg:npm/express", "pkg:npm/lodash", "pkg:npm/old-package"}},
+		{"--after-only", []string{"pkg:npm/express", "pkg:npm/lodash", "pkg:npm/new-package"}},
+	} {
+		if got := ids(t, before, after, tt.flag); !slices.Equal(got, tt.want) {
+			t.Errorf("%s IDs = %v, want %v", tt.flag, got, tt.want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{before, "--after-only"},
+		{before, after, "--before-only", "--after-only"},
+	} {
+		if _, _, exitCode := runCLI(args...); exitCode != cli.ExitError {
+			t.Errorf("%v: expected exit code %d, got %d", args, cli.ExitError, exitCode)
+		}
+	}
+}
+
+func TestMinSeverity(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	lodash := func(version string) string {
+		return fmt.Sprintf(`{"type":"library","name":"lodash","version":%q,"purl":"pkg:npm/lodash@%s"}`, version, version)
+	}
+	bash := `{"type":"library","name":"bash","version":"5.2","purl":"pkg:apk/alpine/bash@5.2","licenses":[{"license":{"id":"GPL-3.0-only"}}]}`
+	before := write("before.json", `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[`+lodash("4.17.20")+`]}`)
+	jump := write("jump.json", `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[`+lodash("99.0.0")+`]}`)
+	jumpAndGPL := write("jump-gpl.json", `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[`+lodash("99.0.0")+","+bash+`]}`)
+	pol := write("policy.json", `{"warn_version_jump":true,"deny_licenses":["GPL-3.0-only"]}`)
+
+	tests := []struct {
+		name     string
+		after    string
+		flags    []string
+		wantExit int
+		want     []string // violation rules
+	}{
+		{"default shows warnings", jumpAndGPL, nil, cli.ExitPolicy, []string{"deny_licenses", "warn_version_jump"}},
+		{"error hides warnings", jumpAndGPL, []string{"--min-severity", "error"}, cli.ExitPolicy, []string{"deny_licenses"}},
+		{"warning shows everything", jumpAndGPL, []string{"--min-severity", "warning"}, cli.ExitPolicy, []string{"deny_licenses", "warn_version_jump"}},
+		{"only warnings hidden", jump, []string{"--min-severity", "error"}, cli.ExitDiff, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{before, tt.after, "--policy", pol, "--json"}, tt.flags...)
+			stdout, stderr, exitCode := runCLI(args...)
+			if exitCode != tt.wantExit {
+				t.Fatalf("expected exit code %d, got %d\nstderr: %s", tt.wantExit, exitCode, stderr)
+			}
+			var out struct {
+				Violations []struct {
+					Rule string `json:"rule"`
+				} `json:"violations"`
+			}
+			if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+				t.Fatalf("failed to parse JSON: %v", err)
+			}
+			var got []string
+			for _, v := range out.Violations {
+				got = append(got, v.Rule)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("text output", func(t *testing.T) {
+		stdout, _, _ := runCLI(before, jumpAndGPL, "--policy", pol, "--min-severity", "error", "--no-color")
+		if strings.Contains(stdout, "suspicious version jump") {
+			t.Errorf("expected the warning to be hidden, got:\n%s", stdout)
+		}
+		if !strings.Contains(stdout, "denied license GPL-3.0-only") {
+			t.Errorf("expected the error to be shown, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("unknown severity", func(t *testing.T) {
+		_, stderr, exitCode := runCLI(before, jump, "--min-severity", "info")
+		if exitCode != cli.ExitError || !strings.Contains(stderr, "--min-severity") {
+			t.Errorf("expected exit %d with a --min-severity error, got %d: %s", cli.ExitError, exitCode, stderr)
+		}
+	})
+}
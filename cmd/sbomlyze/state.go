@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rezmoss/sbomlyze/internal/cli"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+	"github.com/rezmoss/sbomlyze/internal/state"
+)
+
+// loadState opens the --state store, exiting on error.
+func loadState(path string) *state.Store {
+	store, err := state.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "err: load state: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+	return store
+}
+
+// saveSnapshot records this run's inventory as the store's latest snapshot.
+// The label defaults to the current time.
+func saveSnapshot(store *state.Store, opts cli.Options, source string, comps []sbom.Component, info sbom.SBOMInfo) state.Snapshot {
+	now := time.Now().UTC()
+	snap := state.Snapshot{
+		Label:      opts.StateLabel,
+		Created:    now,
+		Source:     source,
+		Info:       info,
+		Components: comps,
+	}
+	if snap.Label == "" {
+		snap.Label = now.Format(time.RFC3339)
+	}
+	store.Add(snap)
+	if err := store.Save(opts.StatePath); err != nil {
+		fmt.Fprintf(os.Stderr, "err: save state: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+	return snap
+}
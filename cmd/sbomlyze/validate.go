@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/cli"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// runValidate lints one SBOM. Broken cross references are errors;
+// component inconsistencies and exact duplicates are warnings.
+func runValidate(path string, opts cli.Options) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "err: read %s: %v\n", path, err)
+		os.Exit(cli.ExitError)
+	}
+	issues, err := sbom.CheckStructure(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "err: parse %s: %v\n", path, err)
+		os.Exit(cli.ExitError)
+	}
+	var errs []cli.ParseWarning
+	for _, is := range issues {
+		errs = append(errs, cli.ParseWarning{Code: is.Code, File: path, Message: is.Message, Field: is.Field})
+	}
+
+	// parse errors are fatal here; there is nothing to lint without components
+	parseOpts := cli.ParseOptions{Strict: true}
+	comps, _, err := parseFileWithOptionsAndInfo(path, &parseOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "err: parse %s: %v\n", path, err)
+		os.Exit(cli.ExitError)
+	}
+	// dropped edges are already reported as errors above, and
+	// --strict-licenses makes unknown licenses errors too
+	parseOpts.Warnings = slices.DeleteFunc(parseOpts.Warnings, func(w cli.ParseWarning) bool {
+		if w.Code == sbom.IssueUnknownLicenseID && opts.StrictLicenses {
+			errs = append(errs, w)
+			return true
+		}
+		return w.Code == sbom.IssueDanglingDependency
+	})
+	for _, d := range analysis.DetectDuplicates(sbom.NormalizeComponents(comps)) {
+		for _, v := range d.Versions {
+			if d.Counts[v] > 1 {
+				parseOpts.AddWarning(cli.WarnExactDuplicate, path, fmt.Sprintf("%s@%s is listed %d times", d.Name, v, d.Counts[v]), "id")
+			}
+		}
+	}
+
+	if opts.Format == "json" {
+		out := struct {
+			File     string             `json:"file"`
+			Errors   []cli.ParseWarning `json:"errors"`
+			Warnings []cli.ParseWarning `json:"warnings"`
+		}{
+			File:     path,
+			Errors:   errs,
+			Warnings: parseOpts.Warnings,
+		}
+		if out.Errors == nil {
+			out.Errors = []cli.ParseWarning{}
+		}
+		if out.Warnings == nil {
+			out.Warnings = []cli.ParseWarning{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(os.Stderr, "err: encode JSON: %v\n", err)
+			os.Exit(cli.ExitError)
+		}
+	} else {
+		fmt.Printf("Validating %s: %d errors, %d warnings\n", path, len(errs), len(parseOpts.Warnings))
+		for _, e := range errs {
+			fmt.Printf("  error: %s (field: %s)\n", e.Message, e.Field)
+		}
+		for _, w := range parseOpts.Warnings {
+			fmt.Printf("  warn:  %s (field: %s)\n", w.Message, w.Field)
+		}
+	}
+
+	if len(errs) > 0 {
+		os.Exit(cli.ExitDiff)
+	}
+}
@@ -0,0 +1,55 @@
+package analysis
+
+import "github.com/rezmoss/sbomlyze/internal/sbom"
+
+// Churn kinds.
+const (
+	ChurnUpgrade     = "upgrade"     // same package, new version
+	ChurnReplacement = "replacement" // same name, different package identity
+)
+
+// ChurnEntry pairs a removed and an added component that share a name.
+type ChurnEntry struct {
+	Name   string         `json:"name"`
+	Kind   string         `json:"kind"`
+	Before sbom.Component `json:"before"`
+	After  sbom.Component `json:"after"`
+}
+
+// ChurnSummary reports removed/added pairs that are really one package
+// moving, e.g. across a major version that changes the component's ID.
+type ChurnSummary struct {
+	Upgrades     int          `json:"upgrades"`
+	Replacements int          `json:"replacements"`
+	Entries      []ChurnEntry `json:"entries"`
+}
+
+// DetectChurn pairs removed and added components by name. As with
+// DetectTypeChanges, a name is only paired when exactly one removed and one
+// added component carry it, and the components stay in Added and Removed.
+// Pairs whose PURL types are both known and differ are left to
+// DetectTypeChanges. A pair is an upgrade when the PURL type matches and the
+// version differs, otherwise a replacement. It returns nil when nothing pairs.
+func DetectChurn(removed, added []sbom.Component) *ChurnSummary {
+	all := func(sbom.Component) bool { return true }
+
+	summary := &ChurnSummary{}
+	for _, p := range pairByName(removed, added, all) {
+		from, to := ExtractPURLType(p.before.PURL), ExtractPURLType(p.after.PURL)
+		if from != to && hasPURLType(p.before) && hasPURLType(p.after) {
+			continue
+		}
+		entry := ChurnEntry{Name: p.name, Kind: ChurnReplacement, Before: p.before, After: p.after}
+		if from == to && p.before.Version != p.after.Version {
+			entry.Kind = ChurnUpgrade
+			summary.Upgrades++
+		} else {
+			summary.Replacements++
+		}
+		summary.Entries = append(summary.Entries, entry)
+	}
+	if len(summary.Entries) == 0 {
+		return nil
+	}
+	return summary
+}
@@ -0,0 +1,115 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestDetectChurn(t *testing.T) {
+	tests := []struct {
+		name    string
+		removed []sbom.Component
+		added   []sbom.Component
+		want    []string // "name:kind"
+	}{
+		{
+			"major version upgrade",
+			[]sbom.Component{{Name: "yaml", Version: "v2.4.0", PURL: "pkg:golang/gopkg.in/yaml.v2@v2.4.0"}},
+			[]sbom.Component{{Name: "yaml", Version: "v3.0.1", PURL: "pkg:golang/gopkg.in/yaml.v3@v3.0.1"}},
+			[]string{"yaml:upgrade"},
+		},
+		{
+			"type change is left to TypeChanged",
+			[]sbom.Component{{Name: "yaml", Version: "3.0.1", PURL: "pkg:deb/debian/yaml@3.0.1"}},
+			[]sbom.Component{{Name: "yaml", Version: "v3.0.1", PURL: "pkg:golang/gopkg.in/yaml@v3.0.1"}},
+			nil,
+		},
+		{
+			"gaining a PURL is a replacement",
+			[]sbom.Component{{Name: "yaml", Version: "3.0.1"}},
+			[]sbom.Component{{Name: "yaml", Version: "3.0.1", PURL: "pkg:golang/gopkg.in/yaml@v3.0.1"}},
+			[]string{"yaml:replacement"},
+		},
+		{
+			"same version under a new namespace is a replacement",
+			[]sbom.Component{{Name: "lodash", Version: "4.17.21", PURL: "pkg:npm/lodash@4.17.21"}},
+			[]sbom.Component{{Name: "lodash", Version: "4.17.21", PURL: "pkg:npm/%40fork/lodash@4.17.21"}},
+			[]string{"lodash:replacement"},
+		},
+		{
+			"ambiguous names are not paired",
+			[]sbom.Component{{Name: "zlib", Version: "1.2"}},
+			[]sbom.Component{{Name: "zlib", Version: "1.3", PURL: "pkg:deb/debian/zlib@1.3"}, {Name: "zlib", Version: "1.3", PURL: "pkg:apk/alpine/zlib@1.3"}},
+			nil,
+		},
+		{
+			"unrelated names",
+			[]sbom.Component{{Name: "a", Version: "1"}},
+			[]sbom.Component{{Name: "b", Version: "1"}},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectChurn(tt.removed, tt.added)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("DetectChurn() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || len(got.Entries) != len(tt.want) {
+				t.Fatalf("DetectChurn() = %+v, want %v", got, tt.want)
+			}
+			for i, e := range got.Entries {
+				if s := e.Name + ":" + e.Kind; s != tt.want[i] {
+					t.Errorf("entry %d = %s, want %s", i, s, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiffComponents_Churn(t *testing.T) {
+	before := []sbom.Component{
+		{ID: "ref:jackson-2", Name: "jackson-databind", Version: "2.15.0"},
+		{ID: "ref:guava-31", Name: "guava", Version: "31.1"},
+		{ID: "ref:old-lib", Name: "old-lib", Version: "1.0"},
+	}
+	after := []sbom.Component{
+		{ID: "ref:jackson-3", Name: "jackson-databind", Version: "3.0.0"},
+		{ID: "ref:guava-32", Name: "guava", Version: "32.0"},
+		{ID: "ref:new-lib", Name: "new-lib", Version: "1.0"},
+	}
+
+	result := DiffComponents(before, after)
+
+	if len(result.Added) != 3 || len(result.Removed) != 3 {
+		t.Errorf("expected pairs to stay in added/removed, got %d/%d", len(result.Added), len(result.Removed))
+	}
+	if result.Churn == nil {
+		t.Fatal("expected churn summary")
+	}
+	if result.Churn.Upgrades != 2 || result.Churn.Replacements != 0 || len(result.Churn.Entries) != 2 {
+		t.Errorf("expected 2 upgrades, got %+v", result.Churn)
+	}
+	if e := result.Churn.Entries[0]; e.Name != "guava" || e.Before.Version != "31.1" || e.After.Version != "32.0" {
+		t.Errorf("unexpected first entry %+v", e)
+	}
+}
+
+func TestDiffComponents_TypeChangeNotChurn(t *testing.T) {
+	before := []sbom.Component{{ID: "pkg:deb/debian/foo", Name: "foo", Version: "1.0", PURL: "pkg:deb/debian/foo@1.0"}}
+	after := []sbom.Component{{ID: "pkg:golang/foo", Name: "foo", Version: "1.0", PURL: "pkg:golang/foo@1.0"}}
+
+	result := DiffComponents(before, after)
+
+	if len(result.TypeChanged) != 1 {
+		t.Fatalf("expected 1 type change, got %+v", result.TypeChanged)
+	}
+	if result.Churn != nil {
+		t.Errorf("expected type change not to be counted as churn, got %+v", result.Churn)
+	}
+}
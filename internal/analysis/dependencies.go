@@ -1,11 +1,24 @@
 package analysis
 
 import (
+	"slices"
 	"sort"
 
 	"github.com/rezmoss/sbomlyze/internal/sbom"
 )
 
+// FanCount is a component's in/out degree in the dependency graph.
+type FanCount struct {
+	In  int `json:"in"`  // components that depend on it
+	Out int `json:"out"` // components it depends on
+}
+
+// DependedOn is a component and how many components depend on it.
+type DependedOn struct {
+	ID    string `json:"id"`
+	Count int    `json:"count"`
+}
+
 // DependencyDiff holds dependency graph changes between two SBOMs.
 type DependencyDiff struct {
 	AddedDeps      map[string][]string `json:"added_deps,omitempty"`
@@ -22,11 +35,32 @@ type TransitiveDep struct {
 	Depth  int      `json:"depth"`
 }
 
-// DepthSummary groups new deps by hop distance.
+// DefaultDeepDepThreshold is the depth from which new dependencies count
+// as risky unless configured otherwise.
+const DefaultDeepDepThreshold = 3
+
+// DepthSummary groups new deps by hop distance. Deep counts deps at
+// Threshold or further, the ones reported as risky.
 type DepthSummary struct {
 	Depth1     int `json:"depth_1"`
 	Depth2     int `json:"depth_2"`
 	Depth3Plus int `json:"depth_3_plus"`
+	Deep       int `json:"deep"`
+	Threshold  int `json:"deep_threshold"`
+}
+
+// IsDeep reports whether depth is at or beyond the risky threshold. A zero
+// Threshold means DefaultDeepDepThreshold.
+func (d *DepthSummary) IsDeep(depth int) bool {
+	return depth >= d.DeepThreshold()
+}
+
+// DeepThreshold returns the depth from which new deps count as risky.
+func (d *DepthSummary) DeepThreshold() int {
+	if d.Threshold < 1 {
+		return DefaultDeepDepThreshold
+	}
+	return d.Threshold
 }
 
 func (d *DependencyDiff) IsEmpty() bool {
@@ -34,17 +68,75 @@ func (d *DependencyDiff) IsEmpty() bool {
 		len(d.TransitiveNew) == 0 && len(d.TransitiveLost) == 0
 }
 
-// BuildDependencyGraph returns component ID -> dependency IDs.
+// BuildDependencyGraph returns component ID -> dependency IDs. Edges from
+// a component to itself are dropped; Validate reports them.
 func BuildDependencyGraph(comps []sbom.Component) map[string][]string {
 	graph := make(map[string][]string)
 	for _, c := range comps {
-		graph[c.ID] = c.Dependencies
+		deps := c.Dependencies
+		if slices.Contains(deps, c.ID) {
+			deps = slices.DeleteFunc(slices.Clone(deps), func(dep string) bool { return dep == c.ID })
+		}
+		graph[c.ID] = deps
 	}
 	return graph
 }
 
+// ComputeFanInOut returns per-ID fan-in and fan-out. Repeated edges count once.
+func ComputeFanInOut(graph map[string][]string) map[string]FanCount {
+	fans := make(map[string]FanCount, len(graph))
+	for id, deps := range graph {
+		seen := make(map[string]bool, len(deps))
+		for _, dep := range deps {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+
+			parent := fans[id]
+			parent.Out++
+			fans[id] = parent
+
+			child := fans[dep]
+			child.In++
+			fans[dep] = child
+		}
+		if _, ok := fans[id]; !ok {
+			fans[id] = FanCount{}
+		}
+	}
+	return fans
+}
+
+// TopDependedOn returns up to n components with the highest fan-in.
+func TopDependedOn(fans map[string]FanCount, n int) []DependedOn {
+	var top []DependedOn
+	for id, f := range fans {
+		if f.In > 0 {
+			top = append(top, DependedOn{ID: id, Count: f.In})
+		}
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].ID < top[j].ID
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
 // DiffDependencyGraphs compares two dependency graphs.
 func DiffDependencyGraphs(before, after map[string][]string) DependencyDiff {
+	return DiffGraphs(newGraph(before), newGraph(after))
+}
+
+// DiffGraphs is DiffDependencyGraphs over cached graphs, which keep the
+// traversals for later passes.
+func DiffGraphs(beforeGraph, afterGraph *Graph) DependencyDiff {
+	before, after := beforeGraph.Edges, afterGraph.Edges
 	diff := DependencyDiff{
 		AddedDeps:   make(map[string][]string),
 		RemovedDeps: make(map[string][]string),
@@ -83,29 +175,16 @@ func DiffDependencyGraphs(before, after map[string][]string) DependencyDiff {
 	}
 
 	// Transitive reachability changes
-	beforeReach := computeAllReachable(before)
-	afterReach := computeAllReachable(after)
-
-	diff.TransitiveNew, diff.TransitiveLost = diffReachability(before, after, beforeReach, afterReach)
+	diff.TransitiveNew, diff.TransitiveLost = diffReachability(beforeGraph, afterGraph)
 
 	// Depth summary
 	if len(diff.TransitiveNew) > 0 {
-		diff.DepthSummary = computeDepthSummary(diff.TransitiveNew)
+		diff.DepthSummary = computeDepthSummary(diff.TransitiveNew, DefaultDeepDepThreshold)
 	}
 
 	return diff
 }
 
-func computeAllReachable(graph map[string][]string) map[string]map[string]bool {
-	reachable := make(map[string]map[string]bool)
-
-	for node := range graph {
-		reachable[node] = bfsReachable(graph, node)
-	}
-
-	return reachable
-}
-
 // bfsReachable returns all nodes reachable from start via BFS.
 func bfsReachable(graph map[string][]string, start string) map[string]bool {
 	visited := make(map[string]bool)
@@ -131,6 +210,32 @@ func bfsReachable(graph map[string][]string, start string) map[string]bool {
 	return visited
 }
 
+// bfsDepths returns the hop distance from start to every reachable node.
+func bfsDepths(graph map[string][]string, start string) map[string]int {
+	depths := map[string]int{start: 0}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range graph[current] {
+			if _, seen := depths[dep]; !seen {
+				depths[dep] = depths[current] + 1
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	return depths
+}
+
+// ComputeGraphDepth returns the deepest and average shortest distance from a
+// root over all non-root nodes. Graphs without edges have depth 0.
+func ComputeGraphDepth(graph map[string][]string) (int, float64) {
+	return newGraph(graph).Depth()
+}
+
 func bfsWithPath(graph map[string][]string, start, target string) ([]string, int) {
 	if start == target {
 		return nil, 0
@@ -170,80 +275,68 @@ func bfsWithPath(graph map[string][]string, start, target string) ([]string, int
 	return nil, -1
 }
 
-func diffReachability(before, after map[string][]string, beforeReach, afterReach map[string]map[string]bool) ([]TransitiveDep, []TransitiveDep) {
-	var newDeps, lostDeps []TransitiveDep
-	seen := make(map[string]bool)
-
-	// Find root nodes (nodes that are not dependencies of any other node)
-	roots := FindRoots(after)
-	if len(roots) == 0 {
-		// Fallback: use all nodes as potential roots
-		for node := range after {
-			roots = append(roots, node)
-		}
-	}
-
-	// Find new transitive deps - only check from root nodes for correct depth
-	for _, root := range roots {
-		afterSet := afterReach[root]
-		beforeSet := beforeReach[root]
-		if beforeSet == nil {
-			beforeSet = make(map[string]bool)
-		}
+func diffReachability(before, after *Graph) ([]TransitiveDep, []TransitiveDep) {
+	newDeps := lostTransitive(after, before)
+	lostDeps := lostTransitive(before, after)
+	return newDeps, lostDeps
+}
 
-		for dep := range afterSet {
-			if !beforeSet[dep] && !seen[dep] {
-				// This is a new transitive dependency
-				path, depth := bfsWithPath(after, root, dep)
-				if depth > 1 { // Only report truly transitive (not direct)
-					newDeps = append(newDeps, TransitiveDep{
-						Target: dep,
-						Via:    path,
-						Depth:  depth,
-					})
-					seen[dep] = true
-				}
+// lostTransitive returns the deps reachable from a root of from, at depth
+// two or more, that the same node does not reach in to.
+func lostTransitive(from, to *Graph) []TransitiveDep {
+	var deps []TransitiveDep
+	seen := make(map[string]bool)
+	for _, root := range from.Roots() {
+		for dep, d := range from.Depths(root) {
+			// only truly transitive deps, not direct ones
+			if d < 2 || seen[dep] || to.Reaches(root, dep) {
+				continue
 			}
+			path, depth := bfsWithPath(from.Edges, root, dep)
+			deps = append(deps, TransitiveDep{
+				Target: dep,
+				Via:    path,
+				Depth:  depth,
+			})
+			seen[dep] = true
 		}
 	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Target < deps[j].Target })
+	return deps
+}
 
-	// Find roots for before graph
-	beforeRoots := FindRoots(before)
-	if len(beforeRoots) == 0 {
-		for node := range before {
-			beforeRoots = append(beforeRoots, node)
-		}
-	}
-
-	seen = make(map[string]bool)
-
-	// Find lost transitive deps
-	for _, root := range beforeRoots {
-		beforeSet := beforeReach[root]
-		afterSet := afterReach[root]
-		if afterSet == nil {
-			afterSet = make(map[string]bool)
-		}
+// SeveredChain is a removed edge and the transitive dependencies lost
+// because of it. From and To are empty when no removed edge lies on the
+// lost path.
+type SeveredChain struct {
+	From string          `json:"from,omitempty"`
+	To   string          `json:"to,omitempty"`
+	Lost []TransitiveDep `json:"lost"`
+}
 
-		for dep := range beforeSet {
-			if !afterSet[dep] && !seen[dep] {
-				path, depth := bfsWithPath(before, root, dep)
-				if depth > 1 {
-					lostDeps = append(lostDeps, TransitiveDep{
-						Target: dep,
-						Via:    path,
-						Depth:  depth,
-					})
-					seen[dep] = true
-				}
+// SeveredChains groups TransitiveLost by the first removed edge on each
+// lost dependency's before-graph path, so a severed chain shows up as one
+// root-cause edge instead of every node downstream of it. Chains keep the
+// order of their first lost dependency.
+func (d *DependencyDiff) SeveredChains() []SeveredChain {
+	var chains []SeveredChain
+	index := make(map[[2]string]int)
+	for _, td := range d.TransitiveLost {
+		var edge [2]string
+		for i := 0; i+1 < len(td.Via); i++ {
+			if slices.Contains(d.RemovedDeps[td.Via[i]], td.Via[i+1]) {
+				edge = [2]string{td.Via[i], td.Via[i+1]}
+				break
 			}
 		}
+		if i, ok := index[edge]; ok && edge != ([2]string{}) {
+			chains[i].Lost = append(chains[i].Lost, td)
+			continue
+		}
+		index[edge] = len(chains)
+		chains = append(chains, SeveredChain{From: edge[0], To: edge[1], Lost: []TransitiveDep{td}})
 	}
-
-	sort.Slice(newDeps, func(i, j int) bool { return newDeps[i].Target < newDeps[j].Target })
-	sort.Slice(lostDeps, func(i, j int) bool { return lostDeps[i].Target < lostDeps[j].Target })
-
-	return newDeps, lostDeps
+	return chains
 }
 
 // FindRoots returns nodes that no other node depends on.
@@ -265,10 +358,13 @@ func FindRoots(graph map[string][]string) []string {
 	return roots
 }
 
-func computeDepthSummary(deps []TransitiveDep) *DepthSummary {
-	summary := &DepthSummary{}
+func computeDepthSummary(deps []TransitiveDep, threshold int) *DepthSummary {
+	summary := &DepthSummary{Threshold: threshold}
 
 	for _, dep := range deps {
+		if summary.IsDeep(dep.Depth) {
+			summary.Deep++
+		}
 		switch dep.Depth {
 		case 1:
 			summary.Depth1++
@@ -281,3 +377,13 @@ func computeDepthSummary(deps []TransitiveDep) *DepthSummary {
 
 	return summary
 }
+
+// ApplyDeepDepThreshold recomputes the depth summary so that new
+// dependencies at threshold or deeper count as risky. A threshold below 1
+// leaves the default in place.
+func ApplyDeepDepThreshold(result *DiffResult, threshold int) {
+	if threshold < 1 || result.Dependencies == nil || result.Dependencies.DepthSummary == nil {
+		return
+	}
+	result.Dependencies.DepthSummary = computeDepthSummary(result.Dependencies.TransitiveNew, threshold)
+}
@@ -1,6 +1,9 @@
 package analysis
 
 import (
+	"encoding/json"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/rezmoss/sbomlyze/internal/sbom"
@@ -27,6 +30,28 @@ func TestBuildDependencyGraph(t *testing.T) {
 		}
 	})
 
+	t.Run("drops self-edges", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "pkg:npm/app", Name: "app", Version: "1.0.0", Dependencies: []string{"pkg:npm/app", "pkg:npm/lodash"}},
+			{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21", Dependencies: []string{"pkg:npm/lodash"}},
+		}
+
+		graph := BuildDependencyGraph(comps)
+
+		if !slices.Equal(graph["pkg:npm/app"], []string{"pkg:npm/lodash"}) {
+			t.Errorf("expected app -> lodash only, got %v", graph["pkg:npm/app"])
+		}
+		if len(graph["pkg:npm/lodash"]) != 0 {
+			t.Errorf("expected lodash to have no dependencies, got %v", graph["pkg:npm/lodash"])
+		}
+		if len(comps[0].Dependencies) != 2 {
+			t.Errorf("expected the component's own list to be left alone, got %v", comps[0].Dependencies)
+		}
+		if roots := FindRoots(graph); !slices.Equal(roots, []string{"pkg:npm/app"}) {
+			t.Errorf("expected app as the only root, got %v", roots)
+		}
+	})
+
 	t.Run("handles empty dependencies", func(t *testing.T) {
 		comps := []sbom.Component{
 			{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21"},
@@ -201,6 +226,108 @@ func TestTransitiveReachability(t *testing.T) {
 			t.Errorf("expected depth >= 3, got %d", maxDepth)
 		}
 	})
+
+	t.Run("records the shortest path from a root in JSON", func(t *testing.T) {
+		before := map[string][]string{
+			"app": {"a"},
+			"a":   {},
+		}
+		after := map[string][]string{
+			"app": {"a", "x"},
+			"a":   {"b"},
+			"b":   {"c"},
+			"x":   {"c"},
+			"c":   {"d"},
+			"d":   {},
+		}
+
+		data, err := json.Marshal(DiffDependencyGraphs(before, after))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			TransitiveNew []struct {
+				Target string   `json:"target"`
+				Via    []string `json:"via"`
+				Depth  int      `json:"depth"`
+			} `json:"transitive_new"`
+		}
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if len(out.TransitiveNew) == 0 {
+			t.Fatalf("expected new transitive dependencies in %s", data)
+		}
+		roots := FindRoots(after)
+		for _, td := range out.TransitiveNew {
+			if len(td.Via) != td.Depth+1 {
+				t.Errorf("%s: via %v does not match depth %d", td.Target, td.Via, td.Depth)
+				continue
+			}
+			if !slices.Contains(roots, td.Via[0]) || td.Via[len(td.Via)-1] != td.Target {
+				t.Errorf("%s: via %v should run from a root %v to the target", td.Target, td.Via, roots)
+			}
+		}
+		for _, td := range out.TransitiveNew {
+			if td.Target == "d" && !slices.Equal(td.Via, []string{"app", "x", "c", "d"}) {
+				t.Errorf("expected the shortest path to d, got %v", td.Via)
+			}
+		}
+	})
+}
+
+func TestSeveredChains(t *testing.T) {
+	// app -> web -> {router, tmpl}; router -> path; cli -> flags
+	before := map[string][]string{
+		"app":    {"web", "cli"},
+		"web":    {"router", "tmpl"},
+		"router": {"path"},
+		"cli":    {"flags"},
+	}
+
+	t.Run("collapses a severed chain to its cut edge", func(t *testing.T) {
+		after := map[string][]string{
+			"app": {"cli"},
+			"cli": {"flags"},
+		}
+		diff := DiffDependencyGraphs(before, after)
+
+		chains := diff.SeveredChains()
+		if len(chains) != 1 {
+			t.Fatalf("expected 1 chain, got %+v", chains)
+		}
+		c := chains[0]
+		if c.From != "app" || c.To != "web" {
+			t.Errorf("expected cut edge app -> web, got %s -> %s", c.From, c.To)
+		}
+		var targets []string
+		for _, td := range c.Lost {
+			targets = append(targets, td.Target)
+		}
+		if strings.Join(targets, ",") != "path,router,tmpl" {
+			t.Errorf("expected path, router and tmpl lost, got %v", targets)
+		}
+	})
+
+	t.Run("separate cuts stay separate", func(t *testing.T) {
+		after := map[string][]string{
+			"app":    {"web", "cli"},
+			"web":    {"router", "tmpl"},
+			"router": {},
+			"cli":    {},
+		}
+		diff := DiffDependencyGraphs(before, after)
+
+		chains := diff.SeveredChains()
+		if len(chains) != 2 {
+			t.Fatalf("expected 2 chains, got %+v", chains)
+		}
+		for _, c := range chains {
+			if len(c.Lost) != 1 || c.To != c.Lost[0].Target {
+				t.Errorf("expected one lost dep cut directly, got %+v", c)
+			}
+		}
+	})
 }
 
 func TestDepthSummary(t *testing.T) {
@@ -214,7 +341,7 @@ func TestDepthSummary(t *testing.T) {
 			{Target: "f", Depth: 5},
 		}
 
-		summary := computeDepthSummary(deps)
+		summary := computeDepthSummary(deps, DefaultDeepDepThreshold)
 
 		if summary.Depth1 != 1 {
 			t.Errorf("expected 1 depth-1 dep, got %d", summary.Depth1)
@@ -225,9 +352,54 @@ func TestDepthSummary(t *testing.T) {
 		if summary.Depth3Plus != 3 {
 			t.Errorf("expected 3 depth-3+ deps, got %d", summary.Depth3Plus)
 		}
+		if summary.Deep != 3 {
+			t.Errorf("expected 3 deep deps, got %d", summary.Deep)
+		}
 	})
 }
 
+func TestApplyDeepDepThreshold(t *testing.T) {
+	deps := []TransitiveDep{
+		{Target: "a", Depth: 1},
+		{Target: "b", Depth: 2},
+		{Target: "c", Depth: 2},
+		{Target: "d", Depth: 3},
+		{Target: "e", Depth: 5},
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		wantDeep  int
+		wantDepth int // first depth counted as deep
+	}{
+		{"unset keeps default", 0, 2, 3},
+		{"threshold 2 makes depth 2 risky", 2, 4, 2},
+		{"threshold 4 skips depth 3", 4, 1, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DiffResult{Dependencies: &DependencyDiff{
+				TransitiveNew: deps,
+				DepthSummary:  computeDepthSummary(deps, DefaultDeepDepThreshold),
+			}}
+			ApplyDeepDepThreshold(&result, tt.threshold)
+
+			ds := result.Dependencies.DepthSummary
+			if ds.Deep != tt.wantDeep {
+				t.Errorf("Deep = %d, want %d", ds.Deep, tt.wantDeep)
+			}
+			if ds.IsDeep(tt.wantDepth-1) || !ds.IsDeep(tt.wantDepth) {
+				t.Errorf("expected depth %d to be the first deep depth, threshold %d", tt.wantDepth, ds.DeepThreshold())
+			}
+			if ds.Depth1 != 1 || ds.Depth2 != 2 || ds.Depth3Plus != 2 {
+				t.Errorf("buckets changed: %+v", ds)
+			}
+		})
+	}
+}
+
 func TestBFSReachable(t *testing.T) {
 	t.Run("finds all reachable nodes", func(t *testing.T) {
 		graph := map[string][]string{
@@ -302,3 +474,132 @@ func TestBFSWithPath(t *testing.T) {
 		}
 	})
 }
+
+func TestComputeGraphDepth(t *testing.T) {
+	t.Run("known chain", func(t *testing.T) {
+		graph := map[string][]string{
+			"app": {"a"},
+			"a":   {"b"},
+			"b":   {"c"},
+			"c":   {},
+		}
+
+		maxDepth, avgDepth := ComputeGraphDepth(graph)
+
+		if maxDepth != 3 {
+			t.Errorf("expected max depth 3, got %d", maxDepth)
+		}
+		if avgDepth != 2.0 {
+			t.Errorf("expected avg depth 2.0, got %v", avgDepth)
+		}
+	})
+
+	t.Run("uses shortest distance per node", func(t *testing.T) {
+		graph := map[string][]string{
+			"app": {"a", "c"},
+			"a":   {"b"},
+			"b":   {"c"},
+			"c":   {},
+		}
+
+		maxDepth, _ := ComputeGraphDepth(graph)
+
+		if maxDepth != 2 {
+			t.Errorf("expected max depth 2 (c is direct), got %d", maxDepth)
+		}
+	})
+
+	t.Run("no edges", func(t *testing.T) {
+		graph := map[string][]string{"a": nil, "b": nil}
+
+		maxDepth, avgDepth := ComputeGraphDepth(graph)
+
+		if maxDepth != 0 || avgDepth != 0 {
+			t.Errorf("expected depth 0, got %d / %v", maxDepth, avgDepth)
+		}
+	})
+
+	t.Run("pure cycle terminates", func(t *testing.T) {
+		graph := map[string][]string{
+			"a": {"b"},
+			"b": {"c"},
+			"c": {"a"},
+		}
+
+		maxDepth, _ := ComputeGraphDepth(graph)
+
+		if maxDepth != 1 {
+			t.Errorf("expected max depth 1 with every node as root, got %d", maxDepth)
+		}
+	})
+
+	t.Run("set on stats", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "app", Name: "app", Dependencies: []string{"a"}},
+			{ID: "a", Name: "a", Dependencies: []string{"b"}},
+			{ID: "b", Name: "b"},
+		}
+
+		stats := ComputeStats(comps)
+
+		if stats.MaxDepth != 2 {
+			t.Errorf("expected MaxDepth 2, got %d", stats.MaxDepth)
+		}
+		if stats.AvgDepth != 1.5 {
+			t.Errorf("expected AvgDepth 1.5, got %v", stats.AvgDepth)
+		}
+	})
+}
+
+func TestComputeFanInOut(t *testing.T) {
+	t.Run("hub and spoke", func(t *testing.T) {
+		graph := map[string][]string{
+			"a":   {"hub"},
+			"b":   {"hub"},
+			"c":   {"hub", "hub"},
+			"hub": {"x", "y"},
+			"x":   {},
+			"y":   {},
+		}
+
+		fans := ComputeFanInOut(graph)
+
+		if fans["hub"].In != 3 {
+			t.Errorf("expected hub fan-in 3, got %d", fans["hub"].In)
+		}
+		if fans["hub"].Out != 2 {
+			t.Errorf("expected hub fan-out 2, got %d", fans["hub"].Out)
+		}
+		if fans["c"].Out != 1 {
+			t.Errorf("expected duplicate edge counted once, got fan-out %d", fans["c"].Out)
+		}
+		if fans["a"].In != 0 {
+			t.Errorf("expected a fan-in 0, got %d", fans["a"].In)
+		}
+
+		top := TopDependedOn(fans, 2)
+		if len(top) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(top))
+		}
+		if top[0].ID != "hub" || top[0].Count != 3 {
+			t.Errorf("expected hub first with 3, got %+v", top[0])
+		}
+		if top[1].ID != "x" {
+			t.Errorf("expected ties broken by ID, got %+v", top[1])
+		}
+	})
+
+	t.Run("set on stats", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "a", Name: "a", Dependencies: []string{"hub"}},
+			{ID: "b", Name: "b", Dependencies: []string{"hub"}},
+			{ID: "hub", Name: "hub"},
+		}
+
+		stats := ComputeStats(comps)
+
+		if len(stats.MostDependedOn) != 1 || stats.MostDependedOn[0].ID != "hub" {
+			t.Errorf("expected hub as most depended on, got %+v", stats.MostDependedOn)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package analysis
+
+import "github.com/rezmoss/sbomlyze/internal/sbom"
+
+// Differ decides how DiffComponentsWith matches components across the two
+// SBOMs and what counts as a change between a matched pair. Like the rest
+// of internal/, it is only for use within sbomlyze; it is not a public
+// extension point for other modules.
+type Differ interface {
+	// ID is the key a component is matched by.
+	ID(c sbom.Component) string
+	// Compare returns the changes from before to after; none means unchanged.
+	Compare(before, after sbom.Component) []string
+}
+
+// DefaultDiffer matches components by identity ID and compares them with
+// sbom.CompareComponents.
+type DefaultDiffer struct{}
+
+// ID returns c.ID.
+func (DefaultDiffer) ID(c sbom.Component) string {
+	return c.ID
+}
+
+// Compare returns sbom.CompareComponents(before, after).
+func (DefaultDiffer) Compare(before, after sbom.Component) []string {
+	return sbom.CompareComponents(before, after)
+}
@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// licenseBlindDiffer is DefaultDiffer without license changes.
+type licenseBlindDiffer struct{ DefaultDiffer }
+
+func (d licenseBlindDiffer) Compare(before, after sbom.Component) []string {
+	return slices.DeleteFunc(d.DefaultDiffer.Compare(before, after), func(change string) bool {
+		return strings.HasPrefix(change, "licenses:")
+	})
+}
+
+// versionedDiffer matches components by ID and version.
+type versionedDiffer struct{ DefaultDiffer }
+
+func (versionedDiffer) ID(c sbom.Component) string {
+	return c.ID + "@" + c.Version
+}
+
+func TestDiffComponentsWith(t *testing.T) {
+	before := []sbom.Component{
+		{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}},
+		{ID: "pkg:npm/express", Name: "express", Version: "4.18.0", Licenses: []string{"MIT"}},
+	}
+	after := []sbom.Component{
+		{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21", Licenses: []string{"Apache-2.0"}},
+		{ID: "pkg:npm/express", Name: "express", Version: "4.19.0", Licenses: []string{"ISC"}},
+	}
+
+	ids := func(r DiffResult) (added, removed, changed []string) {
+		for _, c := range r.Added {
+			added = append(added, c.ID+"@"+c.Version)
+		}
+		for _, c := range r.Removed {
+			removed = append(removed, c.ID+"@"+c.Version)
+		}
+		for _, c := range r.Changed {
+			changed = append(changed, c.ID)
+		}
+		return added, removed, changed
+	}
+
+	tests := []struct {
+		name        string
+		differ      Differ
+		wantAdded   []string
+		wantRemoved []string
+		wantChanged []string
+	}{
+		{"default", nil, nil, nil, []string{"pkg:npm/express", "pkg:npm/lodash"}},
+		{"explicit default", DefaultDiffer{}, nil, nil, []string{"pkg:npm/express", "pkg:npm/lodash"}},
+		{"ignore licenses", licenseBlindDiffer{}, nil, nil, []string{"pkg:npm/express"}},
+		{"version-sensitive identity", versionedDiffer{},
+			[]string{"pkg:npm/express@4.19.0"}, []string{"pkg:npm/express@4.18.0"}, []string{"pkg:npm/lodash@4.17.21"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, changed := ids(DiffComponentsWith(before, after, tt.differ))
+			if !slices.Equal(added, tt.wantAdded) {
+				t.Errorf("added = %v, want %v", added, tt.wantAdded)
+			}
+			if !slices.Equal(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+			if !slices.Equal(changed, tt.wantChanged) {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+
+	t.Run("changes come from the differ", func(t *testing.T) {
+		result := DiffComponentsWith(before, after, licenseBlindDiffer{})
+		if len(result.Changed) != 1 || !slices.Equal(result.Changed[0].Changes, []string{"version: 4.18.0 -> 4.19.0"}) {
+			t.Errorf("expected only the express version change, got %+v", result.Changed)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package analysis
+
+import "sort"
+
+// FileDiff is one paired file's diff in directory mode.
+type FileDiff struct {
+	Name string     `json:"name"`
+	Diff DiffResult `json:"diff"`
+}
+
+// DirRollup aggregates counts across all paired files.
+type DirRollup struct {
+	FilesCompared  int `json:"files_compared"`
+	FilesChanged   int `json:"files_changed"`
+	Added          int `json:"added"`
+	Removed        int `json:"removed"`
+	Changed        int `json:"changed"`
+	IntegrityDrift int `json:"integrity_drift"`
+}
+
+// DirDiffResult holds a directory-to-directory comparison.
+type DirDiffResult struct {
+	Rollup     DirRollup  `json:"rollup"`
+	Files      []FileDiff `json:"files"`
+	OnlyBefore []string   `json:"only_before,omitempty"`
+	OnlyAfter  []string   `json:"only_after,omitempty"`
+}
+
+// PairFiles matches file names present on both sides. All returned slices are sorted.
+func PairFiles(before, after []string) (paired, onlyBefore, onlyAfter []string) {
+	afterSet := ToSet(after)
+	beforeSet := ToSet(before)
+	for name := range beforeSet {
+		if afterSet[name] {
+			paired = append(paired, name)
+		} else {
+			onlyBefore = append(onlyBefore, name)
+		}
+	}
+	for name := range afterSet {
+		if !beforeSet[name] {
+			onlyAfter = append(onlyAfter, name)
+		}
+	}
+	sort.Strings(paired)
+	sort.Strings(onlyBefore)
+	sort.Strings(onlyAfter)
+	return paired, onlyBefore, onlyAfter
+}
+
+// ComputeDirRollup totals per-file diffs.
+func ComputeDirRollup(files []FileDiff) DirRollup {
+	rollup := DirRollup{FilesCompared: len(files)}
+	for _, f := range files {
+		d := f.Diff
+		if len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0 {
+			rollup.FilesChanged++
+		}
+		rollup.Added += len(d.Added)
+		rollup.Removed += len(d.Removed)
+		rollup.Changed += len(d.Changed)
+		if d.DriftSummary != nil {
+			rollup.IntegrityDrift += d.DriftSummary.IntegrityDrift
+		}
+	}
+	return rollup
+}
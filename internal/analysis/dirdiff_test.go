@@ -0,0 +1,43 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestPairFiles(t *testing.T) {
+	paired, onlyBefore, onlyAfter := PairFiles(
+		[]string{"web.json", "api.json", "legacy.json"},
+		[]string{"api.json", "worker.json", "web.json"},
+	)
+
+	if !reflect.DeepEqual(paired, []string{"api.json", "web.json"}) {
+		t.Errorf("paired = %v", paired)
+	}
+	if !reflect.DeepEqual(onlyBefore, []string{"legacy.json"}) {
+		t.Errorf("onlyBefore = %v", onlyBefore)
+	}
+	if !reflect.DeepEqual(onlyAfter, []string{"worker.json"}) {
+		t.Errorf("onlyAfter = %v", onlyAfter)
+	}
+}
+
+func TestComputeDirRollup(t *testing.T) {
+	files := []FileDiff{
+		{Name: "a.json", Diff: DiffResult{
+			Added:        []sbom.Component{{Name: "x"}, {Name: "y"}},
+			Changed:      []ChangedComponent{{Name: "z"}},
+			DriftSummary: &DriftSummary{IntegrityDrift: 1},
+		}},
+		{Name: "b.json", Diff: DiffResult{}},
+		{Name: "c.json", Diff: DiffResult{Removed: []sbom.Component{{Name: "w"}}}},
+	}
+
+	got := ComputeDirRollup(files)
+	want := DirRollup{FilesCompared: 3, FilesChanged: 2, Added: 2, Removed: 1, Changed: 1, IntegrityDrift: 1}
+	if got != want {
+		t.Errorf("ComputeDirRollup = %+v, want %+v", got, want)
+	}
+}
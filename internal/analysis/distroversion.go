@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// Version change kinds for distro packages (apk, deb, rpm).
+const (
+	VersionChangeEpoch    = "epoch"    // epoch bumped, ordering reset
+	VersionChangeUpstream = "upstream" // upstream source version changed
+	VersionChangeRelease  = "release"  // same upstream, packaging rebuild only
+)
+
+// distroPackageType returns apk, deb or rpm for distro packages, else "".
+func distroPackageType(c sbom.Component) string {
+	ptype := ExtractPURLType(c.PURL)
+	if ptype == "unknown" {
+		ptype = strings.ToLower(c.Type)
+	}
+	switch ptype {
+	case "apk", "deb", "rpm":
+		return ptype
+	}
+	return ""
+}
+
+// SplitDistroVersion splits a distro version into epoch, upstream and release.
+// apk: 1.27.3-r1; deb: [epoch:]upstream[-revision]; rpm: [epoch:]version-release.
+// A zero epoch is returned as "" so "0:1.0-1" and "1.0-1" compare equal.
+func SplitDistroVersion(pkgType, version string) (epoch, upstream, release string) {
+	upstream = version
+	if pkgType != "apk" {
+		if e, rest, ok := strings.Cut(upstream, ":"); ok && isDigits(e) {
+			epoch, upstream = strings.TrimLeft(e, "0"), rest
+		}
+	}
+
+	i := strings.LastIndex(upstream, "-")
+	if i < 0 {
+		return epoch, upstream, ""
+	}
+	rel := upstream[i+1:]
+	if pkgType == "apk" && !(strings.HasPrefix(rel, "r") && isDigits(rel[1:])) {
+		return epoch, upstream, ""
+	}
+	return epoch, upstream[:i], rel
+}
+
+// ClassifyDistroVersionChange labels a distro version change as epoch,
+// upstream or release; "" if the versions are equivalent.
+func ClassifyDistroVersionChange(pkgType, from, to string) string {
+	fe, fu, fr := SplitDistroVersion(pkgType, from)
+	te, tu, tr := SplitDistroVersion(pkgType, to)
+	switch {
+	case fe != te:
+		return VersionChangeEpoch
+	case fu != tu:
+		return VersionChangeUpstream
+	case fr != tr:
+		return VersionChangeRelease
+	}
+	return ""
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
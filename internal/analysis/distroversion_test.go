@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestSplitDistroVersion(t *testing.T) {
+	tests := []struct {
+		pkgType, version         string
+		epoch, upstream, release string
+	}{
+		{"apk", "1.27.3-r1", "", "1.27.3", "r1"},
+		{"apk", "1.36.1-r15", "", "1.36.1", "r15"},
+		{"apk", "2.0-beta", "", "2.0-beta", ""},
+		{"deb", "2.4.52-1ubuntu4", "", "2.4.52", "1ubuntu4"},
+		{"deb", "1:2.3.4-5", "1", "2.3.4", "5"},
+		{"deb", "0:1.0-1", "", "1.0", "1"},
+		{"deb", "1.0", "", "1.0", ""},
+		{"deb", "2.36-9+deb12u4", "", "2.36", "9+deb12u4"},
+		{"rpm", "2:8.2.2637-20.el9", "2", "8.2.2637", "20.el9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pkgType+" "+tt.version, func(t *testing.T) {
+			e, u, r := SplitDistroVersion(tt.pkgType, tt.version)
+			if e != tt.epoch || u != tt.upstream || r != tt.release {
+				t.Errorf("SplitDistroVersion(%q, %q) = (%q, %q, %q), want (%q, %q, %q)",
+					tt.pkgType, tt.version, e, u, r, tt.epoch, tt.upstream, tt.release)
+			}
+		})
+	}
+}
+
+func TestClassifyDistroVersionChange(t *testing.T) {
+	tests := []struct {
+		name, pkgType, from, to string
+		want                    string
+	}{
+		{"apk release bump", "apk", "1.27.3-r1", "1.27.3-r2", VersionChangeRelease},
+		{"apk upstream bump", "apk", "1.27.3-r1", "1.27.4-r0", VersionChangeUpstream},
+		{"deb revision bump", "deb", "2.4.52-1ubuntu4", "2.4.52-1ubuntu4.1", VersionChangeRelease},
+		{"deb upstream bump", "deb", "2.4.52-1ubuntu4", "2.4.57-2", VersionChangeUpstream},
+		{"deb epoch bump", "deb", "2.4.52-1", "1:2.4.52-1", VersionChangeEpoch},
+		{"deb zero epoch is no change", "deb", "0:1.0-1", "1.0-1", ""},
+		{"rpm release bump", "rpm", "8.2.2637-20.el9", "8.2.2637-21.el9", VersionChangeRelease},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyDistroVersionChange(tt.pkgType, tt.from, tt.to); got != tt.want {
+				t.Errorf("ClassifyDistroVersionChange(%q, %q, %q) = %q, want %q", tt.pkgType, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyDrift_DistroVersionChange(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after sbom.Component
+		want          string
+	}{
+		{
+			"apk release-only rebuild",
+			sbom.Component{PURL: "pkg:apk/alpine/nginx@1.27.3-r1", Version: "1.27.3-r1"},
+			sbom.Component{PURL: "pkg:apk/alpine/nginx@1.27.3-r2", Version: "1.27.3-r2"},
+			VersionChangeRelease,
+		},
+		{
+			"deb upstream change",
+			sbom.Component{PURL: "pkg:deb/ubuntu/apache2@2.4.52-1ubuntu4", Version: "2.4.52-1ubuntu4"},
+			sbom.Component{PURL: "pkg:deb/ubuntu/apache2@2.4.58-1ubuntu1", Version: "2.4.58-1ubuntu1"},
+			VersionChangeUpstream,
+		},
+		{
+			"type field fallback",
+			sbom.Component{Type: "apk", Version: "3.0.1-r0"},
+			sbom.Component{Type: "apk", Version: "3.0.1-r3"},
+			VersionChangeRelease,
+		},
+		{
+			"npm is not classified",
+			sbom.Component{PURL: "pkg:npm/lodash@4.17.20", Version: "4.17.20"},
+			sbom.Component{PURL: "pkg:npm/lodash@4.17.21", Version: "4.17.21"},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := ClassifyDrift(tt.before, tt.after)
+			if drift.Type != DriftTypeVersion {
+				t.Errorf("expected version drift, got %s", drift.Type)
+			}
+			if drift.VersionChange != tt.want {
+				t.Errorf("VersionChange = %q, want %q", drift.VersionChange, tt.want)
+			}
+		})
+	}
+}
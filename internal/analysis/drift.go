@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/rezmoss/sbomlyze/internal/sbom"
 )
@@ -18,11 +19,40 @@ const (
 
 // DriftInfo holds drift details for a component.
 type DriftInfo struct {
-	Type         DriftType `json:"type"`
-	HashChanges  *HashDiff `json:"hash_changes,omitempty"`
-	VersionFrom  string    `json:"version_from,omitempty"`
-	VersionTo    string    `json:"version_to,omitempty"`
-	LicensesDiff []string  `json:"licenses_diff,omitempty"`
+	Type           DriftType `json:"type"`
+	HashChanges    *HashDiff `json:"hash_changes,omitempty"`
+	VersionFrom    string    `json:"version_from,omitempty"`
+	VersionTo      string    `json:"version_to,omitempty"`
+	VersionChange  string    `json:"version_change,omitempty"` // apk/deb/rpm: epoch, upstream or release
+	LicensesDiff   []string  `json:"licenses_diff,omitempty"`
+	LicenseRemoved bool      `json:"license_removed,omitempty"` // had licenses before, none after
+	SuspiciousJump bool      `json:"suspicious_version_jump,omitempty"`
+	Properties     []string  `json:"properties_changed,omitempty"` // names of added, removed or changed properties
+	SupplierFrom   string    `json:"supplier_from,omitempty"`      // set when sbom.SupplierChanged
+	SupplierTo     string    `json:"supplier_to,omitempty"`
+}
+
+// Metadata drift kinds, as returned by DriftInfo.MetadataKinds.
+const (
+	MetadataLicense  = "license"
+	MetadataSupplier = "supplier"
+	MetadataProperty = "property"
+)
+
+// MetadataKinds lists which metadata changed: licenses, supplier and
+// properties, in that order.
+func (d *DriftInfo) MetadataKinds() []string {
+	var kinds []string
+	if len(d.LicensesDiff) > 0 {
+		kinds = append(kinds, MetadataLicense)
+	}
+	if d.SupplierFrom != "" || d.SupplierTo != "" {
+		kinds = append(kinds, MetadataSupplier)
+	}
+	if len(d.Properties) > 0 {
+		kinds = append(kinds, MetadataProperty)
+	}
+	return kinds
 }
 
 // HashDiff tracks hash changes.
@@ -43,6 +73,14 @@ type DriftSummary struct {
 	VersionDrift   int `json:"version_drift"`
 	IntegrityDrift int `json:"integrity_drift"`
 	MetadataDrift  int `json:"metadata_drift"`
+	LicenseRemoved int `json:"license_removed"` // counted on top of the drift type
+
+	// Metadata drift by kind; a component can count in several.
+	LicenseDrift  int `json:"license_drift"`
+	SupplierDrift int `json:"supplier_drift"`
+	PropertyDrift int `json:"property_drift"`
+
+	SuspiciousVersionJump int `json:"suspicious_version_jump"`
 }
 
 // ChangedComponent holds a changed component with before/after state.
@@ -70,16 +108,24 @@ type PackageSamplesByType struct {
 	Samples []PackageSample `json:"samples"`
 }
 
-// DiffResult holds the complete SBOM comparison.
+// DiffResult holds the complete SBOM comparison. Every list is sorted so
+// that the same inputs marshal to the same bytes: components, duplicate
+// groups and collisions by ID, transitive dependencies by target, type
+// changes and churn by name, shadows by name then ID. Risk scores and
+// --sort-risk order by descending score, ties in that order.
 type DiffResult struct {
-	Added         []sbom.Component     `json:"added,omitempty"`
-	Removed       []sbom.Component     `json:"removed,omitempty"`
-	Changed       []ChangedComponent   `json:"changed,omitempty"`
-	Duplicates    *DuplicateReport     `json:"duplicates,omitempty"`
-	Dependencies  *DependencyDiff      `json:"dependencies,omitempty"`
-	DriftSummary  *DriftSummary        `json:"drift_summary,omitempty"`
+	Added         []sbom.Component       `json:"added,omitempty"`
+	Removed       []sbom.Component       `json:"removed,omitempty"`
+	Changed       []ChangedComponent     `json:"changed,omitempty"`
+	Duplicates    *DuplicateReport       `json:"duplicates,omitempty"`
+	Dependencies  *DependencyDiff        `json:"dependencies,omitempty"`
+	DriftSummary  *DriftSummary          `json:"drift_summary,omitempty"`
 	AddedByType   []PackageSamplesByType `json:"added_by_type,omitempty"`
 	RemovedByType []PackageSamplesByType `json:"removed_by_type,omitempty"`
+	TypeChanged   []TypeChange           `json:"type_changed,omitempty"`
+	Shadows       []CrossEcosystemShadow `json:"cross_ecosystem_shadows,omitempty"`
+	Churn         *ChurnSummary          `json:"churn,omitempty"`
+	RiskScores    []RiskScore            `json:"risk_scores,omitempty"` // set by ComputeRiskScores
 }
 
 func (h *HashDiff) IsEmpty() bool {
@@ -94,6 +140,11 @@ func ClassifyDrift(before, after sbom.Component) DriftInfo {
 	if versionChanged {
 		drift.VersionFrom = before.Version
 		drift.VersionTo = after.Version
+		if ptype := distroPackageType(after); ptype != "" {
+			drift.VersionChange = ClassifyDistroVersionChange(ptype, before.Version, after.Version)
+		} else {
+			drift.SuspiciousJump = IsSuspiciousVersionJump(before.Version, after.Version)
+		}
 	}
 
 	hashDiff := DiffHashes(before.Hashes, after.Hashes)
@@ -114,6 +165,14 @@ func ClassifyDrift(before, after sbom.Component) DriftInfo {
 				drift.LicensesDiff = append(drift.LicensesDiff, "-"+lic)
 			}
 		}
+		sort.Strings(drift.LicensesDiff)
+		drift.LicenseRemoved = len(beforeSet) > 0 && len(afterSet) == 0
+	}
+
+	drift.Properties = sbom.ChangedProperties(before.Properties, after.Properties)
+	if sbom.SupplierChanged(before.Supplier, after.Supplier) {
+		drift.SupplierFrom = before.Supplier
+		drift.SupplierTo = after.Supplier
 	}
 
 	if !hashDiff.IsEmpty() && !versionChanged {
@@ -126,7 +185,7 @@ func ClassifyDrift(before, after sbom.Component) DriftInfo {
 		return drift
 	}
 
-	if len(drift.LicensesDiff) > 0 {
+	if len(drift.MetadataKinds()) > 0 {
 		drift.Type = DriftTypeMetadata
 		return drift
 	}
@@ -134,6 +193,29 @@ func ClassifyDrift(before, after sbom.Component) DriftInfo {
 	return drift
 }
 
+// A major version leap to at least SuspiciousMajorFactor times the old
+// major, or from below SuspiciousMajorFloor to at or above it, is a
+// dependency-confusion signal: attackers publish absurd versions such as
+// 99.0.0 so that resolvers prefer them.
+const (
+	SuspiciousMajorFactor = 10
+	SuspiciousMajorFloor  = 90
+)
+
+// IsSuspiciousVersionJump reports whether from -> to is a suspicious
+// major version leap (1.x -> 99.x, 2.x -> 20.x). A 0.x major counts as 1.
+func IsSuspiciousVersionJump(from, to string) bool {
+	pf := parseVersionParts(strings.TrimPrefix(from, "v"))
+	pt := parseVersionParts(strings.TrimPrefix(to, "v"))
+	if len(pf) == 0 || len(pt) == 0 || pt[0] <= pf[0] {
+		return false
+	}
+	if pf[0] < SuspiciousMajorFloor && pt[0] >= SuspiciousMajorFloor {
+		return true
+	}
+	return pt[0] >= max(pf[0], 1)*SuspiciousMajorFactor
+}
+
 func DiffHashes(before, after map[string]string) HashDiff {
 	diff := HashDiff{
 		Added:   make(map[string]string),
@@ -160,6 +242,13 @@ func DiffHashes(before, after map[string]string) HashDiff {
 	return diff
 }
 
+// IsRoutineVersionChange reports whether c is an ordinary version bump:
+// version drift without license changes or a suspicious version jump.
+func (c ChangedComponent) IsRoutineVersionChange() bool {
+	return c.Drift != nil && c.Drift.Type == DriftTypeVersion &&
+		len(c.Drift.LicensesDiff) == 0 && !c.Drift.SuspiciousJump
+}
+
 // SummarizeDrift aggregates drift counts.
 func SummarizeDrift(changes []ChangedComponent) DriftSummary {
 	summary := DriftSummary{}
@@ -175,6 +264,22 @@ func SummarizeDrift(changes []ChangedComponent) DriftSummary {
 			summary.IntegrityDrift++
 		case DriftTypeMetadata:
 			summary.MetadataDrift++
+			for _, kind := range c.Drift.MetadataKinds() {
+				switch kind {
+				case MetadataLicense:
+					summary.LicenseDrift++
+				case MetadataSupplier:
+					summary.SupplierDrift++
+				case MetadataProperty:
+					summary.PropertyDrift++
+				}
+			}
+		}
+		if c.Drift.LicenseRemoved {
+			summary.LicenseRemoved++
+		}
+		if c.Drift.SuspiciousJump {
+			summary.SuspiciousVersionJump++
 		}
 	}
 
@@ -207,8 +312,28 @@ func EqualSlices(a, b []string) bool {
 	return true
 }
 
-// DiffComponents compares two component sets.
+// DiffComponents compares two component sets using DefaultDiffer.
 func DiffComponents(before, after []sbom.Component) DiffResult {
+	return DiffComponentsWith(before, after, DefaultDiffer{})
+}
+
+// DiffComponentsWith is DiffComponents with d deciding how components are
+// matched and compared; a nil d means DefaultDiffer. Duplicates, collisions
+// and the dependency graphs always go by identity ID.
+func DiffComponentsWith(before, after []sbom.Component, d Differ) DiffResult {
+	return diffComponents(before, after, NewGraph(before), NewGraph(after), d)
+}
+
+// DiffComponentsWithGraphs is DiffComponents reusing graphs built by
+// NewGraph, so later passes such as ComputeStatsWithGraph skip the walk.
+func DiffComponentsWithGraphs(before, after []sbom.Component, beforeGraph, afterGraph *Graph) DiffResult {
+	return diffComponents(before, after, beforeGraph, afterGraph, DefaultDiffer{})
+}
+
+func diffComponents(before, after []sbom.Component, beforeGraph, afterGraph *Graph, d Differ) DiffResult {
+	if d == nil {
+		d = DefaultDiffer{}
+	}
 	beforeDups := DetectDuplicates(before)
 	afterDups := DetectDuplicates(after)
 
@@ -216,13 +341,15 @@ func DiffComponents(before, after []sbom.Component) DiffResult {
 	afterMap := make(map[string]sbom.Component)
 
 	for _, c := range before {
-		if _, exists := beforeMap[c.ID]; !exists {
-			beforeMap[c.ID] = c
+		id := d.ID(c)
+		if _, exists := beforeMap[id]; !exists {
+			beforeMap[id] = c
 		}
 	}
 	for _, c := range after {
-		if _, exists := afterMap[c.ID]; !exists {
-			afterMap[c.ID] = c
+		id := d.ID(c)
+		if _, exists := afterMap[id]; !exists {
+			afterMap[id] = c
 		}
 	}
 
@@ -242,7 +369,7 @@ func DiffComponents(before, after []sbom.Component) DiffResult {
 
 	for id, b := range beforeMap {
 		if a, exists := afterMap[id]; exists {
-			changes := sbom.CompareComponents(b, a)
+			changes := d.Compare(b, a)
 			if len(changes) > 0 {
 				drift := ClassifyDrift(b, a)
 				result.Changed = append(result.Changed, ChangedComponent{
@@ -257,10 +384,14 @@ func DiffComponents(before, after []sbom.Component) DiffResult {
 		}
 	}
 
-	sort.Slice(result.Added, func(i, j int) bool { return result.Added[i].ID < result.Added[j].ID })
-	sort.Slice(result.Removed, func(i, j int) bool { return result.Removed[i].ID < result.Removed[j].ID })
+	sort.Slice(result.Added, func(i, j int) bool { return d.ID(result.Added[i]) < d.ID(result.Added[j]) })
+	sort.Slice(result.Removed, func(i, j int) bool { return d.ID(result.Removed[i]) < d.ID(result.Removed[j]) })
 	sort.Slice(result.Changed, func(i, j int) bool { return result.Changed[i].ID < result.Changed[j].ID })
 
+	result.TypeChanged = DetectTypeChanges(result.Removed, result.Added)
+	result.Shadows = DetectCrossEcosystemShadows(before, result.Added, result.TypeChanged)
+	result.Churn = DetectChurn(result.Removed, result.Added)
+
 	// Compute drift summary
 	if len(result.Changed) > 0 {
 		summary := SummarizeDrift(result.Changed)
@@ -280,6 +411,16 @@ func DiffComponents(before, after []sbom.Component) DiffResult {
 		}
 	}
 
+	beforeVendored := DetectVendoredDuplicates(before)
+	afterVendored := DetectVendoredDuplicates(after)
+	if len(beforeVendored) > 0 || len(afterVendored) > 0 {
+		if result.Duplicates == nil {
+			result.Duplicates = &DuplicateReport{}
+		}
+		result.Duplicates.VendoredBefore = beforeVendored
+		result.Duplicates.VendoredAfter = afterVendored
+	}
+
 	// Detect collisions in both SBOMs
 	beforeCollisions := DetectCollisions(before)
 	afterCollisions := DetectCollisions(after)
@@ -297,12 +438,11 @@ func DiffComponents(before, after []sbom.Component) DiffResult {
 				seen[key] = true
 			}
 		}
+		sortCollisions(result.Duplicates.Collisions)
 	}
 
 	// Dependency graph diff
-	beforeGraph := BuildDependencyGraph(before)
-	afterGraph := BuildDependencyGraph(after)
-	depDiff := DiffDependencyGraphs(beforeGraph, afterGraph)
+	depDiff := DiffGraphs(beforeGraph, afterGraph)
 	if !depDiff.IsEmpty() {
 		result.Dependencies = &depDiff
 	}
@@ -326,7 +466,10 @@ func groupSamplesByType(comps []sbom.Component, maxSamples int) []PackageSamples
 		types = append(types, t)
 	}
 	sort.Slice(types, func(i, j int) bool {
-		return len(typeMap[types[i]]) > len(typeMap[types[j]])
+		if len(typeMap[types[i]]) != len(typeMap[types[j]]) {
+			return len(typeMap[types[i]]) > len(typeMap[types[j]])
+		}
+		return types[i] < types[j]
 	})
 
 	var result []PackageSamplesByType
@@ -1,6 +1,9 @@
 package analysis
 
 import (
+	"bytes"
+	"encoding/json"
+	"slices"
 	"testing"
 
 	"github.com/rezmoss/sbomlyze/internal/sbom"
@@ -70,6 +73,30 @@ func TestClassifyDrift(t *testing.T) {
 		}
 	})
 
+	t.Run("metadata drift when only properties change", func(t *testing.T) {
+		before := sbom.Component{
+			ID:         "pkg:npm/lodash",
+			Name:       "lodash",
+			Version:    "4.17.20",
+			Properties: map[string]string{"build:compiler": "gcc-12", "build:ci": "true"},
+		}
+		after := sbom.Component{
+			ID:         "pkg:npm/lodash",
+			Name:       "lodash",
+			Version:    "4.17.20",
+			Properties: map[string]string{"build:compiler": "gcc-13", "build:runner": "self-hosted"},
+		}
+
+		drift := ClassifyDrift(before, after)
+
+		if drift.Type != DriftTypeMetadata {
+			t.Errorf("expected metadata drift, got %s", drift.Type)
+		}
+		if want := []string{"build:ci", "build:compiler", "build:runner"}; !slices.Equal(drift.Properties, want) {
+			t.Errorf("Properties = %v, want %v", drift.Properties, want)
+		}
+	})
+
 	t.Run("no drift when identical", func(t *testing.T) {
 		comp := sbom.Component{
 			ID:      "pkg:npm/lodash",
@@ -108,6 +135,31 @@ func TestClassifyDrift(t *testing.T) {
 			t.Errorf("expected integrity drift (most severe), got %s", drift.Type)
 		}
 	})
+
+	t.Run("license removal", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			before []string
+			after  []string
+			want   bool
+		}{
+			{"licensed to unlicensed", []string{"MIT"}, nil, true},
+			{"all of several removed", []string{"MIT", "Apache-2.0"}, []string{}, true},
+			{"unchanged", []string{"MIT"}, []string{"MIT"}, false},
+			{"replaced", []string{"MIT"}, []string{"Apache-2.0"}, false},
+			{"one of two removed", []string{"MIT", "Apache-2.0"}, []string{"MIT"}, false},
+			{"unlicensed to licensed", nil, []string{"MIT"}, false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				before := sbom.Component{Name: "lodash", Version: "4.17.20", Licenses: tt.before}
+				after := sbom.Component{Name: "lodash", Version: "4.17.20", Licenses: tt.after}
+				if got := ClassifyDrift(before, after).LicenseRemoved; got != tt.want {
+					t.Errorf("LicenseRemoved = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
 }
 
 func TestDiffComponents_CrossNamespaceRPM(t *testing.T) {
@@ -143,6 +195,54 @@ func TestDiffComponents_CrossNamespaceRPM(t *testing.T) {
 	}
 }
 
+func TestMetadataDriftKinds(t *testing.T) {
+	base := sbom.Component{
+		ID:         "pkg:npm/lodash",
+		Name:       "lodash",
+		Version:    "4.17.21",
+		Licenses:   []string{"MIT"},
+		Supplier:   "OpenJS Foundation",
+		Properties: map[string]string{"build:runner": "gha"},
+	}
+
+	tests := []struct {
+		name   string
+		change func(c *sbom.Component)
+		kinds  []string
+		want   DriftSummary
+	}{
+		{"license only", func(c *sbom.Component) { c.Licenses = []string{"Apache-2.0"} },
+			[]string{MetadataLicense}, DriftSummary{MetadataDrift: 1, LicenseDrift: 1}},
+		{"supplier only", func(c *sbom.Component) { c.Supplier = "Evil Corp" },
+			[]string{MetadataSupplier}, DriftSummary{MetadataDrift: 1, SupplierDrift: 1}},
+		{"property only", func(c *sbom.Component) { c.Properties = map[string]string{"build:runner": "jenkins"} },
+			[]string{MetadataProperty}, DriftSummary{MetadataDrift: 1, PropertyDrift: 1}},
+		{"license and supplier", func(c *sbom.Component) { c.Licenses = nil; c.Supplier = "Evil Corp" },
+			[]string{MetadataLicense, MetadataSupplier}, DriftSummary{MetadataDrift: 1, LicenseRemoved: 1, LicenseDrift: 1, SupplierDrift: 1}},
+		{"supplier case and spacing", func(c *sbom.Component) { c.Supplier = "openjs  foundation" },
+			nil, DriftSummary{}},
+		{"supplier dropped", func(c *sbom.Component) { c.Supplier = "" },
+			nil, DriftSummary{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := base
+			tt.change(&after)
+			drift := ClassifyDrift(base, after)
+			if got := drift.MetadataKinds(); !slices.Equal(got, tt.kinds) {
+				t.Errorf("MetadataKinds = %v, want %v", got, tt.kinds)
+			}
+			if len(tt.kinds) > 0 && drift.Type != DriftTypeMetadata {
+				t.Errorf("Type = %s, want metadata", drift.Type)
+			}
+			if got := SummarizeDrift([]ChangedComponent{{ID: base.ID, Drift: &drift}}); got != tt.want {
+				t.Errorf("SummarizeDrift = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestHashDiff(t *testing.T) {
 	t.Run("detects added hash", func(t *testing.T) {
 		before := map[string]string{}
@@ -191,6 +291,75 @@ func TestHashDiff(t *testing.T) {
 	})
 }
 
+func TestIsSuspiciousVersionJump(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"1.2.0", "1.3.0", false},
+		{"1.2.0", "2.0.0", false},
+		{"4.17.21", "5.0.0", false},
+		{"0.9.1", "1.0.0", false},
+		{"2.1.0", "1.0.0", false},
+		{"2023.1", "2024.1", false},
+		{"v1.0.0", "v3.0.0", false},
+		{"1.2.0", "99.0.0", true},
+		{"1.2.0", "10.0.0", true},
+		{"0.3.0", "12.0.0", true},
+		{"2.5.0", "20.0.0", true},
+		{"12.0.0", "95.0.0", true},
+		{"v1.0.0", "v100.0.0", true},
+		{"", "99.0.0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := IsSuspiciousVersionJump(tt.from, tt.to); got != tt.want {
+				t.Errorf("IsSuspiciousVersionJump(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("classified on semver packages only", func(t *testing.T) {
+		npm := ClassifyDrift(
+			sbom.Component{Name: "acme-utils", Version: "1.0.0", PURL: "pkg:npm/acme-utils@1.0.0"},
+			sbom.Component{Name: "acme-utils", Version: "99.0.0", PURL: "pkg:npm/acme-utils@99.0.0"},
+		)
+		if !npm.SuspiciousJump {
+			t.Error("expected npm 1.0.0 -> 99.0.0 to be flagged")
+		}
+		deb := ClassifyDrift(
+			sbom.Component{Name: "tzdata", Version: "1:2.0-1", PURL: "pkg:deb/debian/tzdata@1:2.0-1"},
+			sbom.Component{Name: "tzdata", Version: "1:2024a-1", PURL: "pkg:deb/debian/tzdata@1:2024a-1"},
+		)
+		if deb.SuspiciousJump {
+			t.Error("expected distro packages not to be flagged")
+		}
+	})
+}
+
+func TestIsRoutineVersionChange(t *testing.T) {
+	tests := []struct {
+		name  string
+		drift *DriftInfo
+		want  bool
+	}{
+		{"version bump", &DriftInfo{Type: DriftTypeVersion}, true},
+		{"license changed", &DriftInfo{Type: DriftTypeVersion, LicensesDiff: []string{"+MIT"}}, false},
+		{"suspicious jump", &DriftInfo{Type: DriftTypeVersion, SuspiciousJump: true}, false},
+		{"integrity", &DriftInfo{Type: DriftTypeIntegrity}, false},
+		{"metadata", &DriftInfo{Type: DriftTypeMetadata}, false},
+		{"no drift info", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ChangedComponent{Name: "pkg", Drift: tt.drift}
+			if got := c.IsRoutineVersionChange(); got != tt.want {
+				t.Errorf("IsRoutineVersionChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDriftSummary(t *testing.T) {
 	t.Run("summarizes drift by type", func(t *testing.T) {
 		changes := []ChangedComponent{
@@ -212,4 +381,70 @@ func TestDriftSummary(t *testing.T) {
 			t.Errorf("expected 1 metadata drift, got %d", summary.MetadataDrift)
 		}
 	})
+
+	t.Run("counts license removal alongside drift type", func(t *testing.T) {
+		changes := []ChangedComponent{
+			{ID: "a", Drift: &DriftInfo{Type: DriftTypeMetadata, LicenseRemoved: true}},
+			{ID: "b", Drift: &DriftInfo{Type: DriftTypeVersion, LicenseRemoved: true}},
+			{ID: "c", Drift: &DriftInfo{Type: DriftTypeMetadata}},
+		}
+
+		summary := SummarizeDrift(changes)
+
+		if summary.LicenseRemoved != 2 {
+			t.Errorf("expected 2 license removals, got %d", summary.LicenseRemoved)
+		}
+		if summary.MetadataDrift != 2 {
+			t.Errorf("expected 2 metadata drifts, got %d", summary.MetadataDrift)
+		}
+	})
+
+	t.Run("counts suspicious version jumps", func(t *testing.T) {
+		changes := []ChangedComponent{
+			{ID: "a", Drift: &DriftInfo{Type: DriftTypeVersion, SuspiciousJump: true}},
+			{ID: "b", Drift: &DriftInfo{Type: DriftTypeVersion}},
+		}
+
+		summary := SummarizeDrift(changes)
+
+		if summary.SuspiciousVersionJump != 1 || summary.VersionDrift != 2 {
+			t.Errorf("expected 1 suspicious jump among 2 version drifts, got %+v", summary)
+		}
+	})
+}
+
+func TestDiffComponents_DeterministicJSON(t *testing.T) {
+	before := []sbom.Component{
+		{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"MIT", "ISC", "BSD-3-Clause"},
+			Dependencies: []string{"pkg:npm/b"}},
+		{ID: "pkg:npm/b", Name: "b", Version: "1.0.0", Dependencies: []string{"pkg:npm/c"}},
+		{ID: "pkg:npm/c", Name: "c", Version: "1.0.0", Dependencies: []string{"pkg:npm/a"}},
+		{ID: "pkg:npm/dup", Name: "dup", Version: "1.0.0", Supplier: "Acme", Hashes: map[string]string{"SHA-256": "aaa", "SHA-1": "111"}},
+		{ID: "pkg:npm/dup", Name: "dup", Version: "1.0.0", Supplier: "Other", Hashes: map[string]string{"SHA-256": "bbb", "SHA-1": "222"}},
+		{ID: "pkg:npm/gone", Name: "gone", Version: "1.0.0"},
+	}
+	after := []sbom.Component{
+		{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"Apache-2.0", "GPL-3.0-only", "MPL-2.0"},
+			Dependencies: []string{"pkg:npm/b", "pkg:npm/d"}},
+		{ID: "pkg:npm/b", Name: "b", Version: "1.1.0", Dependencies: []string{"pkg:npm/c", "pkg:npm/e"}},
+		{ID: "pkg:npm/c", Name: "c", Version: "1.0.0", Dependencies: []string{"pkg:npm/a"}},
+		{ID: "pkg:npm/d", Name: "d", Version: "1.0.0", Dependencies: []string{"pkg:npm/f"}},
+		{ID: "pkg:npm/e", Name: "e", Version: "1.0.0"},
+		{ID: "pkg:npm/f", Name: "f", Version: "1.0.0"},
+		{ID: "pkg:pypi/gone", Name: "gone", Version: "1.0.0", PURL: "pkg:pypi/gone@1.0.0"},
+	}
+
+	want, err := json.Marshal(DiffComponents(before, after))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 20 {
+		got, err := json.Marshal(DiffComponents(before, after))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("run %d marshaled differently:\n%s\nvs\n%s", i, got, want)
+		}
+	}
 }
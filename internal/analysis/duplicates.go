@@ -1,7 +1,10 @@
 package analysis
 
 import (
+	"maps"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/rezmoss/sbomlyze/internal/sbom"
 )
@@ -12,6 +15,10 @@ type DuplicateReport struct {
 	After       []DuplicateGroup      `json:"after,omitempty"`
 	VersionDiff *DuplicateVersionDiff `json:"version_diff,omitempty"`
 	Collisions  []Collision           `json:"collisions,omitempty"`
+
+	// vendored copies, kept out of Before/After
+	VendoredBefore []VendoredDuplicate `json:"vendored_before,omitempty"`
+	VendoredAfter  []VendoredDuplicate `json:"vendored_after,omitempty"`
 }
 
 // DuplicateGroup is a set of components sharing an ID.
@@ -19,6 +26,8 @@ type DuplicateGroup struct {
 	ID         string           `json:"id"`
 	Name       string           `json:"name"`
 	Versions   []string         `json:"versions"`
+	Counts     map[string]int   `json:"counts"`          // entries per version
+	Exact      bool             `json:"exact,omitempty"` // some version is listed more than once
 	Components []sbom.Component `json:"components"`
 }
 
@@ -30,6 +39,17 @@ type DuplicateVersionDiff struct {
 	ResolvedDuplicates []DuplicateGroup    `json:"resolved_duplicates,omitempty"`
 }
 
+// VendoredDuplicate is a package listed again as a vendored copy: same
+// name and version, with a PURL that differs only by subpath (e.g.
+// #vendor/... or #node_modules/...).
+type VendoredDuplicate struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Version    string           `json:"version"`
+	Subpaths   []string         `json:"subpaths"`   // of the vendored copies
+	Components []sbom.Component `json:"components"` // the kept entry first, preferring one without a subpath
+}
+
 // Collision is an ambiguous identity match.
 type Collision struct {
 	ID         string           `json:"id"`
@@ -44,7 +64,9 @@ func (d *DuplicateVersionDiff) IsEmpty() bool {
 		len(d.ResolvedDuplicates) == 0
 }
 
-// DetectDuplicates finds same-ID components with different versions.
+// DetectDuplicates finds components sharing an ID. A group whose entries
+// repeat the same version is marked Exact; that usually points to an
+// SBOM generation bug rather than two installed versions.
 func DetectDuplicates(comps []sbom.Component) []DuplicateGroup {
 	groups := make(map[string][]sbom.Component)
 	for _, c := range comps {
@@ -53,20 +75,26 @@ func DetectDuplicates(comps []sbom.Component) []DuplicateGroup {
 
 	var dups []DuplicateGroup
 	for id, components := range groups {
+		components, _ = splitVendored(components)
 		if len(components) > 1 {
 			versions := make([]string, 0, len(components))
-			seen := make(map[string]bool)
+			counts := make(map[string]int)
+			exact := false
 			for _, c := range components {
-				if !seen[c.Version] {
+				if counts[c.Version] == 0 {
 					versions = append(versions, c.Version)
-					seen[c.Version] = true
+				} else {
+					exact = true
 				}
+				counts[c.Version]++
 			}
 			sort.Strings(versions)
 			dups = append(dups, DuplicateGroup{
 				ID:         id,
 				Name:       components[0].Name,
 				Versions:   versions,
+				Counts:     counts,
+				Exact:      exact,
 				Components: components,
 			})
 		}
@@ -75,6 +103,106 @@ func DetectDuplicates(comps []sbom.Component) []DuplicateGroup {
 	return dups
 }
 
+// DetectVendoredDuplicates finds vendored copies, which DetectDuplicates
+// leaves out of its groups.
+func DetectVendoredDuplicates(comps []sbom.Component) []VendoredDuplicate {
+	groups := make(map[string][]sbom.Component)
+	for _, c := range comps {
+		groups[c.ID] = append(groups[c.ID], c)
+	}
+
+	var vendored []VendoredDuplicate
+	for _, components := range groups {
+		if len(components) < 2 {
+			continue
+		}
+		kept, copies := splitVendored(components)
+		for _, k := range kept {
+			v := VendoredDuplicate{ID: k.ID, Name: k.Name, Version: k.Version, Components: []sbom.Component{k}}
+			for _, c := range copies {
+				if isVendoredCopy(k, c) {
+					v.Subpaths = append(v.Subpaths, purlSubpath(c.PURL))
+					v.Components = append(v.Components, c)
+				}
+			}
+			if len(v.Subpaths) > 0 {
+				vendored = append(vendored, v)
+			}
+		}
+	}
+	sort.Slice(vendored, func(i, j int) bool {
+		if vendored[i].ID != vendored[j].ID {
+			return vendored[i].ID < vendored[j].ID
+		}
+		return vendored[i].Version < vendored[j].Version
+	})
+	return vendored
+}
+
+// splitVendored separates vendored copies from a same-ID group. Of entries
+// that differ only by PURL subpath, the one without a subpath is kept.
+func splitVendored(components []sbom.Component) (kept, copies []sbom.Component) {
+	for _, c := range components {
+		i := slices.IndexFunc(kept, func(k sbom.Component) bool { return isVendoredCopy(k, c) })
+		switch {
+		case i < 0:
+			kept = append(kept, c)
+		case purlSubpath(kept[i].PURL) != "" && purlSubpath(c.PURL) == "":
+			copies = append(copies, kept[i])
+			kept[i] = c
+		default:
+			copies = append(copies, c)
+		}
+	}
+	return kept, copies
+}
+
+// isVendoredCopy reports whether a and b are the same package and version
+// with PURLs differing only by subpath.
+func isVendoredCopy(a, b sbom.Component) bool {
+	if a.Name != b.Name || a.Version != b.Version || a.PURL == b.PURL {
+		return false
+	}
+	baseA, subA, _ := strings.Cut(a.PURL, "#")
+	baseB, subB, _ := strings.Cut(b.PURL, "#")
+	return baseA == baseB && (subA != "" || subB != "")
+}
+
+func purlSubpath(purl string) string {
+	_, sub, _ := strings.Cut(purl, "#")
+	return sub
+}
+
+// DroppedConflict is a same-ID, same-version entry the diff discards
+// although its licenses or hashes differ from the entry it keeps.
+type DroppedConflict struct {
+	Kept    sbom.Component
+	Dropped sbom.Component
+	Changes []string // as reported by sbom.CompareComponents
+}
+
+// DetectDroppedConflicts finds entries the diff drops in favour of the
+// first component with the same ID whose metadata differs from it.
+// Entries with another version are left to the duplicates report.
+func DetectDroppedConflicts(comps []sbom.Component) []DroppedConflict {
+	kept := make(map[string]sbom.Component)
+	var conflicts []DroppedConflict
+	for _, c := range comps {
+		k, ok := kept[c.ID]
+		if !ok {
+			kept[c.ID] = c
+			continue
+		}
+		if k.Version != c.Version {
+			continue
+		}
+		if changes := sbom.CompareComponents(k, c); len(changes) > 0 {
+			conflicts = append(conflicts, DroppedConflict{Kept: k, Dropped: c, Changes: changes})
+		}
+	}
+	return conflicts
+}
+
 // DiffDuplicateVersions compares duplicate groups.
 func DiffDuplicateVersions(before, after []DuplicateGroup) DuplicateVersionDiff {
 	diff := DuplicateVersionDiff{
@@ -170,7 +298,8 @@ func DetectCollisions(comps []sbom.Component) []Collision {
 			if _, exists := versionHashes[c.Version]; !exists {
 				versionHashes[c.Version] = make(map[string]string)
 			}
-			for algo, hash := range c.Hashes {
+			for _, algo := range slices.Sorted(maps.Keys(c.Hashes)) {
+				hash := c.Hashes[algo]
 				if existing, ok := versionHashes[c.Version][algo]; ok && existing != hash {
 					collisions = append(collisions, Collision{
 						ID:         id,
@@ -182,10 +311,34 @@ func DetectCollisions(comps []sbom.Component) []Collision {
 				versionHashes[c.Version][algo] = hash
 			}
 		}
+
+		// empty suppliers are missing data, not a disagreement
+		suppliers := make(map[string]bool)
+		for _, c := range components {
+			if c.Supplier != "" {
+				suppliers[c.Supplier] = true
+			}
+		}
+		if len(suppliers) > 1 {
+			collisions = append(collisions, Collision{
+				ID:         id,
+				Reason:     "supplier_mismatch",
+				Components: components,
+			})
+		}
 	}
 
-	sort.Slice(collisions, func(i, j int) bool {
-		return collisions[i].ID < collisions[j].ID
-	})
+	sortCollisions(collisions)
 	return collisions
 }
+
+// sortCollisions orders collisions by ID, then reason. The sort is stable
+// so repeated hash mismatches of one ID keep their input order.
+func sortCollisions(collisions []Collision) {
+	sort.SliceStable(collisions, func(i, j int) bool {
+		if collisions[i].ID != collisions[j].ID {
+			return collisions[i].ID < collisions[j].ID
+		}
+		return collisions[i].Reason < collisions[j].Reason
+	})
+}
@@ -1,6 +1,8 @@
 package analysis
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/rezmoss/sbomlyze/internal/sbom"
@@ -29,6 +31,34 @@ func TestDetectDuplicates(t *testing.T) {
 		}
 	})
 
+	t.Run("same version twice is an exact duplicate", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21"},
+			{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21"},
+			{ID: "pkg:npm/express", Name: "express", Version: "4.18.0"},
+			{ID: "pkg:npm/express", Name: "express", Version: "4.18.1"},
+		}
+
+		dups := DetectDuplicates(comps)
+
+		if len(dups) != 2 {
+			t.Fatalf("expected 2 duplicate groups, got %d", len(dups))
+		}
+		express, lodash := dups[0], dups[1]
+		if !lodash.Exact {
+			t.Error("expected lodash group to be exact")
+		}
+		if len(lodash.Versions) != 1 || lodash.Counts["4.17.21"] != 2 {
+			t.Errorf("expected one version listed twice, got versions %v counts %v", lodash.Versions, lodash.Counts)
+		}
+		if express.Exact {
+			t.Error("expected express group with two versions not to be exact")
+		}
+		if express.Counts["4.18.0"] != 1 || express.Counts["4.18.1"] != 1 {
+			t.Errorf("unexpected express counts %v", express.Counts)
+		}
+	})
+
 	t.Run("no duplicates when all unique", func(t *testing.T) {
 		comps := []sbom.Component{
 			{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21"},
@@ -205,4 +235,151 @@ func TestDetectCollisions(t *testing.T) {
 			t.Errorf("expected hash_mismatch reason, got %s", collisions[0].Reason)
 		}
 	})
+
+	t.Run("detects collision with different suppliers", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Supplier: "Acme Corp"},
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.1", Supplier: "Evil Corp"},
+		}
+
+		collisions := DetectCollisions(comps)
+
+		if len(collisions) != 1 {
+			t.Fatalf("expected 1 collision for different suppliers, got %d", len(collisions))
+		}
+		if collisions[0].Reason != "supplier_mismatch" {
+			t.Errorf("expected supplier_mismatch reason, got %s", collisions[0].Reason)
+		}
+	})
+
+	t.Run("no supplier collision when a supplier is empty", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Supplier: "Acme Corp"},
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.1"},
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.2", Supplier: "Acme Corp"},
+		}
+
+		if collisions := DetectCollisions(comps); len(collisions) != 0 {
+			t.Errorf("expected no collisions, got %+v", collisions)
+		}
+	})
+}
+
+func TestDetectDroppedConflicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		comps []sbom.Component
+		want  []string // changes for each conflict, joined
+	}{
+		{"conflicting licenses and hashes", []sbom.Component{
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"MIT"}, Hashes: map[string]string{"SHA256": "abc"}},
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"GPL-3.0-only"}, Hashes: map[string]string{"SHA256": "def"}},
+		}, []string{"licenses: [MIT] -> [GPL-3.0-only]; hash[SHA256]: abc -> def"}},
+		{"identical entries", []sbom.Component{
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"MIT"}},
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"MIT"}},
+		}, nil},
+		{"other version is left to the duplicates report", []sbom.Component{
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"MIT"}},
+			{ID: "pkg:npm/a", Name: "a", Version: "2.0.0", Licenses: []string{"ISC"}},
+		}, nil},
+		{"compared against the first entry", []sbom.Component{
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"MIT"}},
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"MIT"}},
+			{ID: "pkg:npm/a", Name: "a", Version: "1.0.0", Licenses: []string{"ISC"}},
+		}, []string{"licenses: [MIT] -> [ISC]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := DetectDroppedConflicts(tt.comps)
+			if len(conflicts) != len(tt.want) {
+				t.Fatalf("expected %d conflicts, got %+v", len(tt.want), conflicts)
+			}
+			for i, c := range conflicts {
+				if got := strings.Join(c.Changes, "; "); got != tt.want[i] {
+					t.Errorf("conflict %d changes = %q, want %q", i, got, tt.want[i])
+				}
+				if c.Kept.Licenses[0] != tt.comps[0].Licenses[0] {
+					t.Errorf("expected the first entry to be kept, got %+v", c.Kept)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectVendoredDuplicates(t *testing.T) {
+	const purl = "pkg:golang/github.com/pkg/errors@v0.9.1"
+	errs := func(purl, version string) sbom.Component {
+		return sbom.Component{ID: "pkg:golang/github.com/pkg/errors", Name: "github.com/pkg/errors", Version: version, PURL: purl}
+	}
+
+	tests := []struct {
+		name         string
+		comps        []sbom.Component
+		wantSubpaths []string // of the single vendored group, if any
+		wantDups     int
+	}{
+		{"vendor subpath", []sbom.Component{
+			errs(purl, "v0.9.1"),
+			errs(purl+"#vendor/github.com/pkg/errors", "v0.9.1"),
+		}, []string{"vendor/github.com/pkg/errors"}, 0},
+		{"resolved entry listed last", []sbom.Component{
+			errs(purl+"#vendor/github.com/pkg/errors", "v0.9.1"),
+			errs(purl, "v0.9.1"),
+		}, []string{"vendor/github.com/pkg/errors"}, 0},
+		{"differing only by subpath", []sbom.Component{
+			errs(purl+"#a", "v0.9.1"),
+			errs(purl+"#b", "v0.9.1"),
+		}, []string{"b"}, 0},
+		{"other version is a genuine duplicate", []sbom.Component{
+			errs(purl, "v0.9.1"),
+			errs("pkg:golang/github.com/pkg/errors@v0.8.0#vendor/github.com/pkg/errors", "v0.8.0"),
+		}, nil, 1},
+		{"identical PURLs are exact duplicates", []sbom.Component{
+			errs(purl, "v0.9.1"),
+			errs(purl, "v0.9.1"),
+		}, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendored := DetectVendoredDuplicates(tt.comps)
+			if tt.wantSubpaths == nil {
+				if len(vendored) != 0 {
+					t.Errorf("expected no vendored copies, got %+v", vendored)
+				}
+			} else {
+				if len(vendored) != 1 {
+					t.Fatalf("expected 1 vendored group, got %+v", vendored)
+				}
+				if !reflect.DeepEqual(vendored[0].Subpaths, tt.wantSubpaths) {
+					t.Errorf("subpaths = %v, want %v", vendored[0].Subpaths, tt.wantSubpaths)
+				}
+				if kept := vendored[0].Components[0].PURL; strings.Contains(kept, "#vendor") {
+					t.Errorf("expected the resolved entry first, got %s", kept)
+				}
+			}
+			if dups := DetectDuplicates(tt.comps); len(dups) != tt.wantDups {
+				t.Errorf("expected %d duplicate groups, got %+v", tt.wantDups, dups)
+			}
+		})
+	}
+}
+
+func TestDiffComponents_VendoredCopies(t *testing.T) {
+	resolved := sbom.Component{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21", PURL: "pkg:npm/lodash@4.17.21"}
+	vendored := resolved
+	vendored.PURL += "#node_modules/app/node_modules/lodash"
+
+	result := DiffComponents([]sbom.Component{resolved}, []sbom.Component{resolved, vendored})
+	if result.Duplicates == nil {
+		t.Fatal("expected a duplicates report")
+	}
+	if len(result.Duplicates.After) != 0 {
+		t.Errorf("expected the vendored copy not to count as a duplicate, got %+v", result.Duplicates.After)
+	}
+	if len(result.Duplicates.VendoredAfter) != 1 || len(result.Duplicates.VendoredBefore) != 0 {
+		t.Errorf("expected one vendored copy after, got %+v", result.Duplicates)
+	}
 }
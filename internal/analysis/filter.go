@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Diff categories accepted by --only.
+const (
+	CategoryAdded      = "added"
+	CategoryRemoved    = "removed"
+	CategoryChanged    = "changed"
+	CategoryIntegrity  = "integrity"
+	CategoryDeps       = "deps"
+	CategoryDuplicates = "duplicates"
+)
+
+var diffCategories = []string{
+	CategoryAdded, CategoryRemoved, CategoryChanged,
+	CategoryIntegrity, CategoryDeps, CategoryDuplicates,
+}
+
+// ValidateCategories rejects names FilterCategories does not know.
+func ValidateCategories(cats []string) error {
+	for _, c := range cats {
+		if !slices.Contains(diffCategories, c) {
+			return fmt.Errorf("unknown category %q (valid: %s)", c, strings.Join(diffCategories, ", "))
+		}
+	}
+	return nil
+}
+
+// FilterCategories returns a copy of result with only the given categories
+// kept, for display. "integrity" keeps only changed components with
+// integrity drift. Risk scores are kept for the components kept. An empty
+// list keeps everything.
+func FilterCategories(result DiffResult, cats []string) DiffResult {
+	if len(cats) == 0 {
+		return result
+	}
+	has := func(c string) bool { return slices.Contains(cats, c) }
+
+	var out DiffResult
+	if has(CategoryAdded) {
+		out.Added = result.Added
+		out.AddedByType = result.AddedByType
+		out.Shadows = result.Shadows
+	}
+	if has(CategoryRemoved) {
+		out.Removed = result.Removed
+		out.RemovedByType = result.RemovedByType
+	}
+	if has(CategoryAdded) || has(CategoryRemoved) {
+		out.TypeChanged = result.TypeChanged
+		out.Churn = result.Churn
+	}
+	switch {
+	case has(CategoryChanged):
+		out.Changed = result.Changed
+		out.DriftSummary = result.DriftSummary
+	case has(CategoryIntegrity):
+		for _, c := range result.Changed {
+			if c.Drift != nil && c.Drift.Type == DriftTypeIntegrity {
+				out.Changed = append(out.Changed, c)
+			}
+		}
+		out.DriftSummary = result.DriftSummary
+	}
+	for _, s := range result.RiskScores {
+		switch s.Status {
+		case CategoryAdded:
+			if has(CategoryAdded) {
+				out.RiskScores = append(out.RiskScores, s)
+			}
+		case CategoryChanged:
+			if slices.ContainsFunc(out.Changed, func(c ChangedComponent) bool { return c.ID == s.ID }) {
+				out.RiskScores = append(out.RiskScores, s)
+			}
+		}
+	}
+	if has(CategoryDeps) {
+		out.Dependencies = result.Dependencies
+	}
+	if has(CategoryDuplicates) {
+		out.Duplicates = result.Duplicates
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package analysis
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestFilterCategories(t *testing.T) {
+	result := DiffResult{
+		Added:   []sbom.Component{{Name: "a"}},
+		Removed: []sbom.Component{{Name: "r"}},
+		Changed: []ChangedComponent{
+			{Name: "v", Drift: &DriftInfo{Type: DriftTypeVersion}},
+			{Name: "i", Drift: &DriftInfo{Type: DriftTypeIntegrity}},
+		},
+		DriftSummary: &DriftSummary{VersionDrift: 1, IntegrityDrift: 1},
+		Dependencies: &DependencyDiff{},
+		Duplicates:   &DuplicateReport{},
+	}
+
+	tests := []struct {
+		name                     string
+		cats                     []string
+		added, removed, changed  int
+		deps, dups, driftSummary bool
+	}{
+		{"no filter", nil, 1, 1, 2, true, true, true},
+		{"added", []string{"added"}, 1, 0, 0, false, false, false},
+		{"integrity", []string{"integrity"}, 0, 0, 1, false, false, true},
+		{"changed wins over integrity", []string{"integrity", "changed"}, 0, 0, 2, false, false, true},
+		{"deps and duplicates", []string{"deps", "duplicates"}, 0, 0, 0, true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterCategories(result, tt.cats)
+			if len(got.Added) != tt.added || len(got.Removed) != tt.removed || len(got.Changed) != tt.changed {
+				t.Errorf("added/removed/changed = %d/%d/%d, want %d/%d/%d",
+					len(got.Added), len(got.Removed), len(got.Changed), tt.added, tt.removed, tt.changed)
+			}
+			if (got.Dependencies != nil) != tt.deps || (got.Duplicates != nil) != tt.dups || (got.DriftSummary != nil) != tt.driftSummary {
+				t.Errorf("deps=%v dups=%v drift=%v, want %v %v %v",
+					got.Dependencies != nil, got.Duplicates != nil, got.DriftSummary != nil, tt.deps, tt.dups, tt.driftSummary)
+			}
+		})
+	}
+}
+
+func TestFilterCategories_RiskScores(t *testing.T) {
+	result := DiffResult{
+		Added: []sbom.Component{{ID: "a", Name: "a"}},
+		Changed: []ChangedComponent{
+			{ID: "v", Name: "v", Drift: &DriftInfo{Type: DriftTypeVersion}},
+			{ID: "i", Name: "i", Drift: &DriftInfo{Type: DriftTypeIntegrity}},
+		},
+		RiskScores: []RiskScore{
+			{ID: "i", Status: CategoryChanged, Score: 50},
+			{ID: "a", Status: CategoryAdded, Score: 10},
+			{ID: "v", Status: CategoryChanged, Score: 5},
+		},
+	}
+
+	tests := []struct {
+		name string
+		cats []string
+		want []string
+	}{
+		{"added", []string{"added"}, []string{"a"}},
+		{"changed", []string{"changed"}, []string{"i", "v"}},
+		{"integrity", []string{"integrity"}, []string{"i"}},
+		{"removed", []string{"removed"}, nil},
+		{"no filter", nil, []string{"i", "a", "v"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range FilterCategories(result, tt.cats).RiskScores {
+				got = append(got, s.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("risk scores = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCategories(t *testing.T) {
+	if err := ValidateCategories([]string{"added", "integrity", "deps"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateCategories([]string{"bogus"}); err == nil {
+		t.Error("expected error for unknown category")
+	}
+}
@@ -251,6 +251,7 @@ func ComputeKeyFindings(result DiffResult, overview DiffOverview) KeyFindings {
 	findings = append(findings, detectOSChange(overview)...)
 	findings = append(findings, detectVersionChangeAnalysis(result, overview)...)
 	findings = append(findings, detectIntegrityDriftContext(result)...)
+	findings = append(findings, detectCrossEcosystemShadowing(result)...)
 	findings = append(findings, detectDominantPathPattern(result)...)
 	findings = append(findings, detectRemovalHotspots(result)...)
 	findings = append(findings, detectStableTypes(overview)...)
@@ -507,6 +508,11 @@ func compareVersions(from, to string) int {
 	return 0
 }
 
+// IsDowngrade reports whether to is a lower version than from.
+func IsDowngrade(from, to string) bool {
+	return compareVersions(from, to) < 0
+}
+
 func classifySemVerChange(from, to string) string {
 	pf := parseVersionParts(from)
 	pt := parseVersionParts(to)
@@ -751,6 +757,23 @@ func detectIntegrityDriftContext(result DiffResult) []Finding {
 	}}
 }
 
+func detectCrossEcosystemShadowing(result DiffResult) []Finding {
+	if len(result.Shadows) == 0 {
+		return nil
+	}
+	parts := make([]string, 0, min(len(result.Shadows), 3))
+	for _, s := range result.Shadows[:min(len(result.Shadows), 3)] {
+		parts = append(parts, fmt.Sprintf("%s (%s, was %s)", s.Name, s.Type, strings.Join(s.ExistingTypes, "/")))
+	}
+	if n := len(result.Shadows) - len(parts); n > 0 {
+		parts = append(parts, fmt.Sprintf("+%d more", n))
+	}
+	return []Finding{{
+		Icon:    "\u26a0\ufe0f",
+		Message: fmt.Sprintf("%d added packages reuse a name from another ecosystem, possible dependency confusion: %s", len(result.Shadows), strings.Join(parts, ", ")),
+	}}
+}
+
 func detectLicenseCategoryShift(overview DiffOverview) []Finding {
 	bLC := overview.Before.Stats.LicenseCategories
 	aLC := overview.After.Stats.LicenseCategories
@@ -0,0 +1,31 @@
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+)
+
+// Fingerprint returns a stable hash of the diff: the added and removed
+// components (ID and version) and the changes of each changed component.
+// Diffs with the same content share a fingerprint whatever their order,
+// so CI can tell when a run reports the same diff as the last one.
+func (r DiffResult) Fingerprint() string {
+	lines := make([]string, 0, len(r.Added)+len(r.Removed)+len(r.Changed))
+	for _, c := range r.Added {
+		lines = append(lines, "+\t"+c.ID+"\t"+c.Version)
+	}
+	for _, c := range r.Removed {
+		lines = append(lines, "-\t"+c.ID+"\t"+c.Version)
+	}
+	for _, c := range r.Changed {
+		changes := slices.Clone(c.Changes)
+		slices.Sort(changes)
+		lines = append(lines, "~\t"+c.ID+"\t"+strings.Join(changes, "\x1f"))
+	}
+	slices.Sort(lines)
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
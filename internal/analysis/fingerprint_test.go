@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestDiffResult_Fingerprint(t *testing.T) {
+	base := func() DiffResult {
+		return DiffResult{
+			Added:   []sbom.Component{{ID: "pkg:npm/react", Version: "18.2.0"}, {ID: "pkg:npm/vue", Version: "3.4.0"}},
+			Removed: []sbom.Component{{ID: "pkg:npm/angular", Version: "17.0.0"}},
+			Changed: []ChangedComponent{{ID: "pkg:npm/lodash", Changes: []string{"version: 4.17.20 -> 4.17.21", "hashes changed"}}},
+		}
+	}
+
+	want := base().Fingerprint()
+	if len(want) != 64 {
+		t.Fatalf("expected a sha256 hex digest, got %q", want)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *DiffResult)
+		same   bool
+	}{
+		{"identical", func(r *DiffResult) {}, true},
+		{"reordered", func(r *DiffResult) {
+			r.Added[0], r.Added[1] = r.Added[1], r.Added[0]
+			r.Changed[0].Changes = []string{"hashes changed", "version: 4.17.20 -> 4.17.21"}
+		}, true},
+		{"display-only fields", func(r *DiffResult) {
+			r.Added[0].Name = "React"
+			r.DriftSummary = &DriftSummary{VersionDrift: 1}
+		}, true},
+		{"added version", func(r *DiffResult) { r.Added[0].Version = "18.3.0" }, false},
+		{"extra removal", func(r *DiffResult) {
+			r.Removed = append(r.Removed, sbom.Component{ID: "pkg:npm/jquery", Version: "3.7.1"})
+		}, false},
+		{"different change", func(r *DiffResult) { r.Changed[0].Changes = []string{"version: 4.17.20 -> 4.17.21"} }, false},
+		{"added moved to removed", func(r *DiffResult) {
+			r.Removed = append(r.Removed, r.Added[1])
+			r.Added = r.Added[:1]
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := base()
+			tt.modify(&r)
+			if got := r.Fingerprint(); (got == want) != tt.same {
+				t.Errorf("Fingerprint() = %s, base %s, want same=%v", got, want, tt.same)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// Graph is a dependency graph that caches the traversals shared by the
+// diff and stats passes, so each SBOM's graph is built and walked once.
+// Not safe for concurrent use.
+type Graph struct {
+	Edges map[string][]string // component ID -> dependency IDs
+
+	roots  []string
+	depths map[string]map[string]int
+	fans   map[string]FanCount
+}
+
+// NewGraph builds the dependency graph of comps.
+func NewGraph(comps []sbom.Component) *Graph {
+	return newGraph(BuildDependencyGraph(comps))
+}
+
+func newGraph(edges map[string][]string) *Graph {
+	return &Graph{Edges: edges, depths: make(map[string]map[string]int)}
+}
+
+// Roots returns the sorted nodes nothing depends on, or every node when
+// each sits on a cycle.
+func (g *Graph) Roots() []string {
+	if g.roots == nil {
+		g.roots = FindRoots(g.Edges)
+		if len(g.roots) == 0 {
+			for node := range g.Edges {
+				g.roots = append(g.roots, node)
+			}
+			sort.Strings(g.roots)
+		}
+	}
+	return g.roots
+}
+
+// Depths returns the hop distance from start to every node it reaches,
+// including start itself at 0.
+func (g *Graph) Depths(start string) map[string]int {
+	d, ok := g.depths[start]
+	if !ok {
+		d = bfsDepths(g.Edges, start)
+		g.depths[start] = d
+	}
+	return d
+}
+
+// Reaches reports whether target is reachable from start in one or more hops.
+func (g *Graph) Reaches(start, target string) bool {
+	return g.Depths(start)[target] > 0
+}
+
+// FanInOut returns per-ID fan-in and fan-out.
+func (g *Graph) FanInOut() map[string]FanCount {
+	if g.fans == nil {
+		g.fans = ComputeFanInOut(g.Edges)
+	}
+	return g.fans
+}
+
+// Depth returns the deepest and average shortest distance from a root
+// over all non-root nodes. Graphs without edges have depth 0.
+func (g *Graph) Depth() (int, float64) {
+	shortest := make(map[string]int)
+	for _, root := range g.Roots() {
+		for node, d := range g.Depths(root) {
+			if d == 0 {
+				continue
+			}
+			if prev, ok := shortest[node]; !ok || d < prev {
+				shortest[node] = d
+			}
+		}
+	}
+
+	if len(shortest) == 0 {
+		return 0, 0
+	}
+
+	maxDepth, total := 0, 0
+	for _, d := range shortest {
+		total += d
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	return maxDepth, float64(total) / float64(len(shortest))
+}
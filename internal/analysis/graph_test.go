@@ -0,0 +1,109 @@
+package analysis
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestGraph(t *testing.T) {
+	graph := map[string][]string{
+		"app": {"a", "b"},
+		"a":   {"c"},
+		"b":   {"c"},
+		"c":   {"a"}, // cycle back into a
+	}
+	g := newGraph(graph)
+
+	if roots := g.Roots(); !reflect.DeepEqual(roots, []string{"app"}) {
+		t.Errorf("Roots() = %v, want [app]", roots)
+	}
+	reach := []struct {
+		start, target string
+		want          bool
+	}{
+		{"app", "c", true},
+		{"c", "b", false},
+		{"app", "app", false},
+		{"a", "a", false}, // like bfsReachable, the start is never its own dep
+	}
+	for _, r := range reach {
+		if got := g.Reaches(r.start, r.target); got != r.want {
+			t.Errorf("Reaches(%s, %s) = %v, want %v", r.start, r.target, got, r.want)
+		}
+	}
+
+	maxDepth, avgDepth := g.Depth()
+	wantMax, wantAvg := ComputeGraphDepth(graph)
+	if maxDepth != wantMax || avgDepth != wantAvg {
+		t.Errorf("Depth() = %d, %v, want %d, %v", maxDepth, avgDepth, wantMax, wantAvg)
+	}
+
+	// the traversal is cached: the same map comes back
+	first := g.Depths("app")
+	first["marker"] = 99
+	if g.Depths("app")["marker"] != 99 {
+		t.Error("expected Depths to reuse the cached traversal")
+	}
+}
+
+func TestDiffComponentsWithGraphs(t *testing.T) {
+	before, after := benchGraphComponents(200, false), benchGraphComponents(200, true)
+	want := DiffComponents(before, after)
+
+	beforeGraph, afterGraph := NewGraph(before), NewGraph(after)
+	got := DiffComponentsWithGraphs(before, after, beforeGraph, afterGraph)
+	if !reflect.DeepEqual(got, want) {
+		t.Error("expected the same result as DiffComponents")
+	}
+	if !reflect.DeepEqual(ComputeStatsWithGraph(after, afterGraph), ComputeStats(after)) {
+		t.Error("expected the same stats with a shared graph")
+	}
+}
+
+// benchGraphComponents returns n components forming a binary tree under
+// pkg-0. With moved set, the subtree under pkg-1 hangs off pkg-2 instead.
+func benchGraphComponents(n int, moved bool) []sbom.Component {
+	comps := make([]sbom.Component, n)
+	for i := range comps {
+		comps[i] = sbom.Component{ID: fmt.Sprintf("pkg:npm/pkg-%d", i), Name: fmt.Sprintf("pkg-%d", i), Version: "1.0.0"}
+	}
+	for i := range comps {
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < n {
+				comps[i].Dependencies = append(comps[i].Dependencies, comps[child].ID)
+			}
+		}
+	}
+	if moved && n > 3 {
+		comps[0].Dependencies = []string{comps[2].ID}
+		comps[2].Dependencies = append(comps[2].Dependencies, comps[1].ID)
+	}
+	return comps
+}
+
+// BenchmarkDiffAndStats compares the diff plus both sides' stats with
+// each pass building its own graph against passes sharing one per SBOM.
+func BenchmarkDiffAndStats(b *testing.B) {
+	before, after := benchGraphComponents(5000, false), benchGraphComponents(5000, true)
+
+	b.Run("separate", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			DiffComponents(before, after)
+			ComputeStats(before)
+			ComputeStats(after)
+		}
+	})
+	b.Run("shared", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			beforeGraph, afterGraph := NewGraph(before), NewGraph(after)
+			DiffComponentsWithGraphs(before, after, beforeGraph, afterGraph)
+			ComputeStatsWithGraph(before, beforeGraph)
+			ComputeStatsWithGraph(after, afterGraph)
+		}
+	})
+}
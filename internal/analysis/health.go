@@ -0,0 +1,108 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// HealthBaseline is a --baseline-stats file: the stats of a prior run,
+// minimum coverage thresholds, or both.
+type HealthBaseline struct {
+	// Stats is a prior run, as in the "stats" of --format json output.
+	Stats *Stats `json:"stats,omitempty"`
+	// MinCoverage holds hard floors; a zero metric is not checked.
+	MinCoverage *CoveragePercent `json:"min_coverage,omitempty"`
+	// Tolerance is how many percentage points a metric may drop below
+	// the prior run. Thresholds are hard.
+	Tolerance float64 `json:"tolerance,omitempty"`
+}
+
+// Health check kinds.
+const (
+	HealthKindBaseline  = "baseline"
+	HealthKindThreshold = "threshold"
+)
+
+// HealthCheck compares one coverage metric against a baseline.
+type HealthCheck struct {
+	Metric    string  `json:"metric"`   // CoveragePercent JSON name, e.g. "license_percent"
+	Kind      string  `json:"kind"`     // HealthKindBaseline or HealthKindThreshold
+	Expected  float64 `json:"expected"` // the prior value or the minimum
+	Current   float64 `json:"current"`
+	Regressed bool    `json:"regressed"`
+}
+
+// ParseHealthBaseline parses a --baseline-stats file.
+func ParseHealthBaseline(data []byte) (HealthBaseline, error) {
+	var b HealthBaseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return HealthBaseline{}, err
+	}
+	if b.Stats == nil && b.MinCoverage == nil {
+		return HealthBaseline{}, errors.New(`neither "stats" nor "min_coverage" is set`)
+	}
+	if b.Tolerance < 0 {
+		return HealthBaseline{}, fmt.Errorf("tolerance %g is negative", b.Tolerance)
+	}
+	return b, nil
+}
+
+// coverageMetrics lists the coverage percentages by JSON name, in output order.
+func coverageMetrics(c CoveragePercent) []struct {
+	name  string
+	value float64
+} {
+	return []struct {
+		name  string
+		value float64
+	}{
+		{"purl_percent", c.PURL},
+		{"license_percent", c.License},
+		{"hash_percent", c.Hash},
+		{"cpe_percent", c.CPE},
+	}
+}
+
+// CheckHealth compares current's coverage against b: every metric against
+// the prior run, then each non-zero threshold.
+func CheckHealth(current Stats, b HealthBaseline) []HealthCheck {
+	now := coverageMetrics(current.CoveragePercent)
+	var checks []HealthCheck
+	if b.Stats != nil {
+		for i, m := range coverageMetrics(b.Stats.CoveragePercent) {
+			checks = append(checks, HealthCheck{
+				Metric:    m.name,
+				Kind:      HealthKindBaseline,
+				Expected:  m.value,
+				Current:   now[i].value,
+				Regressed: now[i].value < m.value-b.Tolerance,
+			})
+		}
+	}
+	if b.MinCoverage != nil {
+		for i, m := range coverageMetrics(*b.MinCoverage) {
+			if m.value == 0 {
+				continue
+			}
+			checks = append(checks, HealthCheck{
+				Metric:    m.name,
+				Kind:      HealthKindThreshold,
+				Expected:  m.value,
+				Current:   now[i].value,
+				Regressed: now[i].value < m.value,
+			})
+		}
+	}
+	return checks
+}
+
+// HasRegression reports whether any check regressed.
+func HasRegression(checks []HealthCheck) bool {
+	for _, c := range checks {
+		if c.Regressed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package analysis
+
+import "testing"
+
+func TestCheckHealth(t *testing.T) {
+	prior := &Stats{CoveragePercent: CoveragePercent{PURL: 100, License: 90, Hash: 50, CPE: 10}}
+
+	tests := []struct {
+		name      string
+		current   CoveragePercent
+		baseline  HealthBaseline
+		regressed []string // metric/kind pairs
+	}{
+		{
+			name:     "improvement",
+			current:  CoveragePercent{PURL: 100, License: 95, Hash: 60, CPE: 10},
+			baseline: HealthBaseline{Stats: prior},
+		},
+		{
+			name:      "regression",
+			current:   CoveragePercent{PURL: 100, License: 80, Hash: 50, CPE: 5},
+			baseline:  HealthBaseline{Stats: prior},
+			regressed: []string{"license_percent/baseline", "cpe_percent/baseline"},
+		},
+		{
+			name:     "drop within tolerance",
+			current:  CoveragePercent{PURL: 99, License: 88, Hash: 50, CPE: 10},
+			baseline: HealthBaseline{Stats: prior, Tolerance: 2},
+		},
+		{
+			name:      "drop beyond tolerance",
+			current:   CoveragePercent{PURL: 99, License: 87.5, Hash: 50, CPE: 10},
+			baseline:  HealthBaseline{Stats: prior, Tolerance: 2},
+			regressed: []string{"license_percent/baseline"},
+		},
+		{
+			name:      "thresholds are hard",
+			current:   CoveragePercent{PURL: 100, License: 79.5, Hash: 0},
+			baseline:  HealthBaseline{MinCoverage: &CoveragePercent{License: 80}, Tolerance: 5},
+			regressed: []string{"license_percent/threshold"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := CheckHealth(Stats{CoveragePercent: tt.current}, tt.baseline)
+			var regressed []string
+			for _, c := range checks {
+				if c.Regressed {
+					regressed = append(regressed, c.Metric+"/"+c.Kind)
+				}
+			}
+			if len(regressed) != len(tt.regressed) {
+				t.Fatalf("regressed = %v, want %v", regressed, tt.regressed)
+			}
+			for i := range regressed {
+				if regressed[i] != tt.regressed[i] {
+					t.Errorf("regressed = %v, want %v", regressed, tt.regressed)
+				}
+			}
+			if HasRegression(checks) != (len(tt.regressed) > 0) {
+				t.Errorf("HasRegression = %v", HasRegression(checks))
+			}
+		})
+	}
+}
+
+func TestCheckHealth_SkipsZeroThresholds(t *testing.T) {
+	checks := CheckHealth(Stats{}, HealthBaseline{MinCoverage: &CoveragePercent{Hash: 50}})
+	if len(checks) != 1 || checks[0].Metric != "hash_percent" || checks[0].Kind != HealthKindThreshold || !checks[0].Regressed {
+		t.Errorf("expected one failed hash threshold, got %+v", checks)
+	}
+}
+
+func TestParseHealthBaseline(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"prior run", `{"info": {}, "stats": {"coverage_percent": {"license_percent": 90}}}`, false},
+		{"thresholds", `{"min_coverage": {"hash_percent": 50}, "tolerance": 1}`, false},
+		{"empty", `{}`, true},
+		{"negative tolerance", `{"min_coverage": {"hash_percent": 50}, "tolerance": -1}`, true},
+		{"malformed", `{`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseHealthBaseline([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// LicenseChange is a changed component whose licenses differ.
+type LicenseChange struct {
+	Name           string   `json:"name"`
+	VersionBefore  string   `json:"version_before"`
+	VersionAfter   string   `json:"version_after"`
+	Before         []string `json:"before"`
+	After          []string `json:"after"`
+	Diff           []string `json:"diff"` // "+MIT", "-GPL-2.0-only", sorted
+	LicenseRemoved bool     `json:"license_removed,omitempty"`
+}
+
+// AddedLicense is the licensing of an added component.
+type AddedLicense struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	Licenses []string `json:"licenses"`
+	Category string   `json:"category"` // CategorizeLicense of the first license, or "unknown"
+}
+
+// LicenseDiff is the compliance view of a diff: license changes only.
+type LicenseDiff struct {
+	Changed     []LicenseChange `json:"changed"`
+	Added       []AddedLicense  `json:"added"`
+	NewCopyleft []string        `json:"new_copyleft"` // copyleft licenses no "before" component carried, sorted
+}
+
+// HasChanges reports whether the diff changes what licenses ship.
+func (d LicenseDiff) HasChanges() bool {
+	return len(d.Changed) > 0 || len(d.Added) > 0
+}
+
+// ComputeLicenseDiff extracts the license changes of result, the diff of
+// before against another SBOM. NOASSERTION/NONE placeholders are not
+// licenses and never count as new copyleft.
+func ComputeLicenseDiff(before []sbom.Component, result DiffResult) LicenseDiff {
+	d := LicenseDiff{
+		Changed:     []LicenseChange{},
+		Added:       []AddedLicense{},
+		NewCopyleft: []string{},
+	}
+
+	known := make(map[string]bool)
+	for _, c := range before {
+		for _, lic := range c.Licenses {
+			known[lic] = true
+		}
+	}
+	newCopyleft := make(map[string]bool)
+	noteNew := func(lic string) {
+		if !known[lic] && !sbom.IsPlaceholderLicense(lic) && CategorizeLicense(lic) == "copyleft" {
+			newCopyleft[lic] = true
+		}
+	}
+
+	for _, c := range result.Changed {
+		if c.Drift == nil || len(c.Drift.LicensesDiff) == 0 {
+			continue
+		}
+		diff := slices.Clone(c.Drift.LicensesDiff)
+		sort.Strings(diff)
+		d.Changed = append(d.Changed, LicenseChange{
+			Name:           c.Name,
+			VersionBefore:  c.Before.Version,
+			VersionAfter:   c.After.Version,
+			Before:         nonNil(c.Before.Licenses),
+			After:          nonNil(c.After.Licenses),
+			Diff:           diff,
+			LicenseRemoved: c.Drift.LicenseRemoved,
+		})
+		for _, lic := range diff {
+			if added, ok := strings.CutPrefix(lic, "+"); ok {
+				noteNew(added)
+			}
+		}
+	}
+
+	for _, c := range result.Added {
+		category := "unknown"
+		if lics := assertedLicenses(c.Licenses); len(lics) > 0 {
+			category = CategorizeLicense(lics[0])
+		}
+		d.Added = append(d.Added, AddedLicense{
+			Name:     c.Name,
+			Version:  c.Version,
+			Licenses: nonNil(c.Licenses),
+			Category: category,
+		})
+		for _, lic := range c.Licenses {
+			noteNew(lic)
+		}
+	}
+
+	for lic := range newCopyleft {
+		d.NewCopyleft = append(d.NewCopyleft, lic)
+	}
+	sort.Strings(d.NewCopyleft)
+	return d
+}
+
+// nonNil keeps empty license lists as [] in JSON.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
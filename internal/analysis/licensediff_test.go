@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestComputeLicenseDiff(t *testing.T) {
+	before := []sbom.Component{
+		{ID: "pkg:npm/a", Name: "a", Version: "1.0", Licenses: []string{"MIT"}},
+		{ID: "pkg:npm/b", Name: "b", Version: "1.0", Licenses: []string{"GPL-2.0-only"}},
+		{ID: "pkg:npm/c", Name: "c", Version: "1.0", Licenses: []string{"MIT"}},
+		{ID: "pkg:npm/d", Name: "d", Version: "1.0", Licenses: []string{"Apache-2.0"}},
+	}
+	after := []sbom.Component{
+		{ID: "pkg:npm/a", Name: "a", Version: "2.0", Licenses: []string{"MIT"}},                 // version only
+		{ID: "pkg:npm/b", Name: "b", Version: "1.0", Licenses: []string{"MIT", "GPL-2.0-only"}}, // license added, copyleft kept
+		{ID: "pkg:npm/c", Name: "c", Version: "1.1", Licenses: []string{"AGPL-3.0-only"}},       // relicensed to new copyleft
+		{ID: "pkg:npm/d", Name: "d", Version: "1.0"},                                            // license removed
+		{ID: "pkg:npm/e", Name: "e", Version: "1.0", Licenses: []string{"LGPL-3.0-only"}},       // added copyleft
+		{ID: "pkg:npm/f", Name: "f", Version: "1.0", Licenses: []string{"NOASSERTION"}},         // added, no license
+		{ID: "pkg:npm/g", Name: "g", Version: "1.0", Licenses: []string{"GPL-2.0-only"}},        // added, copyleft already used
+	}
+
+	d := ComputeLicenseDiff(before, DiffComponents(before, after))
+
+	wantChanged := []LicenseChange{
+		{Name: "b", VersionBefore: "1.0", VersionAfter: "1.0", Before: []string{"GPL-2.0-only"}, After: []string{"MIT", "GPL-2.0-only"}, Diff: []string{"+MIT"}},
+		{Name: "c", VersionBefore: "1.0", VersionAfter: "1.1", Before: []string{"MIT"}, After: []string{"AGPL-3.0-only"}, Diff: []string{"+AGPL-3.0-only", "-MIT"}},
+		{Name: "d", VersionBefore: "1.0", VersionAfter: "1.0", Before: []string{"Apache-2.0"}, After: []string{}, Diff: []string{"-Apache-2.0"}, LicenseRemoved: true},
+	}
+	if !reflect.DeepEqual(d.Changed, wantChanged) {
+		t.Errorf("Changed = %+v\nwant %+v", d.Changed, wantChanged)
+	}
+	wantAdded := []AddedLicense{
+		{Name: "e", Version: "1.0", Licenses: []string{"LGPL-3.0-only"}, Category: "copyleft"},
+		{Name: "f", Version: "1.0", Licenses: []string{"NOASSERTION"}, Category: "unknown"},
+		{Name: "g", Version: "1.0", Licenses: []string{"GPL-2.0-only"}, Category: "copyleft"},
+	}
+	if !reflect.DeepEqual(d.Added, wantAdded) {
+		t.Errorf("Added = %+v\nwant %+v", d.Added, wantAdded)
+	}
+	if want := []string{"AGPL-3.0-only", "LGPL-3.0-only"}; !reflect.DeepEqual(d.NewCopyleft, want) {
+		t.Errorf("NewCopyleft = %v, want %v", d.NewCopyleft, want)
+	}
+	if !d.HasChanges() {
+		t.Error("expected HasChanges")
+	}
+}
+
+func TestComputeLicenseDiff_VersionOnly(t *testing.T) {
+	before := []sbom.Component{{ID: "pkg:npm/a", Name: "a", Version: "1.0", Licenses: []string{"MIT"}}}
+	after := []sbom.Component{{ID: "pkg:npm/a", Name: "a", Version: "2.0", Licenses: []string{"MIT"}}}
+
+	d := ComputeLicenseDiff(before, DiffComponents(before, after))
+	if d.HasChanges() || len(d.Changed) != 0 || d.Added == nil || d.NewCopyleft == nil {
+		t.Errorf("expected an empty, non-nil license diff, got %+v", d)
+	}
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// MergeComponents combines normalized component lists into one inventory.
+// A component is dropped when an earlier list already has one with the same
+// ID and version. The same ID at another version is kept, so
+// DetectDuplicates reports the conflict. Repeats within one list are left
+// alone.
+func MergeComponents(lists ...[]sbom.Component) []sbom.Component {
+	type key struct{ id, version string }
+	seen := make(map[key]bool)
+	var merged []sbom.Component
+	for _, comps := range lists {
+		var added []key
+		for _, c := range comps {
+			k := key{c.ID, c.Version}
+			if seen[k] {
+				continue
+			}
+			added = append(added, k)
+			merged = append(merged, c)
+		}
+		for _, k := range added {
+			seen[k] = true
+		}
+	}
+	return merged
+}
+
+// MergeInfo combines the metadata of merged SBOMs. Descriptive fields are
+// kept only when every input agrees; file and relationship counts are summed.
+func MergeInfo(infos ...sbom.SBOMInfo) sbom.SBOMInfo {
+	if len(infos) == 0 {
+		return sbom.SBOMInfo{}
+	}
+	same := func(get func(sbom.SBOMInfo) string) string {
+		v := get(infos[0])
+		for _, info := range infos[1:] {
+			if get(info) != v {
+				return ""
+			}
+		}
+		return v
+	}
+
+	merged := sbom.SBOMInfo{
+		Format:        same(func(i sbom.SBOMInfo) string { return i.Format }),
+		OSName:        same(func(i sbom.SBOMInfo) string { return i.OSName }),
+		OSVersion:     same(func(i sbom.SBOMInfo) string { return i.OSVersion }),
+		OSPrettyName:  same(func(i sbom.SBOMInfo) string { return i.OSPrettyName }),
+		SourceType:    same(func(i sbom.SBOMInfo) string { return i.SourceType }),
+		ToolName:      same(func(i sbom.SBOMInfo) string { return i.ToolName }),
+		ToolVersion:   same(func(i sbom.SBOMInfo) string { return i.ToolVersion }),
+		SchemaVersion: same(func(i sbom.SBOMInfo) string { return i.SchemaVersion }),
+	}
+	for _, info := range infos {
+		merged.FilesCount += info.FilesCount
+		for rel, n := range info.RelationshipCounts {
+			if merged.RelationshipCounts == nil {
+				merged.RelationshipCounts = make(map[string]int)
+			}
+			merged.RelationshipCounts[rel] += n
+		}
+	}
+	return merged
+}
@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestMergeComponents(t *testing.T) {
+	a := []sbom.Component{
+		{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21"},
+		{ID: "pkg:npm/express", Name: "express", Version: "4.18.0"},
+	}
+	b := []sbom.Component{
+		{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.21"},
+		{ID: "pkg:npm/express", Name: "express", Version: "4.19.0"},
+		{ID: "pkg:npm/react", Name: "react", Version: "18.2.0"},
+		{ID: "pkg:npm/react", Name: "react", Version: "18.2.0"},
+	}
+
+	merged := MergeComponents(a, b)
+
+	var got []string
+	for _, c := range merged {
+		got = append(got, c.Name+"@"+c.Version)
+	}
+	want := []string{"lodash@4.17.21", "express@4.18.0", "express@4.19.0", "react@18.2.0", "react@18.2.0"}
+	if len(got) != len(want) {
+		t.Fatalf("MergeComponents() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("component %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	// the version conflict surfaces as a duplicate group
+	var conflict bool
+	for _, d := range DetectDuplicates(merged) {
+		if d.Name == "express" && len(d.Versions) == 2 && !d.Exact {
+			conflict = true
+		}
+	}
+	if !conflict {
+		t.Errorf("expected express version conflict in duplicates, got %+v", DetectDuplicates(merged))
+	}
+}
+
+func TestMergeInfo(t *testing.T) {
+	merged := MergeInfo(
+		sbom.SBOMInfo{ToolName: "syft", SourceName: "api", FilesCount: 3, RelationshipCounts: map[string]int{"contains": 2}},
+		sbom.SBOMInfo{ToolName: "syft", SourceName: "web", FilesCount: 4, RelationshipCounts: map[string]int{"contains": 1}},
+	)
+
+	if merged.ToolName != "syft" {
+		t.Errorf("expected shared tool to be kept, got %q", merged.ToolName)
+	}
+	if merged.SourceName != "" {
+		t.Errorf("expected differing source to be dropped, got %q", merged.SourceName)
+	}
+	if merged.FilesCount != 7 || merged.RelationshipCounts["contains"] != 3 {
+		t.Errorf("expected summed counts, got %+v", merged)
+	}
+}
@@ -0,0 +1,146 @@
+package analysis
+
+import (
+	"slices"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// Risk signals; each is also a RiskWeights key.
+const (
+	RiskIntegrityDrift = "integrity_drift"
+	RiskDeepDependency = "deep_dependency"
+	RiskSuspiciousJump = "suspicious_jump"
+	RiskNewSupplier    = "new_supplier"
+	RiskMissingLicense = "missing_license"
+	RiskMissingHashes  = "missing_hashes"
+)
+
+// RiskWeights maps a risk signal to the points it adds to a score.
+type RiskWeights map[string]int
+
+// DefaultRiskWeights returns the built-in weights. Integrity drift, a hash
+// change without a version change, weighs the most.
+func DefaultRiskWeights() RiskWeights {
+	return RiskWeights{
+		RiskIntegrityDrift: 50,
+		RiskDeepDependency: 20,
+		RiskSuspiciousJump: 15,
+		RiskNewSupplier:    15,
+		RiskMissingLicense: 10,
+		RiskMissingHashes:  5,
+	}
+}
+
+// WithOverrides returns a copy of w with the given weights replaced.
+func (w RiskWeights) WithOverrides(overrides map[string]int) RiskWeights {
+	out := make(RiskWeights, len(w))
+	for k, v := range w {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		out[k] = v
+	}
+	return out
+}
+
+// RiskScore is the weighted sum of the risk signals of one added or
+// changed component.
+type RiskScore struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Status  string   `json:"status"` // added or changed
+	Score   int      `json:"score"`
+	Signals []string `json:"signals,omitempty"`
+}
+
+// ComputeRiskScores scores the added and changed components of result into
+// result.RiskScores, highest first. before is the "before" SBOM, whose
+// suppliers count as known.
+func ComputeRiskScores(result *DiffResult, before []sbom.Component, weights RiskWeights) {
+	suppliers := SupplierSet(before)
+	deep := make(map[string]bool)
+	if result.Dependencies != nil {
+		summary := result.Dependencies.DepthSummary
+		if summary == nil {
+			summary = &DepthSummary{}
+		}
+		for _, dep := range result.Dependencies.TransitiveNew {
+			if summary.IsDeep(dep.Depth) {
+				deep[dep.Target] = true
+			}
+		}
+	}
+
+	score := func(c sbom.Component, status string, drift *DriftInfo, supplierBefore string) RiskScore {
+		var signals []string
+		if drift != nil && drift.Type == DriftTypeIntegrity {
+			signals = append(signals, RiskIntegrityDrift)
+		}
+		if deep[c.ID] {
+			signals = append(signals, RiskDeepDependency)
+		}
+		if drift != nil && drift.SuspiciousJump {
+			signals = append(signals, RiskSuspiciousJump)
+		}
+		if s := sbom.NormalizeSupplier(c.Supplier); s != "" && !suppliers[s] && s != sbom.NormalizeSupplier(supplierBefore) {
+			signals = append(signals, RiskNewSupplier)
+		}
+		if len(c.Licenses) == 0 {
+			signals = append(signals, RiskMissingLicense)
+		}
+		if len(c.Hashes) == 0 {
+			signals = append(signals, RiskMissingHashes)
+		}
+		r := RiskScore{ID: c.ID, Name: c.Name, Version: c.Version, Status: status, Signals: signals}
+		for _, s := range signals {
+			r.Score += weights[s]
+		}
+		return r
+	}
+
+	scores := make([]RiskScore, 0, len(result.Added)+len(result.Changed))
+	for _, c := range result.Added {
+		scores = append(scores, score(c, CategoryAdded, nil, ""))
+	}
+	for _, c := range result.Changed {
+		scores = append(scores, score(c.After, CategoryChanged, c.Drift, c.Before.Supplier))
+	}
+	slices.SortStableFunc(scores, func(a, b RiskScore) int {
+		return b.Score - a.Score
+	})
+	result.RiskScores = scores
+}
+
+// SortByRisk orders the Added and Changed lists by descending RiskScore,
+// keeping the existing order among equal scores.
+func SortByRisk(result *DiffResult) {
+	byID := RiskScoreByID(result.RiskScores)
+	slices.SortStableFunc(result.Added, func(a, b sbom.Component) int {
+		return byID[b.ID] - byID[a.ID]
+	})
+	slices.SortStableFunc(result.Changed, func(a, b ChangedComponent) int {
+		return byID[b.ID] - byID[a.ID]
+	})
+}
+
+// RiskScoreByID maps component IDs to their score.
+func RiskScoreByID(scores []RiskScore) map[string]int {
+	byID := make(map[string]int, len(scores))
+	for _, s := range scores {
+		byID[s.ID] = s.Score
+	}
+	return byID
+}
+
+// SupplierSet returns the normalized suppliers of comps; never nil.
+func SupplierSet(comps []sbom.Component) map[string]bool {
+	set := make(map[string]bool)
+	for _, c := range comps {
+		if s := sbom.NormalizeSupplier(c.Supplier); s != "" {
+			set[s] = true
+		}
+	}
+	return set
+}
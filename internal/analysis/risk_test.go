@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestComputeRiskScores(t *testing.T) {
+	hashes := map[string]string{"SHA256": "aaa"}
+	licensed := []string{"MIT"}
+	before := []sbom.Component{{ID: "pkg:npm/base", Name: "base", Supplier: "Known Corp"}}
+	result := DiffResult{
+		Added: []sbom.Component{
+			{ID: "pkg:npm/clean", Name: "clean", Licenses: licensed, Hashes: hashes, Supplier: "known corp"},
+			{ID: "pkg:npm/bare", Name: "bare"},
+			{ID: "pkg:npm/deep", Name: "deep", Licenses: licensed, Hashes: hashes},
+			{ID: "pkg:npm/stranger", Name: "stranger", Licenses: licensed, Hashes: hashes, Supplier: "Unknown Corp"},
+		},
+		Changed: []ChangedComponent{
+			{ID: "pkg:npm/tampered", Name: "tampered",
+				After: sbom.Component{ID: "pkg:npm/tampered", Name: "tampered", Licenses: licensed, Hashes: hashes},
+				Drift: &DriftInfo{Type: DriftTypeIntegrity}},
+			{ID: "pkg:npm/jumped", Name: "jumped",
+				After: sbom.Component{ID: "pkg:npm/jumped", Name: "jumped", Licenses: licensed, Hashes: hashes},
+				Drift: &DriftInfo{Type: DriftTypeVersion, SuspiciousJump: true}},
+		},
+		Dependencies: &DependencyDiff{
+			TransitiveNew: []TransitiveDep{
+				{Target: "pkg:npm/deep", Depth: 3},
+				{Target: "pkg:npm/clean", Depth: 1},
+			},
+		},
+	}
+
+	ComputeRiskScores(&result, before, DefaultRiskWeights())
+
+	want := []struct {
+		name    string
+		score   int
+		signals []string
+	}{
+		{"tampered", 50, []string{RiskIntegrityDrift}},
+		{"deep", 20, []string{RiskDeepDependency}},
+		// ties keep added before changed, in diff order
+		{"bare", 15, []string{RiskMissingLicense, RiskMissingHashes}},
+		{"stranger", 15, []string{RiskNewSupplier}},
+		{"jumped", 15, []string{RiskSuspiciousJump}},
+		{"clean", 0, nil},
+	}
+	var got []string
+	for _, s := range result.RiskScores {
+		got = append(got, s.Name)
+	}
+	if len(result.RiskScores) != len(want) {
+		t.Fatalf("expected %d scores, got %v", len(want), got)
+	}
+	for i, w := range want {
+		s := result.RiskScores[i]
+		if s.Name != w.name || s.Score != w.score || !slices.Equal(s.Signals, w.signals) {
+			t.Errorf("score %d = %s %d %v, want %s %d %v", i, s.Name, s.Score, s.Signals, w.name, w.score, w.signals)
+		}
+	}
+
+	t.Run("custom weights", func(t *testing.T) {
+		ComputeRiskScores(&result, before, DefaultRiskWeights().WithOverrides(map[string]int{RiskMissingHashes: 100}))
+		if top := result.RiskScores[0]; top.Name != "bare" || top.Score != 110 {
+			t.Errorf("expected bare to rank first with 110, got %s %d", top.Name, top.Score)
+		}
+	})
+
+	t.Run("sort by risk", func(t *testing.T) {
+		ComputeRiskScores(&result, before, DefaultRiskWeights())
+		SortByRisk(&result)
+		var added, changed []string
+		for _, c := range result.Added {
+			added = append(added, c.Name)
+		}
+		for _, c := range result.Changed {
+			changed = append(changed, c.Name)
+		}
+		if want := []string{"deep", "bare", "stranger", "clean"}; !slices.Equal(added, want) {
+			t.Errorf("added order = %v, want %v", added, want)
+		}
+		if want := []string{"tampered", "jumped"}; !slices.Equal(changed, want) {
+			t.Errorf("changed order = %v, want %v", changed, want)
+		}
+	})
+}
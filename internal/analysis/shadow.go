@@ -0,0 +1,67 @@
+package analysis
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// CrossEcosystemShadow is an added component whose name the "before" SBOM
+// already carried under another PURL type, e.g. pkg:npm/foo arriving while
+// pkg:pypi/foo was in use. Dependency-confusion attacks look like this, so
+// it is an advisory signal rather than proof of one.
+type CrossEcosystemShadow struct {
+	Name          string         `json:"name"`
+	Type          string         `json:"type"`           // PURL type of the added component
+	ExistingTypes []string       `json:"existing_types"` // PURL types the name had before, sorted
+	Added         sbom.Component `json:"added"`
+}
+
+// DetectCrossEcosystemShadows flags added components whose name existed
+// before only under other PURL types. Names reported as a TypeChange are
+// skipped: a lone package moving between types is re-cataloguing, not a
+// second package appearing.
+func DetectCrossEcosystemShadows(before, added []sbom.Component, typeChanged []TypeChange) []CrossEcosystemShadow {
+	beforeTypes := make(map[string][]string)
+	for name, comps := range groupByName(before, hasPURLType) {
+		for _, c := range comps {
+			t := ExtractPURLType(c.PURL)
+			if !slices.Contains(beforeTypes[name], t) {
+				beforeTypes[name] = append(beforeTypes[name], t)
+			}
+		}
+	}
+	moved := make(map[string]bool, len(typeChanged))
+	for _, tc := range typeChanged {
+		moved[tc.Name] = true
+	}
+
+	var shadows []CrossEcosystemShadow
+	for name, comps := range groupByName(added, hasPURLType) {
+		if moved[name] {
+			continue
+		}
+		for _, c := range comps {
+			t := ExtractPURLType(c.PURL)
+			existing := beforeTypes[name]
+			if len(existing) == 0 || slices.Contains(existing, t) {
+				continue
+			}
+			others := slices.Sorted(slices.Values(existing))
+			shadows = append(shadows, CrossEcosystemShadow{
+				Name:          name,
+				Type:          t,
+				ExistingTypes: others,
+				Added:         c,
+			})
+		}
+	}
+	sort.Slice(shadows, func(i, j int) bool {
+		if shadows[i].Name != shadows[j].Name {
+			return shadows[i].Name < shadows[j].Name
+		}
+		return shadows[i].Added.ID < shadows[j].Added.ID
+	})
+	return shadows
+}
@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestDetectCrossEcosystemShadows(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []sbom.Component
+		added  []sbom.Component
+		want   []string // "name:type<-existing"
+	}{
+		{
+			"npm package shadows pypi package",
+			[]sbom.Component{{ID: "pkg:pypi/requests", Name: "requests", PURL: "pkg:pypi/requests@2.31.0"}},
+			[]sbom.Component{{ID: "pkg:npm/requests", Name: "requests", PURL: "pkg:npm/requests@1.0.0"}},
+			[]string{"requests:npm<-pypi"},
+		},
+		{
+			"several existing types",
+			[]sbom.Component{
+				{ID: "pkg:pypi/foo", Name: "foo", PURL: "pkg:pypi/foo@1"},
+				{ID: "pkg:gem/foo", Name: "foo", PURL: "pkg:gem/foo@1"},
+			},
+			[]sbom.Component{{ID: "pkg:npm/foo", Name: "foo", PURL: "pkg:npm/foo@1"}},
+			[]string{"foo:npm<-gem,pypi"},
+		},
+		{
+			"same type already present",
+			[]sbom.Component{
+				{ID: "pkg:npm/foo", Name: "foo", PURL: "pkg:npm/foo@1"},
+				{ID: "pkg:pypi/foo", Name: "foo", PURL: "pkg:pypi/foo@1"},
+			},
+			[]sbom.Component{{ID: "pkg:npm/%40x/foo", Name: "foo", PURL: "pkg:npm/%40x/foo@1"}},
+			nil,
+		},
+		{
+			"new name",
+			[]sbom.Component{{ID: "pkg:pypi/foo", Name: "foo", PURL: "pkg:pypi/foo@1"}},
+			[]sbom.Component{{ID: "pkg:npm/bar", Name: "bar", PURL: "pkg:npm/bar@1"}},
+			nil,
+		},
+		{
+			"no purl",
+			[]sbom.Component{{ID: "pkg:pypi/foo", Name: "foo", PURL: "pkg:pypi/foo@1"}},
+			[]sbom.Component{{ID: "foo", Name: "foo"}},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range DetectCrossEcosystemShadows(tt.before, tt.added, nil) {
+				got = append(got, s.Name+":"+s.Type+"<-"+strings.Join(s.ExistingTypes, ","))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffComponents_CrossEcosystemShadow(t *testing.T) {
+	pypi := sbom.Component{ID: "pkg:pypi/colors", Name: "colors", Version: "1.0", PURL: "pkg:pypi/colors@1.0"}
+	npm := sbom.Component{ID: "pkg:npm/colors", Name: "colors", Version: "1.4.1", PURL: "pkg:npm/colors@1.4.1"}
+
+	result := DiffComponents([]sbom.Component{pypi}, []sbom.Component{pypi, npm})
+	if len(result.Shadows) != 1 {
+		t.Fatalf("expected 1 shadow, got %+v", result.Shadows)
+	}
+	if s := result.Shadows[0]; s.Added.ID != npm.ID || s.Type != "npm" || len(s.ExistingTypes) != 1 || s.ExistingTypes[0] != "pypi" {
+		t.Errorf("unexpected shadow %+v", s)
+	}
+	findings := ComputeKeyFindings(result, DiffOverview{})
+	found := false
+	for _, f := range findings.Findings {
+		found = found || strings.Contains(f.Message, "colors (npm, was pypi)")
+	}
+	if !found {
+		t.Errorf("expected a dependency-confusion finding, got %+v", findings.Findings)
+	}
+
+	// a package re-catalogued from one type to another is a type change
+	result = DiffComponents([]sbom.Component{pypi}, []sbom.Component{npm})
+	if len(result.TypeChanged) != 1 || len(result.Shadows) != 0 {
+		t.Errorf("expected a type change and no shadow, got %+v / %+v", result.TypeChanged, result.Shadows)
+	}
+}
@@ -2,9 +2,12 @@ package analysis
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/rezmoss/sbomlyze/internal/sbom"
 )
@@ -17,10 +20,18 @@ type Stats struct {
 	WithoutLicense    int              `json:"without_license"`
 	WithHashes        int              `json:"with_hashes"`
 	WithoutHashes     int              `json:"without_hashes"`
+	ByHashAlgo        map[string]int   `json:"by_hash_algo,omitempty"`
+	WeakHashOnly      []string         `json:"weak_hash_only,omitempty"` // "name version", sorted
 	TotalDependencies int              `json:"total_dependencies"`
 	WithDependencies  int              `json:"with_dependencies"`
+	DanglingDeps      int              `json:"dangling_deps"` // edges to IDs no component provides
+	ImplicitComponents []string        `json:"implicit_components,omitempty"` // IDs depended on but never declared, sorted
+	MaxDepth          int              `json:"max_depth"`
+	AvgDepth          float64          `json:"avg_depth"`
+	MostDependedOn    []DependedOn     `json:"most_depended_on,omitempty"`
 	DuplicateCount    int              `json:"duplicate_count"`
 	Duplicates        []DuplicateGroup `json:"duplicates,omitempty"`
+	VendoredCopies    []VendoredDuplicate `json:"vendored_copies,omitempty"`
 
 	ByLanguage        map[string]int   `json:"by_language,omitempty"`
 	ByFoundBy         map[string]int   `json:"by_found_by,omitempty"`
@@ -29,6 +40,16 @@ type Stats struct {
 	WithoutCPEs       int              `json:"without_cpes"`
 	WithPURL          int              `json:"with_purl"`
 	WithoutPURL       int              `json:"without_purl"`
+	LicenseConflicts  *LicenseConflicts `json:"license_conflicts,omitempty"`
+	CoveragePercent   CoveragePercent   `json:"coverage_percent"`
+}
+
+// CoveragePercent is the share of components (0-100) carrying each field.
+type CoveragePercent struct {
+	CPE     float64 `json:"cpe_percent"`
+	PURL    float64 `json:"purl_percent"`
+	License float64 `json:"license_percent"`
+	Hash    float64 `json:"hash_percent"`
 }
 
 // LicenseCategory groups license counts.
@@ -39,13 +60,62 @@ type LicenseCategory struct {
 	Unknown     int `json:"unknown"`
 }
 
+// LicenseConflicts is an advisory, heuristic view of license mixing.
+// It only notes that strong-copyleft (GPL/AGPL) components share an SBOM
+// with permissive-only ones; it does not know how components are linked
+// or distributed and is not a compatibility verdict.
+type LicenseConflicts struct {
+	StrongCopyleft []string       `json:"strong_copyleft"`    // "name version (license)", sorted
+	Pairings       map[string]int `json:"pairings,omitempty"` // coarse pairing -> component pairs
+}
+
+// weakHashAlgos are deprecated digests, keyed by normalized name.
+var weakHashAlgos = map[string]bool{
+	"MD2":  true,
+	"MD4":  true,
+	"MD5":  true,
+	"SHA1": true,
+}
+
+// HasOnlyWeakHashes reports whether hashes is non-empty and every algorithm is deprecated.
+func HasOnlyWeakHashes(hashes map[string]string) bool {
+	if len(hashes) == 0 {
+		return false
+	}
+	for algo := range hashes {
+		if !weakHashAlgos[sbom.NormalizeHashAlgorithm(algo)] {
+			return false
+		}
+	}
+	return true
+}
+
+// assertedLicenses drops NOASSERTION/NONE placeholders, which SPDX uses
+// for "no license information" rather than as a license.
+func assertedLicenses(lics []string) []string {
+	var out []string
+	for _, lic := range lics {
+		if !sbom.IsPlaceholderLicense(lic) {
+			out = append(out, lic)
+		}
+	}
+	return out
+}
+
 // ComputeStats calculates SBOM statistics.
 func ComputeStats(comps []sbom.Component) Stats {
+	return ComputeStatsWithGraph(comps, nil)
+}
+
+// ComputeStatsWithGraph is ComputeStats reusing graph, the NewGraph of
+// comps; a nil graph is built on demand.
+func ComputeStatsWithGraph(comps []sbom.Component, graph *Graph) Stats {
 	stats := Stats{
 		ByType:     make(map[string]int),
 		ByLicense:  make(map[string]int),
 		ByLanguage: make(map[string]int),
 		ByFoundBy:  make(map[string]int),
+		ByHashAlgo: make(map[string]int),
 	}
 
 	stats.TotalComponents = len(comps)
@@ -66,14 +136,15 @@ func ComputeStats(comps []sbom.Component) Stats {
 			stats.ByFoundBy[c.FoundBy]++
 		}
 
-		if len(c.Licenses) == 0 {
+		licenses := assertedLicenses(c.Licenses)
+		if len(licenses) == 0 {
 			stats.WithoutLicense++
 			licenseCategories.Unknown++
 		} else {
-			for _, lic := range c.Licenses {
+			for _, lic := range licenses {
 				stats.ByLicense[lic]++
 			}
-			category := CategorizeLicense(c.Licenses[0])
+			category := CategorizeLicense(licenses[0])
 			switch category {
 			case "copyleft":
 				licenseCategories.Copyleft++
@@ -88,6 +159,17 @@ func ComputeStats(comps []sbom.Component) Stats {
 
 		if len(c.Hashes) > 0 {
 			stats.WithHashes++
+			seenAlgo := make(map[string]bool, len(c.Hashes))
+			for algo := range c.Hashes {
+				norm := sbom.NormalizeHashAlgorithm(algo)
+				if !seenAlgo[norm] {
+					seenAlgo[norm] = true
+					stats.ByHashAlgo[norm]++
+				}
+			}
+			if HasOnlyWeakHashes(c.Hashes) {
+				stats.WeakHashOnly = append(stats.WeakHashOnly, strings.TrimSpace(c.Name+" "+c.Version))
+			}
 		} else {
 			stats.WithoutHashes++
 		}
@@ -110,6 +192,12 @@ func ComputeStats(comps []sbom.Component) Stats {
 		}
 	}
 
+	implicit, dangling := sbom.DanglingDependencies(comps)
+	stats.DanglingDeps = dangling
+	if dangling > 0 {
+		stats.ImplicitComponents = slices.Sorted(maps.Keys(implicit))
+	}
+
 	if stats.TotalComponents > 0 {
 		stats.LicenseCategories = licenseCategories
 	}
@@ -120,16 +208,48 @@ func ComputeStats(comps []sbom.Component) Stats {
 	if len(stats.ByFoundBy) == 0 {
 		stats.ByFoundBy = nil
 	}
+	if len(stats.ByHashAlgo) == 0 {
+		stats.ByHashAlgo = nil
+	}
+
+	sort.Strings(stats.WeakHashOnly)
+
+	stats.LicenseConflicts = ComputeLicenseConflicts(comps)
+
+	if stats.WithDependencies > 0 {
+		if graph == nil {
+			graph = NewGraph(comps)
+		}
+		stats.MaxDepth, stats.AvgDepth = graph.Depth()
+		stats.MostDependedOn = TopDependedOn(graph.FanInOut(), 10)
+	}
 
 	dups := DetectDuplicates(comps)
 	stats.DuplicateCount = len(dups)
 	if len(dups) > 0 {
 		stats.Duplicates = dups
 	}
+	stats.VendoredCopies = DetectVendoredDuplicates(comps)
+
+	stats.CoveragePercent = computeCoverage(stats)
 
 	return stats
 }
 
+// computeCoverage returns zero percentages for an empty SBOM.
+func computeCoverage(stats Stats) CoveragePercent {
+	if stats.TotalComponents == 0 {
+		return CoveragePercent{}
+	}
+	total := float64(stats.TotalComponents)
+	return CoveragePercent{
+		CPE:     float64(stats.WithCPEs) / total * 100,
+		PURL:    float64(stats.WithPURL) / total * 100,
+		License: float64(stats.TotalComponents-stats.WithoutLicense) / total * 100,
+		Hash:    float64(stats.WithHashes) / total * 100,
+	}
+}
+
 // CategorizeLicense returns copyleft/permissive/public_domain/unknown.
 func CategorizeLicense(license string) string {
 	lic := strings.ToUpper(license)
@@ -155,6 +275,100 @@ func CategorizeLicense(license string) string {
 	return "unknown"
 }
 
+// strongCopyleftFamily returns "AGPL" or "GPL" for strong-copyleft licenses.
+// LGPL and dual-licensed ("X OR Y") expressions are not strong copyleft.
+func strongCopyleftFamily(license string) string {
+	lic := strings.ToUpper(license)
+	if strings.Contains(lic, " OR ") || strings.Contains(lic, "LGPL") {
+		return ""
+	}
+	if strings.Contains(lic, "AGPL") {
+		return "AGPL"
+	}
+	if strings.Contains(lic, "GPL") {
+		return "GPL"
+	}
+	return ""
+}
+
+// isGPL2Only reports GPL-2.0 without the "or later" grant.
+func isGPL2Only(license string) bool {
+	lic := strings.ToUpper(license)
+	return strings.Contains(lic, "GPL-2.0") && !strings.Contains(lic, "LGPL") &&
+		!strings.Contains(lic, "OR-LATER") && !strings.HasSuffix(lic, "+")
+}
+
+// ComputeLicenseConflicts flags strong-copyleft components and counts coarse
+// pairings with permissive-only components. Returns nil without strong copyleft.
+//
+// Pairings:
+//   - "AGPL+permissive", "GPL+permissive": copyleft x permissive-only components
+//   - "GPL-2.0-only+Apache-2.0": the well-known one-way incompatibility
+func ComputeLicenseConflicts(comps []sbom.Component) *LicenseConflicts {
+	var strong []string
+	families := make(map[string]int)
+	gpl2Only := 0
+	permissive := 0
+	apache2 := 0
+
+	for _, c := range comps {
+		licenses := assertedLicenses(c.Licenses)
+		if len(licenses) == 0 {
+			continue
+		}
+		family, familyLic := "", ""
+		allPermissive := true
+		hasApache2 := false
+		for _, lic := range licenses {
+			if f := strongCopyleftFamily(lic); f != "" {
+				// AGPL wins when a component lists both
+				if family == "" || f == "AGPL" {
+					family, familyLic = f, lic
+				}
+			}
+			if CategorizeLicense(lic) != "permissive" {
+				allPermissive = false
+			}
+			if strings.Contains(strings.ToUpper(lic), "APACHE-2.0") {
+				hasApache2 = true
+			}
+		}
+		if family != "" {
+			families[family]++
+			if family == "GPL" && isGPL2Only(familyLic) {
+				gpl2Only++
+			}
+			strong = append(strong, fmt.Sprintf("%s %s (%s)", c.Name, c.Version, familyLic))
+		}
+		if allPermissive {
+			permissive++
+			if hasApache2 {
+				apache2++
+			}
+		}
+	}
+
+	if len(strong) == 0 {
+		return nil
+	}
+
+	sort.Strings(strong)
+	conflicts := &LicenseConflicts{StrongCopyleft: strong}
+	pairings := make(map[string]int)
+	for family, n := range families {
+		if permissive > 0 {
+			pairings[family+"+permissive"] = n * permissive
+		}
+	}
+	if gpl2Only > 0 && apache2 > 0 {
+		pairings["GPL-2.0-only+Apache-2.0"] = gpl2Only * apache2
+	}
+	if len(pairings) > 0 {
+		conflicts.Pairings = pairings
+	}
+	return conflicts
+}
+
 // ExtractPURLType extracts the type segment from a PURL.
 func ExtractPURLType(purl string) string {
 	if purl == "" || !strings.HasPrefix(purl, "pkg:") {
@@ -174,12 +388,23 @@ func PrintStats(stats Stats) {
 
 	fmt.Printf("Total Components: %d\n\n", stats.TotalComponents)
 
+	if stats.TotalComponents > 0 {
+		fmt.Printf("Coverage:\n")
+		tw := newStatsTable(12)
+		for _, m := range coverageRows(stats.CoveragePercent) {
+			fmt.Fprintf(tw, "  %s:\t%5.1f%%\n", m.name, m.value)
+		}
+		tw.Flush()
+		fmt.Println()
+	}
+
 	if len(stats.ByType) > 0 {
 		fmt.Printf("By Package Type:\n")
-		types := SortedKeys(stats.ByType)
-		for _, t := range types {
-			fmt.Printf("  %-12s %d\n", t, stats.ByType[t])
+		tw := newStatsTable(15)
+		for _, t := range SortedKeys(stats.ByType) {
+			fmt.Fprintf(tw, "  %s\t%d\n", t, stats.ByType[t])
 		}
+		tw.Flush()
 		fmt.Println()
 	}
 
@@ -189,14 +414,26 @@ func PrintStats(stats Stats) {
 	if len(stats.ByLicense) > 0 {
 		fmt.Printf("\n  Top Licenses:\n")
 		licenses := SortedByValue(stats.ByLicense)
-		count := 0
-		for _, lic := range licenses {
-			if count >= 10 {
-				fmt.Printf("    ... and %d more\n", len(licenses)-10)
+		tw := newStatsTable(35)
+		for _, lic := range licenses[:min(len(licenses), 10)] {
+			fmt.Fprintf(tw, "    %s\t%d\n", lic, stats.ByLicense[lic])
+		}
+		tw.Flush()
+		if len(licenses) > 10 {
+			fmt.Printf("    ... and %d more\n", len(licenses)-10)
+		}
+	}
+	if lc := stats.LicenseConflicts; lc != nil {
+		fmt.Printf("\n  ⚠️  Strong copyleft (advisory): %d\n", len(lc.StrongCopyleft))
+		for i, s := range lc.StrongCopyleft {
+			if i >= 5 {
+				fmt.Printf("    ... and %d more\n", len(lc.StrongCopyleft)-5)
 				break
 			}
-			fmt.Printf("    %-30s %d\n", lic, stats.ByLicense[lic])
-			count++
+			fmt.Printf("    %s\n", s)
+		}
+		for _, k := range SortedKeys(lc.Pairings) {
+			fmt.Printf("    %-30s %d pairs\n", k, lc.Pairings[k])
 		}
 	}
 	fmt.Println()
@@ -204,22 +441,123 @@ func PrintStats(stats Stats) {
 	fmt.Printf("Integrity:\n")
 	fmt.Printf("  With hashes:    %d\n", stats.WithHashes)
 	fmt.Printf("  Without hashes: %d\n", stats.WithoutHashes)
+	if len(stats.ByHashAlgo) > 0 {
+		fmt.Printf("\n  By algorithm:\n")
+		for _, algo := range SortedKeys(stats.ByHashAlgo) {
+			fmt.Printf("    %-14s %d\n", algo, stats.ByHashAlgo[algo])
+		}
+	}
+	if len(stats.WeakHashOnly) > 0 {
+		fmt.Printf("\n  ⚠️  Weak hashes only (MD5/SHA-1, no strong hash): %d\n", len(stats.WeakHashOnly))
+		for i, name := range stats.WeakHashOnly {
+			if i == 5 {
+				fmt.Printf("    ...and %d more\n", len(stats.WeakHashOnly)-5)
+				break
+			}
+			fmt.Printf("    %s\n", name)
+		}
+	}
 	fmt.Println()
 
 	fmt.Printf("Dependencies:\n")
 	fmt.Printf("  Components with deps: %d\n", stats.WithDependencies)
 	fmt.Printf("  Total dep relations:  %d\n", stats.TotalDependencies)
+	if stats.DanglingDeps > 0 {
+		fmt.Printf("  Dangling relations:   %d\n", stats.DanglingDeps)
+		fmt.Printf("  Implicit components:  %d\n", len(stats.ImplicitComponents))
+		for i, id := range stats.ImplicitComponents {
+			if i == 5 {
+				fmt.Printf("    ...and %d more\n", len(stats.ImplicitComponents)-5)
+				break
+			}
+			fmt.Printf("    %s\n", id)
+		}
+	}
+	if stats.MaxDepth > 0 {
+		fmt.Printf("  Max depth:            %d\n", stats.MaxDepth)
+		fmt.Printf("  Avg depth:            %.1f\n", stats.AvgDepth)
+	}
+	if len(stats.MostDependedOn) > 0 {
+		fmt.Printf("\n  Most depended on:\n")
+		for i, d := range stats.MostDependedOn {
+			if i >= 5 {
+				break
+			}
+			fmt.Printf("    %-30s %d\n", d.ID, d.Count)
+		}
+	}
 	fmt.Println()
 
 	if stats.DuplicateCount > 0 {
 		fmt.Printf("⚠️  Duplicates Found: %d\n", stats.DuplicateCount)
 		for _, d := range stats.Duplicates {
-			fmt.Printf("  %s: %v\n", d.Name, d.Versions)
+			exact := ""
+			if d.Exact {
+				exact = " [exact copies]"
+			}
+			fmt.Printf("  %s: %v%s\n", d.Name, d.Versions, exact)
+		}
+		fmt.Println()
+	}
+
+	if len(stats.VendoredCopies) > 0 {
+		fmt.Printf("Vendored Copies (not counted as duplicates): %d\n", len(stats.VendoredCopies))
+		for _, v := range stats.VendoredCopies {
+			fmt.Printf("  %s %s: %s\n", v.Name, v.Version, strings.Join(v.Subpaths, ", "))
 		}
 		fmt.Println()
 	}
 }
 
+// PrintStatsTable prints the coverage, by-type and license counts of
+// --format table: plain aligned columns under a header row, for piping.
+func PrintStatsTable(stats Stats) {
+	tw := newStatsTable(0)
+	fmt.Fprintf(tw, "COVERAGE\tPERCENT\n")
+	for _, m := range coverageRows(stats.CoveragePercent) {
+		fmt.Fprintf(tw, "%s\t%.1f\n", m.name, m.value)
+	}
+	tw.Flush()
+
+	fmt.Println()
+	tw = newStatsTable(0)
+	fmt.Fprintf(tw, "TYPE\tCOUNT\n")
+	for _, t := range SortedKeys(stats.ByType) {
+		fmt.Fprintf(tw, "%s\t%d\n", t, stats.ByType[t])
+	}
+	tw.Flush()
+
+	fmt.Println()
+	tw = newStatsTable(0)
+	fmt.Fprintf(tw, "LICENSE\tCOUNT\n")
+	for _, lic := range SortedByValue(stats.ByLicense) {
+		fmt.Fprintf(tw, "%s\t%d\n", lic, stats.ByLicense[lic])
+	}
+	tw.Flush()
+}
+
+// newStatsTable returns a stdout tabwriter with columns at least
+// minwidth wide, padded by one space.
+func newStatsTable(minwidth int) *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, minwidth, 0, 1, ' ', 0)
+}
+
+// coverageRows lists the coverage percentages in PrintStats order.
+func coverageRows(c CoveragePercent) []struct {
+	name  string
+	value float64
+} {
+	return []struct {
+		name  string
+		value float64
+	}{
+		{"PURL", c.PURL},
+		{"CPE", c.CPE},
+		{"License", c.License},
+		{"Hashes", c.Hash},
+	}
+}
+
 func SortedKeys(m map[string]int) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -235,7 +573,10 @@ func SortedByValue(m map[string]int) []string {
 		keys = append(keys, k)
 	}
 	sort.Slice(keys, func(i, j int) bool {
-		return m[keys[i]] > m[keys[j]]
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
 	})
 	return keys
 }
@@ -254,8 +595,9 @@ type DiffOverview struct {
 	After  SBOMSide `json:"after"`
 }
 
-// ComputeDiffOverview builds the comparison overview.
-func ComputeDiffOverview(file1, file2 string, comps1, comps2 []sbom.Component, info1, info2 sbom.SBOMInfo) DiffOverview {
+// ComputeDiffOverview builds the comparison overview. graph1 and graph2
+// may be nil.
+func ComputeDiffOverview(file1, file2 string, comps1, comps2 []sbom.Component, graph1, graph2 *Graph, info1, info2 sbom.SBOMInfo) DiffOverview {
 	var size1, size2 int64
 	if fi, err := os.Stat(file1); err == nil {
 		size1 = fi.Size()
@@ -269,13 +611,13 @@ func ComputeDiffOverview(file1, file2 string, comps1, comps2 []sbom.Component, i
 			FileName: file1,
 			FileSize: size1,
 			Info:     info1,
-			Stats:    ComputeStats(comps1),
+			Stats:    ComputeStatsWithGraph(comps1, graph1),
 		},
 		After: SBOMSide{
 			FileName: file2,
 			FileSize: size2,
 			Info:     info2,
-			Stats:    ComputeStats(comps2),
+			Stats:    ComputeStatsWithGraph(comps2, graph2),
 		},
 	}
 }
@@ -1,6 +1,8 @@
 package analysis
 
 import (
+	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/rezmoss/sbomlyze/internal/sbom"
@@ -319,3 +321,195 @@ func TestComputeStats_CompoundLicenses(t *testing.T) {
 	}
 }
 
+func TestComputeStats_NoAssertionLicenses(t *testing.T) {
+	comps := []sbom.Component{
+		{ID: "a", Name: "a", Licenses: []string{"NOASSERTION"}},
+		{ID: "b", Name: "b", Licenses: []string{"NONE"}},
+		{ID: "c", Name: "c", Licenses: []string{"noassertion", "MIT"}},
+		{ID: "d", Name: "d", Licenses: []string{"MIT"}},
+	}
+	stats := ComputeStats(comps)
+
+	if stats.WithoutLicense != 2 {
+		t.Errorf("expected 2 components without license, got %d", stats.WithoutLicense)
+	}
+	for _, placeholder := range []string{"NOASSERTION", "NONE", "noassertion"} {
+		if _, ok := stats.ByLicense[placeholder]; ok {
+			t.Errorf("expected %s excluded from ByLicense, got %v", placeholder, stats.ByLicense)
+		}
+	}
+	if stats.ByLicense["MIT"] != 2 {
+		t.Errorf("expected MIT counted twice, got %v", stats.ByLicense)
+	}
+	if stats.LicenseCategories.Permissive != 2 || stats.LicenseCategories.Unknown != 2 {
+		t.Errorf("unexpected categories %+v", stats.LicenseCategories)
+	}
+}
+
+
+func TestComputeLicenseConflicts(t *testing.T) {
+	t.Run("flags GPL mixed with MIT", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "a", Name: "readline", Version: "8.2", Licenses: []string{"GPL-3.0-only"}},
+			{ID: "b", Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}},
+			{ID: "c", Name: "chalk", Version: "5.0.0", Licenses: []string{"MIT"}},
+			{ID: "d", Name: "nolicense", Version: "1.0.0"},
+		}
+
+		lc := ComputeLicenseConflicts(comps)
+		if lc == nil {
+			t.Fatal("expected license conflicts for GPL + MIT")
+		}
+		if len(lc.StrongCopyleft) != 1 || lc.StrongCopyleft[0] != "readline 8.2 (GPL-3.0-only)" {
+			t.Errorf("unexpected strong copyleft list: %v", lc.StrongCopyleft)
+		}
+		if lc.Pairings["GPL+permissive"] != 2 {
+			t.Errorf("expected 2 GPL+permissive pairs, got %d", lc.Pairings["GPL+permissive"])
+		}
+		if _, ok := lc.Pairings["GPL-2.0-only+Apache-2.0"]; ok {
+			t.Error("did not expect GPL-2.0-only+Apache-2.0 pairing")
+		}
+	})
+
+	t.Run("GPL-2.0-only with Apache-2.0", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "a", Name: "a", Licenses: []string{"GPL-2.0-only"}},
+			{ID: "b", Name: "b", Licenses: []string{"Apache-2.0"}},
+		}
+
+		lc := ComputeLicenseConflicts(comps)
+		if lc == nil || lc.Pairings["GPL-2.0-only+Apache-2.0"] != 1 {
+			t.Errorf("expected GPL-2.0-only+Apache-2.0 pairing, got %+v", lc)
+		}
+	})
+
+	t.Run("LGPL and dual-licensed are not strong copyleft", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "a", Name: "a", Licenses: []string{"LGPL-2.1-only"}},
+			{ID: "b", Name: "b", Licenses: []string{"MIT OR GPL-2.0-only"}},
+			{ID: "c", Name: "c", Licenses: []string{"MIT"}},
+		}
+
+		if lc := ComputeLicenseConflicts(comps); lc != nil {
+			t.Errorf("expected no conflicts, got %+v", lc)
+		}
+	})
+
+	t.Run("set on stats", func(t *testing.T) {
+		comps := []sbom.Component{
+			{ID: "a", Name: "a", Licenses: []string{"AGPL-3.0-only"}},
+			{ID: "b", Name: "b", Licenses: []string{"MIT"}},
+		}
+
+		stats := ComputeStats(comps)
+		if stats.LicenseConflicts == nil || stats.LicenseConflicts.Pairings["AGPL+permissive"] != 1 {
+			t.Errorf("expected AGPL+permissive pairing on stats, got %+v", stats.LicenseConflicts)
+		}
+	})
+}
+
+func TestComputeStats_ByHashAlgo(t *testing.T) {
+	comps := []sbom.Component{
+		{ID: "a", Name: "a", Hashes: map[string]string{"SHA-1": "aa", "SHA-256": "bb"}},
+		{ID: "b", Name: "b", Hashes: map[string]string{"SHA256": "cc"}},
+		{ID: "c", Name: "c", Hashes: map[string]string{"sha1": "dd"}},
+		{ID: "d", Name: "d"},
+	}
+
+	stats := ComputeStats(comps)
+
+	if stats.ByHashAlgo["SHA256"] != 2 {
+		t.Errorf("expected 2 SHA256, got %d", stats.ByHashAlgo["SHA256"])
+	}
+	if stats.ByHashAlgo["SHA1"] != 2 {
+		t.Errorf("expected 2 SHA1, got %d", stats.ByHashAlgo["SHA1"])
+	}
+	if len(stats.ByHashAlgo) != 2 {
+		t.Errorf("expected only normalized algorithm keys, got %v", stats.ByHashAlgo)
+	}
+}
+
+func TestHasOnlyWeakHashes(t *testing.T) {
+	tests := []struct {
+		name   string
+		hashes map[string]string
+		want   bool
+	}{
+		{"MD5 only", map[string]string{"MD5": "aa"}, true},
+		{"SHA1 only", map[string]string{"SHA-1": "aa"}, true},
+		{"MD5 and SHA1", map[string]string{"md5": "aa", "sha1": "bb"}, true},
+		{"SHA256 present", map[string]string{"SHA-1": "aa", "SHA-256": "bb"}, false},
+		{"SHA256 only", map[string]string{"SHA256": "aa"}, false},
+		{"no hashes", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasOnlyWeakHashes(tt.hashes); got != tt.want {
+				t.Errorf("HasOnlyWeakHashes(%v) = %v, want %v", tt.hashes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeStats_WeakHashOnly(t *testing.T) {
+	comps := []sbom.Component{
+		{ID: "md5", Name: "md5-pkg", Version: "1.0", Hashes: map[string]string{"MD5": "aa"}},
+		{ID: "sha1", Name: "sha1-pkg", Version: "2.0", Hashes: map[string]string{"SHA-1": "bb"}},
+		{ID: "strong", Name: "strong-pkg", Version: "3.0", Hashes: map[string]string{"SHA-1": "cc", "SHA-256": "dd"}},
+		{ID: "none", Name: "none-pkg"},
+	}
+
+	stats := ComputeStats(comps)
+
+	want := []string{"md5-pkg 1.0", "sha1-pkg 2.0"}
+	if !reflect.DeepEqual(stats.WeakHashOnly, want) {
+		t.Errorf("WeakHashOnly = %v, want %v", stats.WeakHashOnly, want)
+	}
+}
+
+func TestComputeStats_DanglingDeps(t *testing.T) {
+	comps := []sbom.Component{
+		{ID: "app", Name: "app", Dependencies: []string{"lib", "ghost", "phantom"}},
+		{ID: "lib", Name: "lib", Dependencies: []string{"ghost"}},
+	}
+
+	stats := ComputeStats(comps)
+
+	if stats.TotalDependencies != 4 || stats.DanglingDeps != 3 {
+		t.Errorf("TotalDependencies = %d, DanglingDeps = %d, want 4 and 3", stats.TotalDependencies, stats.DanglingDeps)
+	}
+	if want := []string{"ghost", "phantom"}; !slices.Equal(stats.ImplicitComponents, want) {
+		t.Errorf("ImplicitComponents = %v, want %v", stats.ImplicitComponents, want)
+	}
+	if got := ComputeStats(comps[1:]).ImplicitComponents; !slices.Equal(got, []string{"ghost"}) {
+		t.Errorf("ImplicitComponents without app = %v, want [ghost]", got)
+	}
+}
+
+func TestComputeStats_CoveragePercent(t *testing.T) {
+	comps := []sbom.Component{
+		{Name: "a", PURL: "pkg:npm/a@1", Licenses: []string{"MIT"}, Hashes: map[string]string{"SHA256": "x"}, CPEs: []string{"cpe:2.3:a:a:a:1:*:*:*:*:*:*:*"}},
+		{Name: "b", PURL: "pkg:npm/b@1", Licenses: []string{"MIT"}, Hashes: map[string]string{"SHA256": "y"}},
+		{Name: "c", PURL: "pkg:npm/c@1", Hashes: map[string]string{"SHA256": "z"}},
+		{Name: "d"},
+	}
+
+	tests := []struct {
+		name  string
+		comps []sbom.Component
+		want  CoveragePercent
+	}{
+		{"known set", comps, CoveragePercent{CPE: 25, PURL: 75, License: 50, Hash: 75}},
+		{"empty sbom", nil, CoveragePercent{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeStats(tt.comps).CoveragePercent
+			if got != tt.want {
+				t.Errorf("CoveragePercent = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
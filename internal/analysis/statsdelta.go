@@ -0,0 +1,97 @@
+package analysis
+
+// CountDelta is a count on both sides of a diff.
+type CountDelta struct {
+	Before int `json:"before"`
+	After  int `json:"after"`
+	Change int `json:"change"`
+}
+
+// PercentDelta is a percentage (0-100) on both sides of a diff; Change is
+// in percentage points.
+type PercentDelta struct {
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Change float64 `json:"change"`
+}
+
+// LicenseCategoryDelta compares LicenseCategory counts.
+type LicenseCategoryDelta struct {
+	Copyleft     CountDelta `json:"copyleft"`
+	Permissive   CountDelta `json:"permissive"`
+	PublicDomain CountDelta `json:"public_domain"`
+	Unknown      CountDelta `json:"unknown"`
+}
+
+// CoverageDelta compares CoveragePercent.
+type CoverageDelta struct {
+	PURL    PercentDelta `json:"purl_percent"`
+	License PercentDelta `json:"license_percent"`
+	Hash    PercentDelta `json:"hash_percent"`
+	CPE     PercentDelta `json:"cpe_percent"`
+}
+
+// StatsDelta is the aggregate view of a diff: how the headline Stats
+// moved between the two SBOMs.
+type StatsDelta struct {
+	TotalComponents   CountDelta            `json:"total_components"`
+	ByType            map[string]CountDelta `json:"by_type,omitempty"` // every type on either side
+	LicenseCategories LicenseCategoryDelta  `json:"license_categories"`
+	Coverage          CoverageDelta         `json:"coverage"`
+	TotalDependencies CountDelta            `json:"total_dependencies"`
+	WithDependencies  CountDelta            `json:"with_dependencies"`
+	MaxDepth          CountDelta            `json:"max_depth"`
+}
+
+// ComputeStatsDelta compares the stats of the "before" and "after" SBOMs.
+func ComputeStatsDelta(before, after Stats) StatsDelta {
+	delta := StatsDelta{
+		TotalComponents: countDelta(before.TotalComponents, after.TotalComponents),
+		Coverage: CoverageDelta{
+			PURL:    percentDelta(before.CoveragePercent.PURL, after.CoveragePercent.PURL),
+			License: percentDelta(before.CoveragePercent.License, after.CoveragePercent.License),
+			Hash:    percentDelta(before.CoveragePercent.Hash, after.CoveragePercent.Hash),
+			CPE:     percentDelta(before.CoveragePercent.CPE, after.CoveragePercent.CPE),
+		},
+		TotalDependencies: countDelta(before.TotalDependencies, after.TotalDependencies),
+		WithDependencies:  countDelta(before.WithDependencies, after.WithDependencies),
+		MaxDepth:          countDelta(before.MaxDepth, after.MaxDepth),
+	}
+
+	if len(before.ByType) > 0 || len(after.ByType) > 0 {
+		delta.ByType = make(map[string]CountDelta)
+		for t, n := range before.ByType {
+			delta.ByType[t] = countDelta(n, after.ByType[t])
+		}
+		for t, n := range after.ByType {
+			if _, ok := before.ByType[t]; !ok {
+				delta.ByType[t] = countDelta(0, n)
+			}
+		}
+	}
+
+	// empty SBOMs have no categories
+	var lb, la LicenseCategory
+	if before.LicenseCategories != nil {
+		lb = *before.LicenseCategories
+	}
+	if after.LicenseCategories != nil {
+		la = *after.LicenseCategories
+	}
+	delta.LicenseCategories = LicenseCategoryDelta{
+		Copyleft:     countDelta(lb.Copyleft, la.Copyleft),
+		Permissive:   countDelta(lb.Permissive, la.Permissive),
+		PublicDomain: countDelta(lb.PublicDomain, la.PublicDomain),
+		Unknown:      countDelta(lb.Unknown, la.Unknown),
+	}
+
+	return delta
+}
+
+func countDelta(before, after int) CountDelta {
+	return CountDelta{Before: before, After: after, Change: after - before}
+}
+
+func percentDelta(before, after float64) PercentDelta {
+	return PercentDelta{Before: before, After: after, Change: after - before}
+}
@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestComputeStatsDelta(t *testing.T) {
+	before := []sbom.Component{
+		{ID: "a", Name: "a", PURL: "pkg:npm/a@1", Licenses: []string{"MIT"}, Dependencies: []string{"b"}},
+		{ID: "b", Name: "b", PURL: "pkg:npm/b@1", Licenses: []string{"GPL-3.0-only"}},
+		{ID: "c", Name: "c", PURL: "pkg:pypi/c@1"},
+		{ID: "d", Name: "d", PURL: "pkg:pypi/d@1"},
+	}
+	after := []sbom.Component{
+		{ID: "a", Name: "a", PURL: "pkg:npm/a@2", Licenses: []string{"MIT"}, Hashes: map[string]string{"SHA256": "x"}, Dependencies: []string{"b"}},
+		{ID: "b", Name: "b", PURL: "pkg:npm/b@1", Licenses: []string{"MIT"}, Dependencies: []string{"e"}},
+		{ID: "c", Name: "c", PURL: "pkg:pypi/c@1", Licenses: []string{"Apache-2.0"}},
+		{ID: "e", Name: "e", PURL: "pkg:golang/e@1", Licenses: []string{"public-domain"}},
+		{ID: "f", Name: "f", PURL: "pkg:npm/f@1"},
+	}
+
+	got := ComputeStatsDelta(ComputeStats(before), ComputeStats(after))
+
+	if got.TotalComponents != (CountDelta{Before: 4, After: 5, Change: 1}) {
+		t.Errorf("TotalComponents = %+v", got.TotalComponents)
+	}
+	wantTypes := map[string]CountDelta{
+		"npm":    {Before: 2, After: 3, Change: 1},
+		"pypi":   {Before: 2, After: 1, Change: -1},
+		"golang": {Before: 0, After: 1, Change: 1},
+	}
+	if !reflect.DeepEqual(got.ByType, wantTypes) {
+		t.Errorf("ByType = %+v, want %+v", got.ByType, wantTypes)
+	}
+	wantLicenses := LicenseCategoryDelta{
+		Copyleft:     CountDelta{Before: 1, After: 0, Change: -1},
+		Permissive:   CountDelta{Before: 1, After: 3, Change: 2},
+		PublicDomain: CountDelta{Before: 0, After: 1, Change: 1},
+		Unknown:      CountDelta{Before: 2, After: 1, Change: -1},
+	}
+	if got.LicenseCategories != wantLicenses {
+		t.Errorf("LicenseCategories = %+v, want %+v", got.LicenseCategories, wantLicenses)
+	}
+	if got.Coverage.License != (PercentDelta{Before: 50, After: 80, Change: 30}) {
+		t.Errorf("Coverage.License = %+v", got.Coverage.License)
+	}
+	if got.Coverage.Hash != (PercentDelta{Before: 0, After: 20, Change: 20}) {
+		t.Errorf("Coverage.Hash = %+v", got.Coverage.Hash)
+	}
+	if got.Coverage.PURL != (PercentDelta{Before: 100, After: 100}) {
+		t.Errorf("Coverage.PURL = %+v", got.Coverage.PURL)
+	}
+	if got.TotalDependencies != (CountDelta{Before: 1, After: 2, Change: 1}) {
+		t.Errorf("TotalDependencies = %+v", got.TotalDependencies)
+	}
+	if got.WithDependencies != (CountDelta{Before: 1, After: 2, Change: 1}) {
+		t.Errorf("WithDependencies = %+v", got.WithDependencies)
+	}
+	if got.MaxDepth != (CountDelta{Before: 1, After: 2, Change: 1}) {
+		t.Errorf("MaxDepth = %+v", got.MaxDepth)
+	}
+}
+
+func TestComputeStatsDelta_Empty(t *testing.T) {
+	got := ComputeStatsDelta(ComputeStats(nil), ComputeStats(nil))
+	if got.ByType != nil || got.TotalComponents != (CountDelta{}) || got.LicenseCategories != (LicenseCategoryDelta{}) {
+		t.Errorf("expected zero delta for empty SBOMs, got %+v", got)
+	}
+}
@@ -0,0 +1,37 @@
+package analysis
+
+// DiffStats holds the headline counts of a diff, for gating and quick
+// reporting without walking the full result.
+type DiffStats struct {
+	Added          int `json:"added"`
+	Removed        int `json:"removed"`
+	Changed        int `json:"changed"`
+	VersionDrift   int `json:"version_drift"`
+	IntegrityDrift int `json:"integrity_drift"`
+	MetadataDrift  int `json:"metadata_drift"`
+	Downgrades     int `json:"downgrades"`
+	DeepDeps       int `json:"deep_deps"` // new deps at or beyond the deep-dependency threshold
+}
+
+// Summary returns the diff's headline counts.
+func (r DiffResult) Summary() DiffStats {
+	s := DiffStats{
+		Added:   len(r.Added),
+		Removed: len(r.Removed),
+		Changed: len(r.Changed),
+	}
+	if r.DriftSummary != nil {
+		s.VersionDrift = r.DriftSummary.VersionDrift
+		s.IntegrityDrift = r.DriftSummary.IntegrityDrift
+		s.MetadataDrift = r.DriftSummary.MetadataDrift
+	}
+	for _, c := range r.Changed {
+		if IsDowngrade(c.Before.Version, c.After.Version) {
+			s.Downgrades++
+		}
+	}
+	if r.Dependencies != nil && r.Dependencies.DepthSummary != nil {
+		s.DeepDeps = r.Dependencies.DepthSummary.Deep
+	}
+	return s
+}
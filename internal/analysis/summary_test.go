@@ -0,0 +1,38 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestDiffResult_Summary(t *testing.T) {
+	result := DiffResult{
+		Added:   []sbom.Component{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+		Removed: []sbom.Component{{ID: "d"}},
+		Changed: []ChangedComponent{
+			{ID: "e", Before: sbom.Component{Version: "1.0.0"}, After: sbom.Component{Version: "2.0.0"}},
+			{ID: "f", Before: sbom.Component{Version: "2.1.0"}, After: sbom.Component{Version: "2.0.0"}},
+			{ID: "g", Before: sbom.Component{Version: "1.0"}, After: sbom.Component{Version: "1.0"}},
+		},
+		DriftSummary: &DriftSummary{VersionDrift: 2, IntegrityDrift: 1},
+		Dependencies: &DependencyDiff{DepthSummary: &DepthSummary{Depth3Plus: 2, Deep: 2}},
+	}
+
+	want := DiffStats{
+		Added:          3,
+		Removed:        1,
+		Changed:        3,
+		VersionDrift:   2,
+		IntegrityDrift: 1,
+		Downgrades:     1,
+		DeepDeps:       2,
+	}
+	if got := result.Summary(); got != want {
+		t.Errorf("Summary() = %+v, want %+v", got, want)
+	}
+
+	if got := (DiffResult{}).Summary(); got != (DiffStats{}) {
+		t.Errorf("empty Summary() = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// TypeChange pairs a removed and an added component that share a name but
+// not a PURL type, e.g. a package re-catalogued from pkg:deb to pkg:golang.
+type TypeChange struct {
+	Name     string         `json:"name"`
+	FromType string         `json:"from_type"`
+	ToType   string         `json:"to_type"`
+	Before   sbom.Component `json:"before"`
+	After    sbom.Component `json:"after"`
+}
+
+// DetectTypeChanges pairs removed and added components by name when the
+// PURL type differs. A name is only paired when exactly one removed and one
+// added component carry it. The components stay in Added and Removed.
+func DetectTypeChanges(removed, added []sbom.Component) []TypeChange {
+	var changes []TypeChange
+	for _, p := range pairByName(removed, added, hasPURLType) {
+		from, to := ExtractPURLType(p.before.PURL), ExtractPURLType(p.after.PURL)
+		if from == to {
+			continue
+		}
+		changes = append(changes, TypeChange{
+			Name:     p.name,
+			FromType: from,
+			ToType:   to,
+			Before:   p.before,
+			After:    p.after,
+		})
+	}
+	return changes
+}
+
+func hasPURLType(c sbom.Component) bool {
+	return ExtractPURLType(c.PURL) != "unknown"
+}
+
+// namePair is a removed and an added component sharing a name.
+type namePair struct {
+	name          string
+	before, after sbom.Component
+}
+
+// pairByName pairs the removed and added components that keep accepts by
+// name, sorted by name. A name is only paired when exactly one removed and
+// one added component carry it.
+func pairByName(removed, added []sbom.Component, keep func(sbom.Component) bool) []namePair {
+	removedByName, addedByName := groupByName(removed, keep), groupByName(added, keep)
+
+	var pairs []namePair
+	for name, before := range removedByName {
+		after, ok := addedByName[name]
+		if !ok || len(before) != 1 || len(after) != 1 {
+			continue
+		}
+		pairs = append(pairs, namePair{name: name, before: before[0], after: after[0]})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].name < pairs[j].name })
+	return pairs
+}
+
+// groupByName groups the named components that keep accepts by name.
+func groupByName(comps []sbom.Component, keep func(sbom.Component) bool) map[string][]sbom.Component {
+	byName := make(map[string][]sbom.Component)
+	for _, c := range comps {
+		if c.Name != "" && keep(c) {
+			byName[c.Name] = append(byName[c.Name], c)
+		}
+	}
+	return byName
+}
@@ -0,0 +1,87 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestDetectTypeChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		removed []sbom.Component
+		added   []sbom.Component
+		want    []string // "name:from->to"
+	}{
+		{
+			"single match",
+			[]sbom.Component{{Name: "yaml", Version: "3.0.1", PURL: "pkg:deb/debian/yaml@3.0.1"}},
+			[]sbom.Component{{Name: "yaml", Version: "v3.0.1", PURL: "pkg:golang/gopkg.in/yaml@v3.0.1"}},
+			[]string{"yaml:deb->golang"},
+		},
+		{
+			"same type is not paired",
+			[]sbom.Component{{Name: "lodash", PURL: "pkg:npm/lodash@4.17.20"}},
+			[]sbom.Component{{Name: "lodash", PURL: "pkg:npm/%40legacy/lodash@4.17.21"}},
+			nil,
+		},
+		{
+			"ambiguous added side",
+			[]sbom.Component{{Name: "requests", PURL: "pkg:deb/debian/requests@2.28"}},
+			[]sbom.Component{
+				{Name: "requests", PURL: "pkg:pypi/requests@2.31.0"},
+				{Name: "requests", PURL: "pkg:rpm/fedora/requests@2.31.0"},
+			},
+			nil,
+		},
+		{
+			"ambiguous removed side",
+			[]sbom.Component{
+				{Name: "zlib", PURL: "pkg:deb/debian/zlib@1.2"},
+				{Name: "zlib", PURL: "pkg:apk/alpine/zlib@1.2"},
+			},
+			[]sbom.Component{{Name: "zlib", PURL: "pkg:generic/zlib@1.3"}},
+			nil,
+		},
+		{
+			"no purl is not paired",
+			[]sbom.Component{{Name: "foo"}},
+			[]sbom.Component{{Name: "foo", PURL: "pkg:npm/foo@1.0"}},
+			nil,
+		},
+		{
+			"unrelated names",
+			[]sbom.Component{{Name: "a", PURL: "pkg:deb/debian/a@1"}},
+			[]sbom.Component{{Name: "b", PURL: "pkg:npm/b@1"}},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectTypeChanges(tt.removed, tt.added)
+			if len(got) != len(tt.want) {
+				t.Fatalf("DetectTypeChanges() = %+v, want %v", got, tt.want)
+			}
+			for i, tc := range got {
+				if s := tc.Name + ":" + tc.FromType + "->" + tc.ToType; s != tt.want[i] {
+					t.Errorf("change %d = %s, want %s", i, s, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiffComponents_TypeChanged(t *testing.T) {
+	before := []sbom.Component{{ID: "pkg:deb/yaml", Name: "yaml", Version: "3.0.1", PURL: "pkg:deb/debian/yaml@3.0.1"}}
+	after := []sbom.Component{{ID: "pkg:golang/gopkg.in/yaml", Name: "yaml", Version: "v3.0.1", PURL: "pkg:golang/gopkg.in/yaml@v3.0.1"}}
+
+	result := DiffComponents(before, after)
+
+	if len(result.Added) != 1 || len(result.Removed) != 1 {
+		t.Errorf("expected the pair to stay in added/removed, got %d/%d", len(result.Added), len(result.Removed))
+	}
+	if len(result.TypeChanged) != 1 || result.TypeChanged[0].ToType != "golang" {
+		t.Errorf("expected one deb->golang type change, got %+v", result.TypeChanged)
+	}
+}
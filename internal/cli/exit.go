@@ -0,0 +1,9 @@
+package cli
+
+// Exit codes. A policy error outranks a plain difference.
+const (
+	ExitOK     = 0 // no differences, or single-file and convert success
+	ExitDiff   = 1 // differences found, no policy errors
+	ExitPolicy = 2 // policy or --fail-on errors
+	ExitError  = 3 // usage, parse or I/O error
+)
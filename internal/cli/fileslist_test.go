@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadFileList(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{"one per line", "a.json\nb.json\n", []string{"a.json", "b.json"}, false},
+		{"comments and blanks", "# services\n\n  a.json  \n# b.json\nc.json", []string{"a.json", "c.json"}, false},
+		{"CRLF", "a.json\r\nb.json\r\n", []string{"a.json", "b.json"}, false},
+		{"only comments", "# nothing yet\n\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "list.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadFileList(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := ReadFileList(filepath.Join(t.TempDir(), "nope.txt")); err == nil {
+			t.Error("expected an error for a missing manifest")
+		}
+	})
+}
@@ -3,32 +3,84 @@ package cli
 import (
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type ParseWarning struct {
+	Code    string `json:"code"` // stable kind for programmatic use; see README
 	File    string `json:"file"`
 	Message string `json:"message"`
 	Field   string `json:"field,omitempty"`
 }
 
+// Warning codes raised by the CLI itself. Component checks use the
+// sbom.Issue* codes.
+const (
+	WarnUnknownFormat        = "unknown_format"
+	WarnParseError           = "parse_error"
+	WarnDroppedInvalid       = "dropped_invalid"
+	WarnExactDuplicate       = "exact_duplicate"
+	WarnConflictingDuplicate = "conflicting_duplicate"
+	WarnMixedFormats         = "mixed_formats"
+)
+
 type ParseOptions struct {
-	Strict   bool
-	Warnings []ParseWarning
+	Strict      bool
+	KeepRaw     bool // keep per-component RawJSON; only interactive views need it
+	DropInvalid bool // drop components with empty or placeholder names
+	Warnings    []ParseWarning
+
+	mu sync.Mutex // guards Warnings in AddWarning
 }
 
 type Options struct {
-	Files        []string
-	JSONOutput   bool
-	PolicyFile   string
-	Strict       bool
-	Format       string // text, json, sarif, junit, markdown, patch
-	Interactive  bool
-	WebServer    bool
-	WebPort      int
-	NoPager      bool
-	Convert      bool
-	TargetFormat string // cyclonedx, cdx, spdx, syft
-	OutputFile   string
+	Files            []string
+	JSONOutput       bool
+	PolicyFiles      []string // --policy may repeat; files are merged
+	FailOn           string   // comma-separated --fail-on conditions
+	MinSeverity      string   // --min-severity: hide policy violations below error or warning
+	Only             []string // --only diff categories to display
+	DepsOnly         bool     // --diff-deps-only: show and exit on dependency graph changes only
+	IgnoreVersions   bool     // --ignore-version-changes: routine version bumps do not exit 1
+	IgnoreMetadata   bool     // --ignore-metadata-drift: metadata-only changes do not exit 1
+	DeepDepThreshold string   // --deep-dep-threshold: depth from which new deps are risky
+	MaxItems         string   // --max-items: entries shown per text/markdown listing
+	Strict           bool
+	StrictLicenses   bool   // --strict-licenses: unknown SPDX license IDs are errors
+	Format           string // text, json, sarif, junit, markdown, patch
+	Interactive      bool
+	WebServer        bool
+	WebPort          int
+	MaxUploadMB      string // --max-upload-mb: web upload size limit
+	UploadTimeout    string // --upload-timeout: web upload read deadline
+	NoPager          bool
+	Summary          bool
+	NoColor          bool
+	Timing           bool // print per-phase elapsed time to stderr
+	DropInvalid      bool
+	CPEList          bool     // print CPEs of added/changed components instead of the diff
+	Fingerprint      bool     // print the diff fingerprint instead of the diff
+	BeforeOnly       bool     // --before-only: dump the normalized "before" components instead of diffing
+	AfterOnly        bool     // --after-only: dump the normalized "after" components instead of diffing
+	Explain          bool     // report which identity field matched each component
+	SortRisk         bool     // --sort-risk: order added/changed by descending risk score
+	StatsDelta       bool     // --stats-delta: compare aggregate stats alongside the diff
+	GroupByType      bool     // --group-by-type: text/markdown diff sections per PURL type
+	DiffLicenses     bool     // --diff-licenses: report license changes only
+	BaselineStats    string   // --baseline-stats: fail if coverage regresses against this file
+	List             bool     // --list: print the component inventory instead of stats
+	Include          []string // --include: --list only components matching these patterns
+	Exclude          []string // --exclude: --list leaves out components matching these patterns
+	StatePath        string   // --state: snapshot store to diff against and update
+	StateLabel       string   // --label: name of the snapshot this run records
+	Git              bool     // --git <rev1> <rev2> <path>: diff a committed file across revisions
+	GitArgs          []string // the values of --git; main checks there are three
+	Validate         bool     // lint a single SBOM's structure instead of showing stats
+	Merge            bool     // combine all files into one inventory for stats
+	FilesFrom        string   // --files-from: manifest of further input paths, one per line
+	Convert          bool
+	TargetFormat     string // cyclonedx, cdx, spdx, syft
+	OutputFile       string
 }
 
 func DefaultParseOptions() ParseOptions {
@@ -38,8 +90,13 @@ func DefaultParseOptions() ParseOptions {
 	}
 }
 
-func (p *ParseOptions) AddWarning(file, message, field string) {
+// AddWarning records a warning. It is safe for concurrent use; read
+// Warnings once the goroutines calling it are done.
+func (p *ParseOptions) AddWarning(code, file, message, field string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.Warnings = append(p.Warnings, ParseWarning{
+		Code:    code,
 		File:    file,
 		Message: message,
 		Field:   field,
@@ -66,9 +123,30 @@ func ParseArgs(args []string) Options {
 			opts.Strict = true
 		case "--tolerant":
 			opts.Strict = false
+		case "--strict-licenses":
+			opts.StrictLicenses = true
 		case "--policy":
 			if i+1 < len(args) {
-				opts.PolicyFile = args[i+1]
+				opts.PolicyFiles = append(opts.PolicyFiles, args[i+1])
+				i++
+			}
+		case "--only":
+			if i+1 < len(args) {
+				for _, c := range strings.Split(args[i+1], ",") {
+					if c = strings.TrimSpace(c); c != "" {
+						opts.Only = append(opts.Only, c)
+					}
+				}
+				i++
+			}
+		case "--fail-on":
+			if i+1 < len(args) {
+				opts.FailOn = args[i+1]
+				i++
+			}
+		case "--min-severity":
+			if i+1 < len(args) {
+				opts.MinSeverity = args[i+1]
 				i++
 			}
 		case "--format", "-f":
@@ -93,8 +171,102 @@ func ParseArgs(args []string) Options {
 			opts.Interactive = true
 		case "--no-pager":
 			opts.NoPager = true
+		case "--no-color":
+			opts.NoColor = true
+		case "--timing":
+			opts.Timing = true
+		case "--drop-invalid":
+			opts.DropInvalid = true
+		case "--diff-deps-only":
+			opts.DepsOnly = true
+		case "--ignore-version-changes":
+			opts.IgnoreVersions = true
+		case "--ignore-metadata-drift":
+			opts.IgnoreMetadata = true
+		case "--deep-dep-threshold":
+			if i+1 < len(args) {
+				opts.DeepDepThreshold = args[i+1]
+				i++
+			}
+		case "--max-items":
+			if i+1 < len(args) {
+				opts.MaxItems = args[i+1]
+				i++
+			}
+		case "--merge":
+			opts.Merge = true
+		case "--validate":
+			opts.Validate = true
+		case "--cpe-list":
+			opts.CPEList = true
+		case "--fingerprint":
+			opts.Fingerprint = true
+		case "--before-only":
+			opts.BeforeOnly = true
+		case "--after-only":
+			opts.AfterOnly = true
+		case "--explain":
+			opts.Explain = true
+		case "--sort-risk":
+			opts.SortRisk = true
+		case "--stats-delta":
+			opts.StatsDelta = true
+		case "--group-by-type":
+			opts.GroupByType = true
+		case "--diff-licenses":
+			opts.DiffLicenses = true
+		case "--baseline-stats":
+			if i+1 < len(args) {
+				opts.BaselineStats = args[i+1]
+				i++
+			}
+		case "--list", "--components":
+			opts.List = true
+		case "--include":
+			if i+1 < len(args) {
+				opts.Include = append(opts.Include, args[i+1])
+				i++
+			}
+		case "--exclude":
+			if i+1 < len(args) {
+				opts.Exclude = append(opts.Exclude, args[i+1])
+				i++
+			}
+		case "--git":
+			opts.Git = true
+			for j := 0; j < 3 && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-"); j++ {
+				opts.GitArgs = append(opts.GitArgs, args[i+1])
+				i++
+			}
+		case "--files-from":
+			if i+1 < len(args) {
+				opts.FilesFrom = args[i+1]
+				i++
+			}
+		case "--state":
+			if i+1 < len(args) {
+				opts.StatePath = args[i+1]
+				i++
+			}
+		case "--label":
+			if i+1 < len(args) {
+				opts.StateLabel = args[i+1]
+				i++
+			}
+		case "--summary", "--quiet", "-q":
+			opts.Summary = true
 		case "-web", "--web":
 			opts.WebServer = true
+		case "--max-upload-mb":
+			if i+1 < len(args) {
+				opts.MaxUploadMB = args[i+1]
+				i++
+			}
+		case "--upload-timeout":
+			if i+1 < len(args) {
+				opts.UploadTimeout = args[i+1]
+				i++
+			}
 		case "--port":
 			if i+1 < len(args) {
 				port, _ := strconv.Atoi(args[i+1])
@@ -1,6 +1,11 @@
 package cli
 
-import "testing"
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
 
 func TestParseArgs(t *testing.T) {
 	t.Run("parses strict flag", func(t *testing.T) {
@@ -55,8 +60,29 @@ func TestParseArgs(t *testing.T) {
 		args := []string{"sbomlyze", "a.json", "b.json", "--policy", "policy.json"}
 		opts := ParseArgs(args)
 
-		if opts.PolicyFile != "policy.json" {
-			t.Errorf("expected PolicyFile=policy.json, got %s", opts.PolicyFile)
+		if len(opts.PolicyFiles) != 1 || opts.PolicyFiles[0] != "policy.json" {
+			t.Errorf("expected PolicyFiles=[policy.json], got %v", opts.PolicyFiles)
+		}
+	})
+
+	t.Run("collects repeated policy flags", func(t *testing.T) {
+		args := []string{"sbomlyze", "a.json", "b.json", "--policy", "base.json", "--policy", "team.json"}
+		opts := ParseArgs(args)
+
+		if len(opts.PolicyFiles) != 2 || opts.PolicyFiles[0] != "base.json" || opts.PolicyFiles[1] != "team.json" {
+			t.Errorf("expected PolicyFiles=[base.json team.json], got %v", opts.PolicyFiles)
+		}
+	})
+
+	t.Run("parses fail-on flag", func(t *testing.T) {
+		args := []string{"sbomlyze", "a.json", "b.json", "--fail-on", "integrity-drift,added>5"}
+		opts := ParseArgs(args)
+
+		if opts.FailOn != "integrity-drift,added>5" {
+			t.Errorf("expected FailOn=integrity-drift,added>5, got %s", opts.FailOn)
+		}
+		if len(opts.Files) != 2 {
+			t.Errorf("expected 2 files, got %d", len(opts.Files))
 		}
 	})
 
@@ -69,6 +95,109 @@ func TestParseArgs(t *testing.T) {
 		}
 	})
 
+	t.Run("parses no-color flag", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "a.json", "--no-color"})
+		if !opts.NoColor {
+			t.Error("expected NoColor=true from --no-color flag")
+		}
+	})
+
+	t.Run("parses repeated and comma-separated only flags", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "a.json", "b.json", "--only", "added,removed", "--only", "integrity"})
+		want := []string{"added", "removed", "integrity"}
+		if len(opts.Only) != len(want) {
+			t.Fatalf("expected Only=%v, got %v", want, opts.Only)
+		}
+		for i := range want {
+			if opts.Only[i] != want[i] {
+				t.Errorf("expected Only=%v, got %v", want, opts.Only)
+			}
+		}
+	})
+
+	t.Run("parses ignore-version-changes flag", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "a.json", "b.json", "--ignore-version-changes"})
+		if !opts.IgnoreVersions {
+			t.Error("expected IgnoreVersions=true")
+		}
+		if len(opts.Files) != 2 {
+			t.Errorf("expected 2 files, got %v", opts.Files)
+		}
+	})
+
+	t.Run("parses files-from flag", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "--files-from", "list.txt", "--merge"})
+		if opts.FilesFrom != "list.txt" {
+			t.Errorf("expected FilesFrom=list.txt, got %q", opts.FilesFrom)
+		}
+		if len(opts.Files) != 0 {
+			t.Errorf("expected no files, got %v", opts.Files)
+		}
+	})
+
+	t.Run("parses ignore-metadata-drift flag", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "a.json", "b.json", "--ignore-metadata-drift"})
+		if !opts.IgnoreMetadata {
+			t.Error("expected IgnoreMetadata=true")
+		}
+	})
+
+	t.Run("parses max-items flag", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "a.json", "b.json", "--max-items", "5"})
+		if opts.MaxItems != "5" {
+			t.Errorf("expected MaxItems=5, got %q", opts.MaxItems)
+		}
+		if len(opts.Files) != 2 {
+			t.Errorf("expected 2 files, got %v", opts.Files)
+		}
+	})
+
+	t.Run("parses deep-dep-threshold flag", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "a.json", "b.json", "--deep-dep-threshold", "2"})
+		if opts.DeepDepThreshold != "2" {
+			t.Errorf("expected DeepDepThreshold=2, got %q", opts.DeepDepThreshold)
+		}
+		if len(opts.Files) != 2 {
+			t.Errorf("expected 2 files, got %v", opts.Files)
+		}
+	})
+
+	t.Run("parses timing flag", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "a.json", "--timing"})
+		if !opts.Timing {
+			t.Error("expected Timing=true from --timing flag")
+		}
+	})
+
+	t.Run("parses drop-invalid flag", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "a.json", "--drop-invalid"})
+		if !opts.DropInvalid {
+			t.Error("expected DropInvalid=true from --drop-invalid flag")
+		}
+	})
+
+	t.Run("parses summary flag", func(t *testing.T) {
+		for _, flag := range []string{"--summary", "--quiet", "-q"} {
+			opts := ParseArgs([]string{"sbomlyze", "a.json", "b.json", flag})
+			if !opts.Summary {
+				t.Errorf("expected Summary=true from %s flag", flag)
+			}
+			if len(opts.Files) != 2 {
+				t.Errorf("expected 2 files with %s, got %d", flag, len(opts.Files))
+			}
+		}
+	})
+
+	t.Run("parses git revisions", func(t *testing.T) {
+		opts := ParseArgs([]string{"sbomlyze", "--git", "v1.0", "HEAD", "sbom.json", "--json"})
+		if !opts.Git || !slices.Equal(opts.GitArgs, []string{"v1.0", "HEAD", "sbom.json"}) {
+			t.Errorf("expected --git with 3 values, got %v %v", opts.Git, opts.GitArgs)
+		}
+		if len(opts.Files) != 0 || opts.Format != "json" {
+			t.Errorf("expected no files and json format, got %v %q", opts.Files, opts.Format)
+		}
+	})
+
 	t.Run("collects files", func(t *testing.T) {
 		args := []string{"sbomlyze", "a.json", "b.json"}
 		opts := ParseArgs(args)
@@ -108,6 +237,20 @@ func TestParseArgs_PortFlag(t *testing.T) {
 	}
 }
 
+func TestParseArgs_UploadLimitFlags(t *testing.T) {
+	args := []string{"sbomlyze", "-web", "--max-upload-mb", "100", "--upload-timeout", "30s"}
+	opts := ParseArgs(args)
+	if opts.MaxUploadMB != "100" {
+		t.Errorf("expected MaxUploadMB=100, got %q", opts.MaxUploadMB)
+	}
+	if opts.UploadTimeout != "30s" {
+		t.Errorf("expected UploadTimeout=30s, got %q", opts.UploadTimeout)
+	}
+	if !opts.WebServer || len(opts.Files) != 0 {
+		t.Errorf("expected web mode without files, got %+v", opts)
+	}
+}
+
 func TestParseArgs_PortInvalid(t *testing.T) {
 	args := []string{"sbomlyze", "-web", "--port", "abc"}
 	opts := ParseArgs(args)
@@ -144,7 +287,7 @@ func TestDefaultParseOptions(t *testing.T) {
 
 func TestAddWarning(t *testing.T) {
 	opts := DefaultParseOptions()
-	opts.AddWarning("test.json", "missing field", "name")
+	opts.AddWarning(WarnParseError, "test.json", "missing field", "name")
 	if len(opts.Warnings) != 1 {
 		t.Fatalf("expected 1 warning, got %d", len(opts.Warnings))
 	}
@@ -157,17 +300,40 @@ func TestAddWarning(t *testing.T) {
 	if opts.Warnings[0].Field != "name" {
 		t.Errorf("expected field=name, got %s", opts.Warnings[0].Field)
 	}
+	if opts.Warnings[0].Code != WarnParseError {
+		t.Errorf("expected code=%s, got %s", WarnParseError, opts.Warnings[0].Code)
+	}
 }
 
 func TestAddWarning_Multiple(t *testing.T) {
 	opts := DefaultParseOptions()
-	opts.AddWarning("a.json", "warn1", "")
-	opts.AddWarning("b.json", "warn2", "field2")
+	opts.AddWarning(WarnParseError, "a.json", "warn1", "")
+	opts.AddWarning(WarnParseError, "b.json", "warn2", "field2")
 	if len(opts.Warnings) != 2 {
 		t.Errorf("expected 2 warnings, got %d", len(opts.Warnings))
 	}
 }
 
+// Run with -race to catch unsynchronized appends.
+func TestAddWarning_Concurrent(t *testing.T) {
+	const goroutines, perGoroutine = 50, 100
+	opts := DefaultParseOptions()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				opts.AddWarning(WarnParseError, fmt.Sprintf("%d.json", g), "warn", "")
+			}
+		}()
+	}
+	wg.Wait()
+	if len(opts.Warnings) != goroutines*perGoroutine {
+		t.Errorf("expected %d warnings, got %d", goroutines*perGoroutine, len(opts.Warnings))
+	}
+}
+
 func TestParseArgs_ConvertMode(t *testing.T) {
 	args := []string{"sbomlyze", "convert", "input.json", "--to", "spdx"}
 	opts := ParseArgs(args)
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -12,31 +13,99 @@ func PrintUsage() {
 	fmt.Fprintf(os.Stderr, "Modes:\n")
 	fmt.Fprintf(os.Stderr, "  Single file:  sbomlyze <sbom> [--json]        - Show statistics\n")
 	fmt.Fprintf(os.Stderr, "  Interactive:  sbomlyze <sbom> -i              - Interactive explorer\n")
+	fmt.Fprintf(os.Stderr, "  Validate:     sbomlyze <sbom> --validate      - Lint SBOM structure\n")
+	fmt.Fprintf(os.Stderr, "  Merge:        sbomlyze <sbom>... --merge      - Statistics for combined SBOMs\n")
+	fmt.Fprintf(os.Stderr, "  List:         sbomlyze <sbom> --list          - Component inventory\n")
 	fmt.Fprintf(os.Stderr, "  Convert:      sbomlyze convert <sbom> --to <fmt> - Convert SBOM format\n")
 	fmt.Fprintf(os.Stderr, "  Web server:   sbomlyze -web [--port 8080]     - Web UI explorer\n")
-	fmt.Fprintf(os.Stderr, "  Two files:    sbomlyze <sbom1> <sbom2> [...]  - Show diff\n\n")
+	fmt.Fprintf(os.Stderr, "  Two files:    sbomlyze <sbom1> <sbom2> [...]  - Show diff\n")
+	fmt.Fprintf(os.Stderr, "  Snapshots:    sbomlyze <sbom> --state <file>  - Diff against the previous run\n")
+	fmt.Fprintf(os.Stderr, "  Git:          sbomlyze --git <rev1> <rev2> <path> - Diff a committed SBOM\n")
+	fmt.Fprintf(os.Stderr, "  Directories:  sbomlyze <dir1> <dir2> [...]    - Diff SBOMs paired by file name\n\n")
 	fmt.Fprintf(os.Stderr, "Options:\n")
 	fmt.Fprintf(os.Stderr, "  -i, --interactive   Interactive TUI explorer\n")
 	fmt.Fprintf(os.Stderr, "  -web, --web         Start web UI server\n")
 	fmt.Fprintf(os.Stderr, "  --port <port>       Web server port (default 8080)\n")
+	fmt.Fprintf(os.Stderr, "  --max-upload-mb <n> Web server upload size limit in MB (default 500)\n")
+	fmt.Fprintf(os.Stderr, "  --upload-timeout <d>\n")
+	fmt.Fprintf(os.Stderr, "                      Web server upload read deadline, e.g. 30s (default 5m)\n")
 	fmt.Fprintf(os.Stderr, "  --json              Output in JSON format (shortcut for --format json)\n")
-	fmt.Fprintf(os.Stderr, "  --format <format>   Output format: text, json, sarif, junit, markdown, html, patch\n")
-	fmt.Fprintf(os.Stderr, "  --policy <file>     Policy file for CI checks\n")
+	fmt.Fprintf(os.Stderr, "  --format <format>   Output format: text, text-wide, table, json, jsonl, sarif,\n")
+	fmt.Fprintf(os.Stderr, "                      junit, markdown, html, patch, cyclonedx, spdx-diff,\n")
+	fmt.Fprintf(os.Stderr, "                      ndjson-events, summary-json, prometheus, diffstat, badge\n")
+	fmt.Fprintf(os.Stderr, "  --policy <file>     Policy file for CI checks (repeatable; files are merged)\n")
+	fmt.Fprintf(os.Stderr, "  --fail-on <conds>   Exit 2 only on conditions: integrity-drift, added>N,\n")
+	fmt.Fprintf(os.Stderr, "                      removed>N, changed>N, deep-deps, downgrade\n")
+	fmt.Fprintf(os.Stderr, "  --min-severity <s>  Show only policy violations of severity s or higher:\n")
+	fmt.Fprintf(os.Stderr, "                      error or warning (default); the exit code is unchanged\n")
 	fmt.Fprintf(os.Stderr, "  --strict            Fail on parse warnings\n")
 	fmt.Fprintf(os.Stderr, "  --tolerant          Continue on parse warnings (default)\n")
+	fmt.Fprintf(os.Stderr, "  --strict-licenses   Fail on license IDs not on the SPDX license list\n")
+	fmt.Fprintf(os.Stderr, "  --drop-invalid      Drop components with empty or NOASSERTION names\n")
 	fmt.Fprintf(os.Stderr, "  --no-pager          Disable automatic paging of output\n")
+	fmt.Fprintf(os.Stderr, "  --no-color          Plain ASCII text output (also honors NO_COLOR)\n")
+	fmt.Fprintf(os.Stderr, "  --summary, -q       Text diff: print only counts and drift summary\n")
+	fmt.Fprintf(os.Stderr, "  --only <category>   Show only these diff sections (repeatable): added,\n")
+	fmt.Fprintf(os.Stderr, "                      removed, changed, integrity, deps, duplicates\n")
+	fmt.Fprintf(os.Stderr, "  --diff-deps-only    Show only dependency graph changes; exit 1 only if the graph changed\n")
+	fmt.Fprintf(os.Stderr, "  --ignore-version-changes\n")
+	fmt.Fprintf(os.Stderr, "                      Routine version bumps are shown but do not exit 1\n")
+	fmt.Fprintf(os.Stderr, "  --ignore-metadata-drift\n")
+	fmt.Fprintf(os.Stderr, "                      Metadata-only changes are shown but do not exit 1\n")
+	fmt.Fprintf(os.Stderr, "  --deep-dep-threshold <n>\n")
+	fmt.Fprintf(os.Stderr, "                      Depth from which new dependencies are risky (default 3)\n")
+	fmt.Fprintf(os.Stderr, "  --max-items <n>     Text/markdown: show at most n entries per section\n")
+	fmt.Fprintf(os.Stderr, "  --timing            Print elapsed time per phase to stderr\n")
+	fmt.Fprintf(os.Stderr, "  --validate          Single file: check references and IDs; exit 1 on errors\n")
+	fmt.Fprintf(os.Stderr, "  --merge             Stats for several files combined into one inventory\n")
+	fmt.Fprintf(os.Stderr, "  --files-from <file> Read further input paths from file, one per line (# comments)\n")
+	fmt.Fprintf(os.Stderr, "  --list, --components\n")
+	fmt.Fprintf(os.Stderr, "                      Print name, version, type and PURL of every component\n")
+	fmt.Fprintf(os.Stderr, "  --include <pattern> --list: only components matching (repeatable)\n")
+	fmt.Fprintf(os.Stderr, "  --exclude <pattern> --list: leave out components matching (repeatable)\n")
+	fmt.Fprintf(os.Stderr, "  --baseline-stats <file>\n")
+	fmt.Fprintf(os.Stderr, "                      Stats: exit 2 if coverage drops below a prior --json run\n")
+	fmt.Fprintf(os.Stderr, "                      or the file's min_coverage thresholds\n")
+	fmt.Fprintf(os.Stderr, "  --cpe-list          Diff: print CPEs of added/changed components, one per line\n")
+	fmt.Fprintf(os.Stderr, "  --fingerprint       Diff: print a stable hash of the changes, to detect repeat diffs\n")
+	fmt.Fprintf(os.Stderr, "  --before-only, --after-only\n")
+	fmt.Fprintf(os.Stderr, "                      Diff: print one side's normalized components as JSON, no diff\n")
+	fmt.Fprintf(os.Stderr, "  --sort-risk         Diff: list added/changed components by risk score, highest first\n")
+	fmt.Fprintf(os.Stderr, "  --stats-delta       Diff: compare aggregate stats (types, licenses, coverage, deps)\n")
+	fmt.Fprintf(os.Stderr, "  --group-by-type     Text/markdown diff: group added/removed/changed by PURL type\n")
+	fmt.Fprintf(os.Stderr, "  --diff-licenses     Diff: report license changes only (text, markdown, json);\n")
+	fmt.Fprintf(os.Stderr, "                      exit 1 only if licenses changed\n")
+	fmt.Fprintf(os.Stderr, "  --explain           Show which identity field (purl, cpe, bomref, ...) matched\n")
+	fmt.Fprintf(os.Stderr, "                      each component, in text and JSON output\n")
+	fmt.Fprintf(os.Stderr, "  --state <file>      Diff one SBOM against the latest snapshot in file, then\n")
+	fmt.Fprintf(os.Stderr, "                      record it there (the first run only records)\n")
+	fmt.Fprintf(os.Stderr, "  --label <name>      Name of the snapshot --state records (default: current time)\n")
+	fmt.Fprintf(os.Stderr, "  --git <rev1> <rev2> <path>  Diff the SBOM committed at path between two git\n")
+	fmt.Fprintf(os.Stderr, "                      revisions, without checking either out\n")
 	fmt.Fprintf(os.Stderr, "  --to <format>       Target format for convert: cyclonedx (cdx), spdx, syft\n")
 	fmt.Fprintf(os.Stderr, "  -o, --output <file> Output file for convert (default: stdout)\n")
 	fmt.Fprintf(os.Stderr, "  --version, -v       Show version information\n")
 	fmt.Fprintf(os.Stderr, "  --help, -h          Show this help message\n\n")
 	fmt.Fprintf(os.Stderr, "Output Formats:\n")
 	fmt.Fprintf(os.Stderr, "  text      Human-readable text (default)\n")
+	fmt.Fprintf(os.Stderr, "  text-wide Text with changed components as an aligned table\n")
+	fmt.Fprintf(os.Stderr, "  table     Coverage, type and license tables only (single file only)\n")
 	fmt.Fprintf(os.Stderr, "  json      JSON for programmatic consumption\n")
+	fmt.Fprintf(os.Stderr, "  jsonl     One component per line (single file only)\n")
 	fmt.Fprintf(os.Stderr, "  sarif     SARIF for GitHub Code Scanning\n")
 	fmt.Fprintf(os.Stderr, "  junit     JUnit XML for CI test results\n")
 	fmt.Fprintf(os.Stderr, "  markdown  Markdown for PR comments\n")
 	fmt.Fprintf(os.Stderr, "  html      Self-contained HTML for auditors and reports\n")
-	fmt.Fprintf(os.Stderr, "  patch     JSON Patch (RFC 6902) for automation\n\n")
+	fmt.Fprintf(os.Stderr, "  patch     JSON Patch (RFC 6902) for automation\n")
+	fmt.Fprintf(os.Stderr, "  cyclonedx CycloneDX BOM of added and changed components (diff only)\n")
+	fmt.Fprintf(os.Stderr, "  spdx-diff SPDX document of added, removed and changed packages (diff only)\n")
+	fmt.Fprintf(os.Stderr, "  ndjson-events\n")
+	fmt.Fprintf(os.Stderr, "            One JSON event per diff entry, for streaming (diff only)\n")
+	fmt.Fprintf(os.Stderr, "  summary-json\n")
+	fmt.Fprintf(os.Stderr, "            Headline counts and violation counts only (diff only)\n")
+	fmt.Fprintf(os.Stderr, "  prometheus\n")
+	fmt.Fprintf(os.Stderr, "            Diff counts as Prometheus textfile metrics (diff only)\n")
+	fmt.Fprintf(os.Stderr, "  badge     shields.io endpoint JSON: size and license coverage, or diff status\n\n")
 	fmt.Fprintf(os.Stderr, "Interactive Mode Keys:\n")
 	fmt.Fprintf(os.Stderr, "  ↑/↓, j/k    Navigate components\n")
 	fmt.Fprintf(os.Stderr, "  Enter       View component details\n")
@@ -47,6 +116,11 @@ func PrintUsage() {
 	fmt.Fprintf(os.Stderr, "  c           Clear all filters\n")
 	fmt.Fprintf(os.Stderr, "  Esc         Go back\n")
 	fmt.Fprintf(os.Stderr, "  q           Quit\n\n")
+	fmt.Fprintf(os.Stderr, "Exit Codes:\n")
+	fmt.Fprintf(os.Stderr, "  0           No differences (or single-file/convert success)\n")
+	fmt.Fprintf(os.Stderr, "  1           Differences found (or --validate errors), no policy errors\n")
+	fmt.Fprintf(os.Stderr, "  2           Policy errors or --fail-on conditions triggered\n")
+	fmt.Fprintf(os.Stderr, "  3           Usage, parse or I/O error\n\n")
 	fmt.Fprintf(os.Stderr, "Examples:\n")
 	fmt.Fprintf(os.Stderr, "  sbomlyze image.json                        # Show SBOM statistics\n")
 	fmt.Fprintf(os.Stderr, "  sbomlyze image.json -i                     # Interactive explorer\n")
@@ -54,6 +128,7 @@ func PrintUsage() {
 	fmt.Fprintf(os.Stderr, "  sbomlyze -web --port 3000                  # Start web UI at localhost:3000\n")
 	fmt.Fprintf(os.Stderr, "  sbomlyze before.json after.json            # Compare two SBOMs\n")
 	fmt.Fprintf(os.Stderr, "  sbomlyze a.json b.json --policy p.json     # Apply policy checks\n")
+	fmt.Fprintf(os.Stderr, "  sbomlyze a.json b.json --fail-on added>5   # Fail only on thresholds\n")
 	fmt.Fprintf(os.Stderr, "  sbomlyze a.json b.json --format sarif      # SARIF for GitHub\n")
 	fmt.Fprintf(os.Stderr, "  sbomlyze a.json b.json --format markdown   # Markdown for PR\n")
 	fmt.Fprintf(os.Stderr, "  sbomlyze a.json b.json --format html       # HTML report for auditors\n")
@@ -75,3 +150,11 @@ func PrintWarnings(warnings []ParseWarning) {
 		fmt.Println()
 	}
 }
+
+// PrintWarningsTo writes one "warn:" line per warning to w. Formats whose
+// stdout must hold only machine-readable output send warnings to stderr.
+func PrintWarningsTo(w io.Writer, warnings []ParseWarning) {
+	for _, pw := range warnings {
+		fmt.Fprintf(w, "warn: [%s] %s\n", pw.File, pw.Message)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -105,6 +107,13 @@ func componentToCDX(c sbom.Component) cdx.Component {
 		})
 	}
 
+	for _, name := range slices.Sorted(maps.Keys(c.Properties)) {
+		props = append(props, cdx.Property{
+			Name:  name,
+			Value: c.Properties[name],
+		})
+	}
+
 	if len(props) > 0 {
 		comp.Properties = &props
 	}
@@ -0,0 +1,63 @@
+package convert
+
+import (
+	"io"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// Diff status values for the sbomlyze:diff:status property.
+const (
+	DiffStatusAdded   = "added"
+	DiffStatusChanged = "changed"
+)
+
+// WriteCycloneDXDiff writes a CDX 1.5 BOM holding only the added and changed
+// components of a diff. info describes the "after" SBOM.
+func WriteCycloneDXDiff(w io.Writer, result analysis.DiffResult, info sbom.SBOMInfo) error {
+	enc := cdx.NewBOMEncoder(w, cdx.BOMFileFormatJSON)
+	enc.SetPretty(true)
+	return enc.Encode(BuildCycloneDXDiff(result, info))
+}
+
+// BuildCycloneDXDiff builds the delta BOM. Each component carries a
+// sbomlyze:diff:status property; changed ones also record the drift type
+// and previous version.
+func BuildCycloneDXDiff(result analysis.DiffResult, info sbom.SBOMInfo) *cdx.BOM {
+	bom := cdx.NewBOM()
+	bom.SpecVersion = cdx.SpecVersion1_5
+	bom.SerialNumber = generateURNUUID()
+	bom.Metadata = buildCDXMetadata(info)
+	(*bom.Metadata.Tools.Components)[0].Version = "diff"
+
+	comps := make([]cdx.Component, 0, len(result.Added)+len(result.Changed))
+	for _, c := range result.Added {
+		comps = append(comps, withDiffProps(componentToCDX(c), cdx.Property{
+			Name: "sbomlyze:diff:status", Value: DiffStatusAdded,
+		}))
+	}
+	for _, ch := range result.Changed {
+		props := []cdx.Property{{Name: "sbomlyze:diff:status", Value: DiffStatusChanged}}
+		if ch.Drift != nil {
+			props = append(props, cdx.Property{Name: "sbomlyze:diff:drift", Value: string(ch.Drift.Type)})
+		}
+		if ch.Before.Version != ch.After.Version {
+			props = append(props, cdx.Property{Name: "sbomlyze:diff:previousVersion", Value: ch.Before.Version})
+		}
+		comps = append(comps, withDiffProps(componentToCDX(ch.After), props...))
+	}
+	bom.Components = &comps
+
+	return bom
+}
+
+func withDiffProps(comp cdx.Component, props ...cdx.Property) cdx.Component {
+	if comp.Properties != nil {
+		props = append(props, *comp.Properties...)
+	}
+	comp.Properties = &props
+	return comp
+}
@@ -0,0 +1,81 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func diffProp(c cdx.Component, name string) string {
+	if c.Properties == nil {
+		return ""
+	}
+	for _, p := range *c.Properties {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+func TestWriteCycloneDXDiff(t *testing.T) {
+	result := analysis.DiffResult{
+		Added: []sbom.Component{
+			{ID: "pkg:npm/new", Name: "new", Version: "1.0.0", PURL: "pkg:npm/new@1.0.0", Language: "javascript"},
+		},
+		Removed: []sbom.Component{
+			{ID: "pkg:npm/gone", Name: "gone", Version: "2.0.0"},
+		},
+		Changed: []analysis.ChangedComponent{
+			{
+				ID:     "pkg:npm/lodash",
+				Name:   "lodash",
+				Before: sbom.Component{Name: "lodash", Version: "4.17.20"},
+				After:  sbom.Component{Name: "lodash", Version: "4.17.21", PURL: "pkg:npm/lodash@4.17.21"},
+				Drift:  &analysis.DriftInfo{Type: analysis.DriftTypeVersion},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCycloneDXDiff(&buf, result, sbom.SBOMInfo{}); err != nil {
+		t.Fatalf("WriteCycloneDXDiff failed: %v", err)
+	}
+
+	var bom cdx.BOM
+	if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+		t.Fatalf("output is not a CycloneDX BOM: %v", err)
+	}
+	if bom.Components == nil || len(*bom.Components) != 2 {
+		t.Fatalf("expected 2 components (added + changed), got %v", bom.Components)
+	}
+
+	tests := []struct {
+		name     string
+		comp     cdx.Component
+		status   string
+		previous string
+		language string
+	}{
+		{"added", (*bom.Components)[0], DiffStatusAdded, "", "javascript"},
+		{"changed", (*bom.Components)[1], DiffStatusChanged, "4.17.20", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffProp(tt.comp, "sbomlyze:diff:status"); got != tt.status {
+				t.Errorf("status = %q, want %q", got, tt.status)
+			}
+			if got := diffProp(tt.comp, "sbomlyze:diff:previousVersion"); got != tt.previous {
+				t.Errorf("previousVersion = %q, want %q", got, tt.previous)
+			}
+			if got := diffProp(tt.comp, "sbomlyze:language"); got != tt.language {
+				t.Errorf("language = %q, want %q", got, tt.language)
+			}
+		})
+	}
+}
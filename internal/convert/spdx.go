@@ -112,6 +112,14 @@ func componentToSPDXPackage(c sbom.Component, index int) *spdxv23.Package {
 		})
 	}
 
+	for _, ref := range c.References {
+		refs = append(refs, &spdxv23.PackageExternalReference{
+			Category: common.CategorySecurity,
+			RefType:  ref.Type,
+			Locator:  ref.Locator,
+		})
+	}
+
 	if len(refs) > 0 {
 		pkg.PackageExternalReferences = refs
 	}
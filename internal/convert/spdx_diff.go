@@ -0,0 +1,73 @@
+package convert
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	spdxjson "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/spdx/v2/common"
+	spdxv23 "github.com/spdx/tools-golang/spdx/v2/v2_3"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// DiffStatusRemoved marks removed packages; only the SPDX delta lists them.
+const DiffStatusRemoved = "removed"
+
+// WriteSPDXDiff writes an SPDX 2.3 document holding the added, removed and
+// changed packages of a diff. info describes the "after" SBOM.
+func WriteSPDXDiff(w io.Writer, result analysis.DiffResult, info sbom.SBOMInfo) error {
+	return spdxjson.Write(BuildSPDXDiff(result, info), w, spdxjson.Indent("  "))
+}
+
+// BuildSPDXDiff builds the delta document. The document DESCRIBES each
+// package, and the relationship comment carries its diff status; changed
+// packages appear with their "after" version and note the previous one.
+func BuildSPDXDiff(result analysis.DiffResult, info sbom.SBOMInfo) *spdxv23.Document {
+	doc := &spdxv23.Document{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXIdentifier:    "DOCUMENT",
+		DocumentName:      spdxDocumentName(info) + "-diff",
+		DocumentNamespace: "https://sbomlyze.dev/spdx/diff/" + generateUUID(),
+		CreationInfo: &spdxv23.CreationInfo{
+			Created: time.Now().UTC().Format(time.RFC3339),
+			Creators: []common.Creator{
+				{CreatorType: "Tool", Creator: "sbomlyze-diff"},
+			},
+		},
+	}
+
+	add := func(c sbom.Component, status, comment string) {
+		pkg := componentToSPDXPackage(c, len(doc.Packages))
+		// the same name and version can be both removed and added
+		// under different IDs
+		pkg.PackageSPDXIdentifier = common.ElementID(fmt.Sprintf("%s-%s", status, pkg.PackageSPDXIdentifier))
+		pkg.PackageComment = comment
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, &spdxv23.Relationship{
+			RefA:                common.MakeDocElementID("", "DOCUMENT"),
+			RefB:                common.MakeDocElementID("", string(pkg.PackageSPDXIdentifier)),
+			Relationship:        common.TypeRelationshipDescribe,
+			RelationshipComment: status,
+		})
+	}
+
+	for _, c := range result.Added {
+		add(c, DiffStatusAdded, "")
+	}
+	for _, c := range result.Removed {
+		add(c, DiffStatusRemoved, "")
+	}
+	for _, ch := range result.Changed {
+		comment := ""
+		if ch.Before.Version != ch.After.Version {
+			comment = "previous version: " + ch.Before.Version
+		}
+		add(ch.After, DiffStatusChanged, comment)
+	}
+
+	return doc
+}
@@ -0,0 +1,74 @@
+package convert
+
+import (
+	"bytes"
+	"testing"
+
+	spdxjson "github.com/spdx/tools-golang/json"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestWriteSPDXDiff(t *testing.T) {
+	result := analysis.DiffResult{
+		Added: []sbom.Component{
+			{ID: "pkg:npm/left-pad", Name: "left-pad", Version: "1.3.0", PURL: "pkg:npm/left-pad@1.3.0"},
+		},
+		Removed: []sbom.Component{
+			{ID: "pkg:pypi/left-pad", Name: "left-pad", Version: "1.3.0"},
+		},
+		Changed: []analysis.ChangedComponent{
+			{
+				ID:     "pkg:npm/lodash",
+				Name:   "lodash",
+				Before: sbom.Component{Name: "lodash", Version: "4.17.20"},
+				After:  sbom.Component{Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSPDXDiff(&buf, result, sbom.SBOMInfo{}); err != nil {
+		t.Fatalf("WriteSPDXDiff failed: %v", err)
+	}
+	doc, err := spdxjson.Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("output does not re-parse as SPDX: %v", err)
+	}
+	if len(doc.Packages) != 3 {
+		t.Fatalf("expected 3 packages, got %d", len(doc.Packages))
+	}
+	if len(doc.Relationships) != 3 {
+		t.Fatalf("expected 3 relationships, got %d", len(doc.Relationships))
+	}
+
+	tests := []struct {
+		status  string
+		version string
+		comment string
+	}{
+		{DiffStatusAdded, "1.3.0", ""},
+		{DiffStatusRemoved, "1.3.0", ""},
+		{DiffStatusChanged, "4.17.21", "previous version: 4.17.20"},
+	}
+	seen := make(map[string]bool)
+	for i, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			pkg, rel := doc.Packages[i], doc.Relationships[i]
+			if seen[string(pkg.PackageSPDXIdentifier)] {
+				t.Errorf("duplicate SPDXID %s", pkg.PackageSPDXIdentifier)
+			}
+			seen[string(pkg.PackageSPDXIdentifier)] = true
+			if pkg.PackageVersion != tt.version {
+				t.Errorf("version = %q, want %q", pkg.PackageVersion, tt.version)
+			}
+			if pkg.PackageComment != tt.comment {
+				t.Errorf("comment = %q, want %q", pkg.PackageComment, tt.comment)
+			}
+			if rel.RefB.ElementRefID != pkg.PackageSPDXIdentifier || rel.RelationshipComment != tt.status {
+				t.Errorf("relationship = %+v, want DESCRIBES %s (%s)", rel, pkg.PackageSPDXIdentifier, tt.status)
+			}
+		})
+	}
+}
@@ -255,6 +255,27 @@ func TestWriteSPDX_CPEExternalRefs(t *testing.T) {
 	}
 }
 
+func TestWriteSPDX_SecurityReferences(t *testing.T) {
+	comps := sampleSPDXComponents()
+	advisory := sbom.Reference{Type: "advisory", Locator: "https://example.com/advisories/AXIOS-1"}
+	comps[0].References = []sbom.Reference{advisory}
+
+	var buf bytes.Buffer
+	if err := WriteSPDX(&buf, comps, sampleSPDXInfo()); err != nil {
+		t.Fatalf("WriteSPDX failed: %v", err)
+	}
+	got, err := sbom.ParseSPDXFromBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to re-parse: %v", err)
+	}
+	if len(got[0].References) != 1 || got[0].References[0] != advisory {
+		t.Errorf("References = %+v, want [%+v]", got[0].References, advisory)
+	}
+	if len(got[0].CPEs) != 2 {
+		t.Errorf("expected 2 CPEs, got %v", got[0].CPEs)
+	}
+}
+
 func TestWriteSPDX_LicenseConcluded(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -110,19 +110,20 @@ func WriteSyft(w io.Writer, comps []sbom.Component, info sbom.SBOMInfo) error {
 		idToArtifactID[c.ID] = a.ID
 	}
 
+	// "parent dependency-of child": the dependency is the parent
 	for _, c := range comps {
-		parentID, ok := idToArtifactID[c.ID]
+		dependentID, ok := idToArtifactID[c.ID]
 		if !ok {
 			continue
 		}
 		for _, depID := range c.Dependencies {
-			childID, ok := idToArtifactID[depID]
+			dependencyID, ok := idToArtifactID[depID]
 			if !ok {
 				continue
 			}
 			doc.ArtifactRelationships = append(doc.ArtifactRelationships, syftRelationship{
-				Parent: parentID,
-				Child:  childID,
+				Parent: dependencyID,
+				Child:  dependentID,
 				Type:   "dependency-of",
 			})
 		}
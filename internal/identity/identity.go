@@ -15,33 +15,90 @@ type ComponentIdentity struct {
 	Name      string
 }
 
-// ComputeID generates a canonical identity. Precedence: PURL > CPE > BOM-ref/SPDXID > namespace/name > name.
+// SchemeVersion is the identity scheme ComputeID uses. The IDs produced by a
+// scheme never change; a new derivation gets a new ComputeIDvN and version.
+const SchemeVersion = 2
+
+// Basis names the ComputeID level that produced an ID.
+type Basis string
+
+const (
+	BasisPURL      Basis = "purl"
+	BasisCPE       Basis = "cpe"
+	BasisBOMRef    Basis = "bomref"
+	BasisSPDXID    Basis = "spdxid"
+	BasisNamespace Basis = "name+namespace"
+	BasisName      Basis = "name"
+)
+
+// ComputeID generates a canonical identity using the current scheme.
 func ComputeID(c ComponentIdentity) string {
+	return ComputeIDv2(c)
+}
+
+// ExplainID returns the basis of ComputeID's result for c.
+func ExplainID(c ComponentIdentity) Basis {
+	_, basis := compute(c, NormalizeCPE)
+	return basis
+}
+
+// ComputeIDv1 derives an ID from the first level that applies:
+//
+//  1. PURL: NormalizePURL, i.e. version, qualifiers and subpath dropped, and
+//     the distro namespace dropped for rpm/deb/apk/alpm
+//     ("pkg:rpm/amzn/bash@5.2" -> "pkg:rpm/bash").
+//  2. CPE: the first CPE 2.2 or 2.3 with a concrete vendor and product,
+//     as "cpe:<vendor>:<product>".
+//  3. BOM-ref, then SPDXID, prefixed "ref:".
+//  4. "<namespace>/<name>".
+//  5. The name as-is.
+//
+// A versionless PURL is used unchanged, so "pkg:npm/lodash" and
+// "pkg:npm/lodash@4.17.21" share an ID. The version is cut at the last "@",
+// so an npm scope must be percent-encoded ("pkg:npm/%40babel/core"), as the
+// PURL spec requires. No case folding is applied at any level.
+//
+// v1 splits a CPE 2.3 on every colon, keeps escapes and only skips a "*" or
+// empty vendor or product; see ComputeIDv2.
+func ComputeIDv1(c ComponentIdentity) string {
+	id, _ := compute(c, normalizeCPEv1)
+	return id
+}
+
+// ComputeIDv2 is ComputeIDv1 with CPEs read by NormalizeCPE: CPE 2.3 is
+// split on unescaped colons and unescaped, CPE 2.2 is percent-decoded, and
+// a vendor or product that is ANY or NA skips the CPE.
+func ComputeIDv2(c ComponentIdentity) string {
+	id, _ := compute(c, NormalizeCPE)
+	return id
+}
+
+func compute(c ComponentIdentity, normalizeCPE func(string) string) (string, Basis) {
 	if c.PURL != "" {
-		return NormalizePURL(c.PURL)
+		return NormalizePURL(c.PURL), BasisPURL
 	}
 
 	if len(c.CPEs) > 0 {
 		for _, cpe := range c.CPEs {
-			normalized := NormalizeCPE(cpe)
+			normalized := normalizeCPE(cpe)
 			if normalized != "" {
-				return normalized
+				return normalized, BasisCPE
 			}
 		}
 	}
 
 	if c.BOMRef != "" {
-		return "ref:" + c.BOMRef
+		return "ref:" + c.BOMRef, BasisBOMRef
 	}
 	if c.SPDXID != "" {
-		return "ref:" + c.SPDXID
+		return "ref:" + c.SPDXID, BasisSPDXID
 	}
 
 	if c.Namespace != "" {
-		return c.Namespace + "/" + c.Name
+		return c.Namespace + "/" + c.Name, BasisNamespace
 	}
 
-	return c.Name
+	return c.Name, BasisName
 }
 
 var osPackageTypes = map[string]bool{
@@ -92,8 +149,8 @@ func ExtractPURLVersion(purl string) string {
 	return ""
 }
 
-// NormalizeCPE extracts vendor:product from CPE 2.2/2.3.
-func NormalizeCPE(cpe string) string {
+// normalizeCPEv1 is NormalizeCPE as of identity scheme v1.
+func normalizeCPEv1(cpe string) string {
 	if cpe == "" {
 		return ""
 	}
@@ -125,3 +182,89 @@ func NormalizeCPE(cpe string) string {
 
 	return ""
 }
+
+// NormalizeCPE extracts vendor:product from CPE 2.2/2.3. A wildcard or
+// not-applicable vendor or product ("*", "-" or empty) yields "": such CPEs
+// would otherwise give unrelated components the same identity.
+func NormalizeCPE(cpe string) string {
+	if cpe == "" {
+		return ""
+	}
+
+	if strings.HasPrefix(cpe, "cpe:2.3:") {
+		// formatted string binding: backslash escapes, "*" is ANY, "-" is NA
+		parts := splitCPE23(cpe)
+		if len(parts) >= 5 {
+			vendor, product := parts[3], parts[4]
+			if isCPEValue(vendor) && isCPEValue(product) {
+				return "cpe:" + unescapeCPE23(vendor) + ":" + unescapeCPE23(product)
+			}
+		}
+		return ""
+	}
+
+	if strings.HasPrefix(cpe, "cpe:/") {
+		// URI binding: percent-encoding, empty is ANY, "-" is NA
+		rest := cpe[5:] // remove "cpe:/"
+		parts := strings.Split(rest, ":")
+		if len(parts) >= 3 {
+			vendor, err1 := url.PathUnescape(parts[1])
+			product, err2 := url.PathUnescape(parts[2])
+			if err1 == nil && err2 == nil && isCPEValue(parts[1]) && isCPEValue(parts[2]) {
+				return "cpe:" + escapeCPEColons(vendor) + ":" + escapeCPEColons(product)
+			}
+		}
+		return ""
+	}
+
+	return ""
+}
+
+// isCPEValue reports whether a raw (still escaped) CPE attribute names a
+// value rather than ANY or NA.
+func isCPEValue(attr string) bool {
+	return attr != "" && attr != "*" && attr != "-"
+}
+
+// splitCPE23 splits a CPE 2.3 formatted string on unescaped colons,
+// leaving escapes in place.
+func splitCPE23(cpe string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(cpe); i++ {
+		switch cpe[i] {
+		case '\\':
+			i++ // skip the escaped character
+		case ':':
+			parts = append(parts, cpe[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, cpe[start:])
+}
+
+// unescapeCPE23 drops the backslash escapes of a CPE 2.3 attribute,
+// except before a colon, which keeps the normalized "cpe:vendor:product"
+// unambiguous.
+func unescapeCPE23(attr string) string {
+	if !strings.Contains(attr, "\\") {
+		return attr
+	}
+	var sb strings.Builder
+	for i := 0; i < len(attr); i++ {
+		if attr[i] == '\\' && i+1 < len(attr) {
+			i++
+			if attr[i] == ':' {
+				sb.WriteByte('\\')
+			}
+		}
+		sb.WriteByte(attr[i])
+	}
+	return sb.String()
+}
+
+// escapeCPEColons escapes colons in a decoded CPE 2.2 attribute the way
+// unescapeCPE23 leaves them, so both bindings normalize alike.
+func escapeCPEColons(attr string) string {
+	return strings.ReplaceAll(attr, ":", "\\:")
+}
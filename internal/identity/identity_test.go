@@ -129,6 +129,51 @@ func TestNormalizeCPE(t *testing.T) {
 			"cpe:2.3:a:some-vendor:some-product:1.0:*:*:*:*:*:*:*",
 			"cpe:some-vendor:some-product",
 		},
+		{
+			"splits CPE 2.3 on unescaped colons only",
+			`cpe:2.3:a:acme\:labs:widget\:core:1.0:*:*:*:*:*:*:*`,
+			`cpe:acme\:labs:widget\:core`,
+		},
+		{
+			"unescapes CPE 2.3 punctuation",
+			`cpe:2.3:a:node\.js:node\-fetch:2.6.7:*:*:*:*:node\.js:*:*`,
+			"cpe:node.js:node-fetch",
+		},
+		{
+			"decodes CPE 2.2 percent-encoding like the 2.3 binding",
+			"cpe:/a:acme%3alabs:widget%3acore:1.0",
+			`cpe:acme\:labs:widget\:core`,
+		},
+		{
+			"returns empty for wildcard CPE 2.3 vendor",
+			"cpe:2.3:a:*:log4j:2.14.1:*:*:*:*:*:*:*",
+			"",
+		},
+		{
+			"returns empty for not-applicable CPE 2.3 product",
+			"cpe:2.3:a:apache:-:*:*:*:*:*:*:*:*",
+			"",
+		},
+		{
+			"keeps an escaped hyphen as a product",
+			`cpe:2.3:a:apache:\-:*:*:*:*:*:*:*:*`,
+			"cpe:apache:-",
+		},
+		{
+			"returns empty for empty CPE 2.2 vendor",
+			"cpe:/a::struts:2.5.10",
+			"",
+		},
+		{
+			"returns empty for not-applicable CPE 2.2 product",
+			"cpe:/a:apache:-",
+			"",
+		},
+		{
+			"returns empty for truncated CPE 2.3",
+			"cpe:2.3:a:apache",
+			"",
+		},
 		{
 			"returns empty for invalid CPE",
 			"not-a-cpe",
@@ -180,6 +225,15 @@ func TestIdentityPrecedence(t *testing.T) {
 		}
 	})
 
+	t.Run("wildcard CPEs fall through instead of colliding", func(t *testing.T) {
+		a := ComponentIdentity{Name: "a", CPEs: []string{"cpe:2.3:a:*:*:1.0:*:*:*:*:*:*:*"}, BOMRef: "ref-a"}
+		b := ComponentIdentity{Name: "b", CPEs: []string{"cpe:2.3:a:-:-:2.0:*:*:*:*:*:*:*"}, BOMRef: "ref-b"}
+
+		if idA, idB := ComputeID(a), ComputeID(b); idA != "ref:ref-a" || idB != "ref:ref-b" {
+			t.Errorf("expected BOMRef-based IDs, got %s and %s", idA, idB)
+		}
+	})
+
 	t.Run("BOMRef takes precedence over name", func(t *testing.T) {
 		c := ComponentIdentity{
 			Name:   "test",
@@ -194,6 +248,29 @@ func TestIdentityPrecedence(t *testing.T) {
 	})
 }
 
+func TestExplainID(t *testing.T) {
+	tests := []struct {
+		name string
+		c    ComponentIdentity
+		want Basis
+	}{
+		{"purl", ComponentIdentity{Name: "lodash", PURL: "pkg:npm/lodash@4.17.21", BOMRef: "ref-1"}, BasisPURL},
+		{"cpe", ComponentIdentity{Name: "openssl", CPEs: []string{"cpe:2.3:a:openssl:openssl:3.0.0:*:*:*:*:*:*:*"}}, BasisCPE},
+		{"invalid cpe falls through", ComponentIdentity{Name: "x", CPEs: []string{"bogus"}, BOMRef: "ref-1"}, BasisBOMRef},
+		{"spdxid", ComponentIdentity{Name: "x", SPDXID: "SPDXRef-x"}, BasisSPDXID},
+		{"namespace", ComponentIdentity{Name: "x", Namespace: "org"}, BasisNamespace},
+		{"name only", ComponentIdentity{Name: "internal-tool"}, BasisName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExplainID(tt.c); got != tt.want {
+				t.Errorf("ExplainID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestComponentIDWithSPDXID(t *testing.T) {
 	t.Run("uses SPDXID when available", func(t *testing.T) {
 		c := ComponentIdentity{
@@ -367,3 +444,75 @@ func TestComputeID_AllEmpty(t *testing.T) {
 	}
 }
 
+// TestComputeIDv1_Pinned pins scheme v1. If this fails, the identity scheme
+// changed: add a new ComputeIDvN instead of editing v1.
+func TestComputeIDv1_Pinned(t *testing.T) {
+	tests := []struct {
+		name string
+		in   ComponentIdentity
+		want string
+	}{
+		{"purl with version", ComponentIdentity{PURL: "pkg:npm/lodash@4.17.21"}, "pkg:npm/lodash"},
+		{"versionless purl", ComponentIdentity{PURL: "pkg:npm/lodash"}, "pkg:npm/lodash"},
+		{"purl qualifiers and subpath", ComponentIdentity{PURL: "pkg:golang/github.com/a/b@v1.2.3?type=module#sub"}, "pkg:golang/github.com/a/b"},
+		{"encoded npm scope", ComponentIdentity{PURL: "pkg:npm/%40babel/core@7.0.0"}, "pkg:npm/%40babel/core"},
+		{"distro namespace dropped", ComponentIdentity{PURL: "pkg:rpm/amzn/bash@5.2.15-1.amzn2023?arch=x86_64"}, "pkg:rpm/bash"},
+		{"apk distro namespace dropped", ComponentIdentity{PURL: "pkg:apk/alpine/musl@1.2.4-r2"}, "pkg:apk/musl"},
+		{"purl beats cpe", ComponentIdentity{PURL: "pkg:pypi/requests@2.31.0", CPEs: []string{"cpe:2.3:a:python:requests:2.31.0:*:*:*:*:*:*:*"}}, "pkg:pypi/requests"},
+		{"cpe 2.3", ComponentIdentity{CPEs: []string{"cpe:2.3:a:openssl:openssl:3.0.0:*:*:*:*:*:*:*"}}, "cpe:openssl:openssl"},
+		{"cpe 2.2", ComponentIdentity{CPEs: []string{"cpe:/a:apache:httpd:2.4.57"}}, "cpe:apache:httpd"},
+		{"wildcard cpe skipped", ComponentIdentity{CPEs: []string{"cpe:2.3:a:*:*:1.0:*:*:*:*:*:*:*", "cpe:2.3:a:zlib:zlib:1.3:*:*:*:*:*:*:*"}}, "cpe:zlib:zlib"},
+		{"not-applicable cpe kept", ComponentIdentity{CPEs: []string{"cpe:2.3:a:-:-:1.0:*:*:*:*:*:*:*"}, BOMRef: "comp-123"}, "cpe:-:-"},
+		{"cpe 2.3 escapes kept", ComponentIdentity{CPEs: []string{`cpe:2.3:a:node\.js:node\-fetch:2.6.7:*:*:*:*:*:*:*`}}, `cpe:node\.js:node\-fetch`},
+		{"cpe 2.3 split on escaped colon", ComponentIdentity{CPEs: []string{`cpe:2.3:a:acme\:labs:widget:1.0:*:*:*:*:*:*:*`}}, `cpe:acme\:labs`},
+		{"cpe 2.2 not decoded", ComponentIdentity{CPEs: []string{"cpe:/a:acme%3alabs:widget:1.0"}}, "cpe:acme%3alabs:widget"},
+		{"bom-ref", ComponentIdentity{BOMRef: "comp-123", SPDXID: "SPDXRef-x", Name: "x"}, "ref:comp-123"},
+		{"spdxid", ComponentIdentity{SPDXID: "SPDXRef-Package-x", Name: "x"}, "ref:SPDXRef-Package-x"},
+		{"namespace and name", ComponentIdentity{Namespace: "com.example", Name: "mypackage"}, "com.example/mypackage"},
+		{"name only", ComponentIdentity{Name: "Simple-Package"}, "Simple-Package"},
+		{"empty", ComponentIdentity{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeIDv1(tt.in); got != tt.want {
+				t.Errorf("ComputeIDv1() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestComputeIDv2_Pinned pins scheme v2 where it differs from v1.
+func TestComputeIDv2_Pinned(t *testing.T) {
+	tests := []struct {
+		name string
+		in   ComponentIdentity
+		want string
+	}{
+		{"purl with version", ComponentIdentity{PURL: "pkg:npm/lodash@4.17.21"}, "pkg:npm/lodash"},
+		{"cpe 2.3", ComponentIdentity{CPEs: []string{"cpe:2.3:a:openssl:openssl:3.0.0:*:*:*:*:*:*:*"}}, "cpe:openssl:openssl"},
+		{"not-applicable cpe skipped", ComponentIdentity{CPEs: []string{"cpe:2.3:a:-:-:1.0:*:*:*:*:*:*:*"}, BOMRef: "comp-123"}, "ref:comp-123"},
+		{"cpe 2.3 unescaped", ComponentIdentity{CPEs: []string{`cpe:2.3:a:node\.js:node\-fetch:2.6.7:*:*:*:*:*:*:*`}}, "cpe:node.js:node-fetch"},
+		{"cpe 2.3 escaped colon kept", ComponentIdentity{CPEs: []string{`cpe:2.3:a:acme\:labs:widget:1.0:*:*:*:*:*:*:*`}}, `cpe:acme\:labs:widget`},
+		{"cpe 2.2 decoded", ComponentIdentity{CPEs: []string{"cpe:/a:acme%3alabs:widget:1.0"}}, `cpe:acme\:labs:widget`},
+		{"name only", ComponentIdentity{Name: "Simple-Package"}, "Simple-Package"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeIDv2(tt.in); got != tt.want {
+				t.Errorf("ComputeIDv2() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeID_CurrentScheme(t *testing.T) {
+	if SchemeVersion != 2 {
+		t.Fatalf("SchemeVersion = %d; update ComputeID and this test together", SchemeVersion)
+	}
+	c := ComponentIdentity{CPEs: []string{`cpe:2.3:a:node\.js:node:18.0.0:*:*:*:*:*:*:*`}}
+	if ComputeID(c) != ComputeIDv2(c) {
+		t.Errorf("ComputeID should use scheme v2")
+	}
+}
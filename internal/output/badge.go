@@ -0,0 +1,68 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/policy"
+)
+
+// Badge is the --format badge object, a shields.io endpoint badge:
+// https://shields.io/badges/endpoint-badge
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// Badge colors, from good to bad. All are shields.io named colors.
+const (
+	BadgeColorGood    = "brightgreen"
+	BadgeColorOK      = "yellow"
+	BadgeColorWarn    = "orange"
+	BadgeColorBad     = "red"
+	BadgeColorUnknown = "lightgrey"
+)
+
+// NewStatsBadge summarizes one SBOM: its size and license coverage,
+// colored by the coverage.
+func NewStatsBadge(stats analysis.Stats) Badge {
+	b := Badge{SchemaVersion: 1, Label: "sbom"}
+	if stats.TotalComponents == 0 {
+		b.Message, b.Color = "0 components", BadgeColorUnknown
+		return b
+	}
+	cov := stats.CoveragePercent.License
+	b.Message = fmt.Sprintf("%d components | %.0f%% licensed", stats.TotalComponents, cov)
+	switch {
+	case cov >= 90:
+		b.Color = BadgeColorGood
+	case cov >= 70:
+		b.Color = BadgeColorOK
+	case cov >= 40:
+		b.Color = BadgeColorWarn
+	default:
+		b.Color = BadgeColorBad
+	}
+	return b
+}
+
+// NewDiffBadge summarizes a diff as clean, changed or policy failed.
+// changed is whether the diff counts as a difference for the exit code.
+func NewDiffBadge(changed bool, stats analysis.DiffStats, violations []policy.Violation) Badge {
+	b := Badge{SchemaVersion: 1, Label: "sbom diff"}
+	switch {
+	case policy.HasErrors(violations):
+		b.Message, b.Color = "policy failed", BadgeColorBad
+	case !changed:
+		b.Message, b.Color = "clean", BadgeColorGood
+	default:
+		b.Message = fmt.Sprintf("+%d -%d ~%d", stats.Added, stats.Removed, stats.Changed)
+		b.Color = BadgeColorOK
+		if stats.IntegrityDrift > 0 || len(violations) > 0 {
+			b.Color = BadgeColorWarn
+		}
+	}
+	return b
+}
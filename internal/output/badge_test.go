@@ -0,0 +1,92 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/policy"
+)
+
+// shieldsColors are the shields.io named colors badges may use.
+var shieldsColors = map[string]bool{
+	"brightgreen": true, "green": true, "yellowgreen": true, "yellow": true,
+	"orange": true, "red": true, "blue": true, "lightgrey": true,
+}
+
+// checkBadge asserts the fields shields.io requires of an endpoint badge.
+func checkBadge(t *testing.T, b Badge) {
+	t.Helper()
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["schemaVersion"] != 1.0 {
+		t.Errorf("schemaVersion = %v, want 1", got["schemaVersion"])
+	}
+	for _, key := range []string{"label", "message", "color"} {
+		if s, _ := got[key].(string); s == "" {
+			t.Errorf("%s missing or empty in %s", key, data)
+		}
+	}
+	if !shieldsColors[b.Color] {
+		t.Errorf("color %q is not a shields.io named color", b.Color)
+	}
+}
+
+func TestNewStatsBadge(t *testing.T) {
+	tests := []struct {
+		name        string
+		stats       analysis.Stats
+		wantMessage string
+		wantColor   string
+	}{
+		{"full coverage", analysis.Stats{TotalComponents: 10, CoveragePercent: analysis.CoveragePercent{License: 100}}, "10 components | 100% licensed", BadgeColorGood},
+		{"partial coverage", analysis.Stats{TotalComponents: 124, CoveragePercent: analysis.CoveragePercent{License: 82.3}}, "124 components | 82% licensed", BadgeColorOK},
+		{"low coverage", analysis.Stats{TotalComponents: 5, CoveragePercent: analysis.CoveragePercent{License: 40}}, "5 components | 40% licensed", BadgeColorWarn},
+		{"no licenses", analysis.Stats{TotalComponents: 5}, "5 components | 0% licensed", BadgeColorBad},
+		{"empty sbom", analysis.Stats{}, "0 components", BadgeColorUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewStatsBadge(tt.stats)
+			checkBadge(t, b)
+			if b.Message != tt.wantMessage || b.Color != tt.wantColor {
+				t.Errorf("badge = %q %s, want %q %s", b.Message, b.Color, tt.wantMessage, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestNewDiffBadge(t *testing.T) {
+	counts := analysis.DiffStats{Added: 3, Removed: 1, Changed: 2}
+	tests := []struct {
+		name        string
+		changed     bool
+		stats       analysis.DiffStats
+		violations  []policy.Violation
+		wantMessage string
+		wantColor   string
+	}{
+		{"clean", false, analysis.DiffStats{}, nil, "clean", BadgeColorGood},
+		{"changed", true, counts, nil, "+3 -1 ~2", BadgeColorOK},
+		{"integrity drift", true, analysis.DiffStats{Changed: 1, IntegrityDrift: 1}, nil, "+0 -0 ~1", BadgeColorWarn},
+		{"policy warning", true, counts, []policy.Violation{{Rule: "warn_new_transitive", Severity: policy.SeverityWarning}}, "+3 -1 ~2", BadgeColorWarn},
+		{"policy error", true, counts, []policy.Violation{{Rule: "max_added", Severity: policy.SeverityError}}, "policy failed", BadgeColorBad},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewDiffBadge(tt.changed, tt.stats, tt.violations)
+			checkBadge(t, b)
+			if b.Message != tt.wantMessage || b.Color != tt.wantColor {
+				t.Errorf("badge = %q %s, want %q %s", b.Message, b.Color, tt.wantMessage, tt.wantColor)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package output
+
+// icon returns emoji, or ascii when o.NoColor is set.
+func (o TextOptions) icon(emoji, ascii string) string {
+	if o.NoColor {
+		return ascii
+	}
+	return emoji
+}
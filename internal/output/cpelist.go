@@ -0,0 +1,32 @@
+package output
+
+import (
+	"slices"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/identity"
+)
+
+// CPEList returns the sorted, deduplicated normalized CPEs of added and
+// changed components, for feeding to a vulnerability lookup.
+func CPEList(result analysis.DiffResult) []string {
+	seen := make(map[string]bool)
+	var cpes []string
+	add := func(raw []string) {
+		for _, c := range raw {
+			n := identity.NormalizeCPE(c)
+			if n != "" && !seen[n] {
+				seen[n] = true
+				cpes = append(cpes, n)
+			}
+		}
+	}
+	for _, c := range result.Added {
+		add(c.CPEs)
+	}
+	for _, c := range result.Changed {
+		add(c.After.CPEs)
+	}
+	slices.Sort(cpes)
+	return cpes
+}
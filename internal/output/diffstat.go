@@ -0,0 +1,15 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+)
+
+// Diffstat is the one-line --format diffstat summary of a diff between two
+// SBOMs, in the style of git diff --stat:
+// "2 files, +3 -1 ~2 components, 1 integrity, 0 deep-deps".
+func Diffstat(stats analysis.DiffStats) string {
+	return fmt.Sprintf("2 files, +%d -%d ~%d components, %d integrity, %d deep-deps",
+		stats.Added, stats.Removed, stats.Changed, stats.IntegrityDrift, stats.DeepDeps)
+}
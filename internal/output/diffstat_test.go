@@ -0,0 +1,29 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+)
+
+func TestDiffstat(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats analysis.DiffStats
+		want  string
+	}{
+		{"no changes", analysis.DiffStats{}, "2 files, +0 -0 ~0 components, 0 integrity, 0 deep-deps"},
+		{
+			"counts",
+			analysis.DiffStats{Added: 3, Removed: 1, Changed: 2, IntegrityDrift: 1, VersionDrift: 1, DeepDeps: 4},
+			"2 files, +3 -1 ~2 components, 1 integrity, 4 deep-deps",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diffstat(tt.stats); got != tt.want {
+				t.Errorf("Diffstat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/policy"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// Event types of the ndjson-events format, in the order they are written.
+const (
+	EventAdded          = "added"
+	EventRemoved        = "removed"
+	EventChanged        = "changed"
+	EventDepAdded       = "dependency_added"
+	EventDepRemoved     = "dependency_removed"
+	EventTransitiveNew  = "transitive_new"
+	EventTransitiveLost = "transitive_lost"
+	EventViolation      = "violation"
+	EventSummary        = "summary"
+)
+
+// DiffEvent is one line of ndjson-events output. Only the field matching
+// Type is set.
+type DiffEvent struct {
+	Type       string                     `json:"type"`
+	Component  *sbom.Component            `json:"component,omitempty"`
+	Change     *analysis.ChangedComponent `json:"change,omitempty"`
+	Ref        string                     `json:"ref,omitempty"`
+	Deps       []string                   `json:"deps,omitempty"`
+	Transitive *analysis.TransitiveDep    `json:"transitive,omitempty"`
+	Violation  *policy.Violation          `json:"violation,omitempty"`
+	Summary    *analysis.DiffStats        `json:"summary,omitempty"`
+}
+
+// WriteDiffEvents writes the diff as newline-delimited JSON events, one
+// per component, edge or violation, ending with a summary event. Each
+// event is encoded as it is reached, so consumers can process the stream
+// incrementally. The order is deterministic: added, removed and changed
+// components by ID, dependency edges by component, transitive changes,
+// violations, then the summary.
+func WriteDiffEvents(w io.Writer, result analysis.DiffResult, summary analysis.DiffStats, violations []policy.Violation) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	for i := range result.Added {
+		if err := enc.Encode(DiffEvent{Type: EventAdded, Component: &result.Added[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range result.Removed {
+		if err := enc.Encode(DiffEvent{Type: EventRemoved, Component: &result.Removed[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range result.Changed {
+		if err := enc.Encode(DiffEvent{Type: EventChanged, Change: &result.Changed[i]}); err != nil {
+			return err
+		}
+	}
+
+	if deps := result.Dependencies; deps != nil {
+		for _, edges := range []struct {
+			typ  string
+			deps map[string][]string
+		}{{EventDepAdded, deps.AddedDeps}, {EventDepRemoved, deps.RemovedDeps}} {
+			for _, ref := range slices.Sorted(maps.Keys(edges.deps)) {
+				if err := enc.Encode(DiffEvent{Type: edges.typ, Ref: ref, Deps: edges.deps[ref]}); err != nil {
+					return err
+				}
+			}
+		}
+		for i := range deps.TransitiveNew {
+			if err := enc.Encode(DiffEvent{Type: EventTransitiveNew, Transitive: &deps.TransitiveNew[i]}); err != nil {
+				return err
+			}
+		}
+		for i := range deps.TransitiveLost {
+			if err := enc.Encode(DiffEvent{Type: EventTransitiveLost, Transitive: &deps.TransitiveLost[i]}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range violations {
+		if err := enc.Encode(DiffEvent{Type: EventViolation, Violation: &violations[i]}); err != nil {
+			return err
+		}
+	}
+	if err := enc.Encode(DiffEvent{Type: EventSummary, Summary: &summary}); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// IdentityNote is one component's ID and the field it was derived from.
+type IdentityNote struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Basis   string `json:"basis"`
+}
+
+// IdentityNotes lists the identity basis of comps, which must have been
+// through sbom.ExplainIDs.
+func IdentityNotes(comps []sbom.Component) []IdentityNote {
+	notes := make([]IdentityNote, 0, len(comps))
+	for _, c := range comps {
+		notes = append(notes, IdentityNote{ID: c.ID, Name: c.Name, Version: c.Version, Basis: c.IDBasis})
+	}
+	return notes
+}
+
+// PrintIdentityBasis prints the identity basis of each component.
+func PrintIdentityBasis(comps []sbom.Component, opts TextOptions) {
+	fmt.Printf("\nIdentity Basis (%d):\n", len(comps))
+	shown, more := limitItems(comps, opts.MaxItems)
+	for _, c := range shown {
+		fmt.Printf("  %s %s\n", c.Name, c.Version)
+		printIDBasis(c)
+	}
+	printMore(more)
+}
+
+// printIDBasis prints the note under a component line; components not
+// run through sbom.ExplainIDs print nothing.
+func printIDBasis(c sbom.Component) {
+	if c.IDBasis != "" {
+		fmt.Printf("      id: %s (by %s)\n", c.ID, c.IDBasis)
+	}
+}
@@ -4,11 +4,13 @@ package output
 type Format string
 
 const (
-	FormatText     Format = "text"
-	FormatJSON     Format = "json"
-	FormatSARIF    Format = "sarif"
-	FormatJUnit    Format = "junit"
-	FormatMarkdown Format = "markdown"
-	FormatPatch    Format = "patch"
-	FormatHTML     Format = "html"
+	FormatText      Format = "text"
+	FormatJSON      Format = "json"
+	FormatJSONL     Format = "jsonl"
+	FormatSARIF     Format = "sarif"
+	FormatJUnit     Format = "junit"
+	FormatMarkdown  Format = "markdown"
+	FormatPatch     Format = "patch"
+	FormatHTML      Format = "html"
+	FormatCycloneDX Format = "cyclonedx"
 )
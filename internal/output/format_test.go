@@ -175,7 +175,7 @@ func TestGenerateMarkdown(t *testing.T) {
 			},
 		}
 
-		md := GenerateMarkdown(result, nil)
+		md := GenerateMarkdown(result, nil, TextOptions{})
 
 		if !strings.Contains(md, "## 📦 SBOM Diff Report") {
 			t.Error("expected markdown header")
@@ -196,7 +196,7 @@ func TestGenerateMarkdown(t *testing.T) {
 			},
 		}
 
-		md := GenerateMarkdown(result, nil)
+		md := GenerateMarkdown(result, nil, TextOptions{})
 
 		if !strings.Contains(md, "Drift Summary") {
 			t.Error("expected drift summary section")
@@ -212,7 +212,7 @@ func TestGenerateMarkdown(t *testing.T) {
 			{Rule: "test-warn", Message: "warn message", Severity: policy.SeverityWarning},
 		}
 
-		md := GenerateMarkdown(analysis.DiffResult{}, violations)
+		md := GenerateMarkdown(analysis.DiffResult{}, violations, TextOptions{})
 
 		if !strings.Contains(md, "Policy Errors") {
 			t.Error("expected policy errors section")
@@ -227,7 +227,7 @@ func TestGenerateMarkdown(t *testing.T) {
 			Added: []sbom.Component{{Name: "lib1", Version: "1.0"}},
 		}
 
-		md := GenerateMarkdown(result, nil)
+		md := GenerateMarkdown(result, nil, TextOptions{})
 
 		if !strings.Contains(md, "<details>") {
 			t.Error("expected collapsible sections")
@@ -314,17 +314,37 @@ func TestGenerateJSONPatch(t *testing.T) {
 }
 
 func TestGenerateMarkdown_EmptyDiff(t *testing.T) {
-	md := GenerateMarkdown(analysis.DiffResult{}, nil)
+	md := GenerateMarkdown(analysis.DiffResult{}, nil, TextOptions{})
 	if !strings.Contains(md, "| Added | 0 |") {
 		t.Error("expected '| Added | 0 |' in empty diff markdown")
 	}
 }
 
+func TestGenerateMarkdown_MaxItems(t *testing.T) {
+	result := analysis.DiffResult{
+		Added: []sbom.Component{
+			{Name: "a", Version: "1.0"},
+			{Name: "b", Version: "1.0"},
+			{Name: "c", Version: "1.0"},
+		},
+	}
+	md := GenerateMarkdown(result, nil, TextOptions{MaxItems: 1})
+	if !strings.Contains(md, "| Added | 3 |") {
+		t.Error("expected summary to count all added components")
+	}
+	if !strings.Contains(md, "| a | 1.0 |") || strings.Contains(md, "| b | 1.0 |") {
+		t.Errorf("expected only the first added row, got:\n%s", md)
+	}
+	if !strings.Contains(md, "*...and 2 more*") {
+		t.Errorf("expected truncation footer, got:\n%s", md)
+	}
+}
+
 func TestGenerateMarkdown_IntegrityDriftStatus(t *testing.T) {
 	result := analysis.DiffResult{
 		DriftSummary: &analysis.DriftSummary{IntegrityDrift: 2},
 	}
-	md := GenerateMarkdown(result, nil)
+	md := GenerateMarkdown(result, nil, TextOptions{})
 	if !strings.Contains(md, "Review Required") {
 		t.Error("expected 'Review Required' for integrity drift")
 	}
@@ -336,12 +356,74 @@ func TestGenerateMarkdown_DepthSummary(t *testing.T) {
 			DepthSummary: &analysis.DepthSummary{Depth1: 1, Depth2: 2, Depth3Plus: 1},
 		},
 	}
-	md := GenerateMarkdown(result, nil)
+	md := GenerateMarkdown(result, nil, TextOptions{})
 	if !strings.Contains(md, "Depth") {
 		t.Error("expected depth table in markdown")
 	}
 }
 
+func TestGenerateMarkdown_DeepDeps(t *testing.T) {
+	transitive := []analysis.TransitiveDep{
+		{Target: "pkg:npm/deep-lib", Via: []string{"pkg:npm/app", "pkg:npm/express", "pkg:npm/router", "pkg:npm/deep-lib"}, Depth: 3},
+		{Target: "pkg:npm/deeper-lib", Via: []string{"pkg:npm/app", "pkg:npm/express", "pkg:npm/router", "pkg:npm/deep-lib", "pkg:npm/deeper-lib"}, Depth: 4},
+		{Target: "pkg:npm/lodash", Via: []string{"pkg:npm/app", "pkg:npm/express", "pkg:npm/lodash"}, Depth: 2},
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:      "default threshold",
+			threshold: 0,
+			want: []string{
+				"<summary>⚠️ Deep Dependencies, depth 3+ (2)</summary>",
+				"| pkg:npm/deep-lib | 3 | pkg:npm/app → pkg:npm/express → pkg:npm/router → pkg:npm/deep-lib |",
+				"| pkg:npm/deeper-lib | 4 |",
+			},
+			notWant: []string{"| pkg:npm/lodash |"},
+		},
+		{
+			name:      "raised threshold",
+			threshold: 4,
+			want: []string{
+				"<summary>⚠️ Deep Dependencies, depth 4+ (1)</summary>",
+				"| pkg:npm/deeper-lib | 4 |",
+			},
+			notWant: []string{"| pkg:npm/deep-lib |", "| pkg:npm/lodash |"},
+		},
+		{
+			name:      "nothing deep enough",
+			threshold: 5,
+			notWant:   []string{"Deep Dependencies"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analysis.DiffResult{
+				Dependencies: &analysis.DependencyDiff{
+					TransitiveNew: transitive,
+					DepthSummary:  &analysis.DepthSummary{Depth2: 1, Depth3Plus: 2, Threshold: tt.threshold},
+				},
+			}
+			md := GenerateMarkdown(result, nil, TextOptions{})
+			for _, w := range tt.want {
+				if !strings.Contains(md, w) {
+					t.Errorf("expected %q in markdown, got:\n%s", w, md)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(md, w) {
+					t.Errorf("did not expect %q in markdown, got:\n%s", w, md)
+				}
+			}
+		})
+	}
+}
+
 func TestGenerateMarkdown_DriftTypes(t *testing.T) {
 	result := analysis.DiffResult{
 		Changed: []analysis.ChangedComponent{
@@ -350,7 +432,7 @@ func TestGenerateMarkdown_DriftTypes(t *testing.T) {
 			{Name: "c", Before: sbom.Component{Version: "1"}, After: sbom.Component{Version: "1"}, Drift: &analysis.DriftInfo{Type: analysis.DriftTypeMetadata}},
 		},
 	}
-	md := GenerateMarkdown(result, nil)
+	md := GenerateMarkdown(result, nil, TextOptions{})
 	if !strings.Contains(md, "Version") {
 		t.Error("expected Version drift type")
 	}
@@ -362,6 +444,28 @@ func TestGenerateMarkdown_DriftTypes(t *testing.T) {
 	}
 }
 
+func TestGenerateMarkdown_LicenseRemoved(t *testing.T) {
+	result := analysis.DiffResult{
+		Changed: []analysis.ChangedComponent{
+			{Name: "a", Drift: &analysis.DriftInfo{Type: analysis.DriftTypeMetadata, LicenseRemoved: true}},
+		},
+		DriftSummary: &analysis.DriftSummary{MetadataDrift: 1, LicenseRemoved: 1},
+	}
+	md := GenerateMarkdown(result, nil, TextOptions{})
+	if !strings.Contains(md, "| License removed | 1 |") {
+		t.Errorf("expected license removed row in drift summary, got:\n%s", md)
+	}
+	if !strings.Contains(md, "📝 Metadata 🚫 License removed") {
+		t.Errorf("expected license removed marker on component, got:\n%s", md)
+	}
+
+	result.Changed[0].Drift.LicenseRemoved = false
+	result.DriftSummary.LicenseRemoved = 0
+	if md := GenerateMarkdown(result, nil, TextOptions{}); strings.Contains(md, "License removed") {
+		t.Errorf("expected no license removed marker, got:\n%s", md)
+	}
+}
+
 func TestGenerateSARIF_EmptyDiff(t *testing.T) {
 	sarif := GenerateSARIF(analysis.DiffResult{}, nil, "test.json")
 	if len(sarif.Runs[0].Results) != 0 {
@@ -426,6 +530,185 @@ func TestGenerateSARIF_VersionChange(t *testing.T) {
 	}
 }
 
+func TestGenerateSARIF_MetadataChanges(t *testing.T) {
+	result := analysis.DiffResult{
+		Changed: []analysis.ChangedComponent{
+			{Name: "lib", Drift: &analysis.DriftInfo{Type: analysis.DriftTypeMetadata, LicensesDiff: []string{"+Apache-2.0", "-MIT"}}},
+			{Name: "tool", Drift: &analysis.DriftInfo{Type: analysis.DriftTypeMetadata, SupplierFrom: "Acme", SupplierTo: "Evil Corp"}},
+		},
+	}
+	sarif := GenerateSARIF(result, nil, "test.json")
+	got := make(map[string]SARIFResult)
+	for _, r := range sarif.Runs[0].Results {
+		got[r.RuleID] = r
+	}
+	if r := got["license-change"]; r.Level != "note" || r.Message.Text != "Component lib licenses changed: +Apache-2.0 -MIT" {
+		t.Errorf("unexpected license-change result %+v", r)
+	}
+	if r := got["supplier-change"]; r.Level != "warning" || r.Message.Text != "Component tool supplier changed: Acme -> Evil Corp" {
+		t.Errorf("unexpected supplier-change result %+v", r)
+	}
+	if _, ok := got["property-change"]; ok {
+		t.Error("expected no property-change result")
+	}
+}
+
+func TestGenerateSARIF_DeepDependencyThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		want      []string
+	}{
+		{"default", 0, []string{"c"}},
+		{"threshold 2", 2, []string{"b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analysis.DiffResult{Dependencies: &analysis.DependencyDiff{
+				TransitiveNew: []analysis.TransitiveDep{
+					{Target: "a", Depth: 1},
+					{Target: "b", Depth: 2},
+					{Target: "c", Depth: 3},
+				},
+				DepthSummary: &analysis.DepthSummary{Depth1: 1, Depth2: 1, Depth3Plus: 1, Deep: 1},
+			}}
+			analysis.ApplyDeepDepThreshold(&result, tt.threshold)
+
+			var got []string
+			for _, r := range GenerateSARIF(result, nil, "test.json").Runs[0].Results {
+				if r.RuleID == "deep-dependency" {
+					got = append(got, strings.Fields(r.Message.Text)[3])
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("deep-dependency results for %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSARIF_DeepDependencyPath(t *testing.T) {
+	result := analysis.DiffResult{Dependencies: &analysis.DependencyDiff{
+		TransitiveNew: []analysis.TransitiveDep{
+			{Target: "pkg:npm/d", Depth: 3, Via: []string{"pkg:npm/app", "pkg:npm/b", "pkg:npm/c", "pkg:npm/d"}},
+		},
+		DepthSummary: &analysis.DepthSummary{Depth3Plus: 1, Deep: 1},
+	}}
+
+	results := GenerateSARIF(result, nil, "test.json").Runs[0].Results
+	if len(results) != 1 || results[0].RuleID != "deep-dependency" {
+		t.Fatalf("expected one deep-dependency result, got %+v", results)
+	}
+	want := "New transitive dependency pkg:npm/d at depth 3, introduced via pkg:npm/app → pkg:npm/b → pkg:npm/c → pkg:npm/d"
+	if results[0].Message.Text != want {
+		t.Errorf("message = %q, want %q", results[0].Message.Text, want)
+	}
+}
+
+func TestGenerateJUnit_PerComponentCases(t *testing.T) {
+	componentCaseCounts := func(suite JUnitTestSuite) (cases, fails int) {
+		for _, tc := range suite.TestCases {
+			if strings.HasPrefix(tc.ClassName, "sbomlyze.components.") {
+				cases++
+				if tc.Failure != nil {
+					fails++
+				}
+			}
+		}
+		return cases, fails
+	}
+
+	tests := []struct {
+		name      string
+		result    analysis.DiffResult
+		wantCases int
+		wantFails int
+	}{
+		{"empty diff", analysis.DiffResult{}, 0, 0},
+		{
+			"added and removed pass",
+			analysis.DiffResult{
+				Added:   []sbom.Component{{Name: "a", Version: "1.0"}, {Name: "b", Version: "1.0"}},
+				Removed: []sbom.Component{{Name: "c", Version: "1.0"}},
+			},
+			3, 0,
+		},
+		{
+			"integrity drift and downgrade fail",
+			analysis.DiffResult{
+				Added: []sbom.Component{{Name: "a", Version: "1.0"}},
+				Changed: []analysis.ChangedComponent{
+					{Name: "up", Before: sbom.Component{Version: "1.0"}, After: sbom.Component{Version: "2.0"},
+						Drift: &analysis.DriftInfo{Type: analysis.DriftTypeVersion}},
+					{Name: "down", Before: sbom.Component{Version: "2.0"}, After: sbom.Component{Version: "1.0"},
+						Drift: &analysis.DriftInfo{Type: analysis.DriftTypeVersion}},
+					{Name: "hash", Before: sbom.Component{Version: "1.0"}, After: sbom.Component{Version: "1.0"},
+						Drift: &analysis.DriftInfo{Type: analysis.DriftTypeIntegrity}},
+				},
+			},
+			4, 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junit := GenerateJUnit(tt.result, nil)
+			suite := junit.TestSuite[0]
+			cases, fails := componentCaseCounts(suite)
+			if cases != tt.wantCases {
+				t.Errorf("component cases = %d, want %d", cases, tt.wantCases)
+			}
+			if fails != tt.wantFails {
+				t.Errorf("component failures = %d, want %d", fails, tt.wantFails)
+			}
+			if suite.Tests != len(suite.TestCases) || junit.Tests != suite.Tests {
+				t.Errorf("tests attr %d/%d, want %d", junit.Tests, suite.Tests, len(suite.TestCases))
+			}
+			if junit.Failures != suite.Failures {
+				t.Errorf("failures attr %d, suite %d", junit.Failures, suite.Failures)
+			}
+		})
+	}
+}
+
+func TestCPEList(t *testing.T) {
+	result := analysis.DiffResult{
+		Added: []sbom.Component{
+			{Name: "openssl", CPEs: []string{
+				"cpe:2.3:a:openssl:openssl:3.0.1:*:*:*:*:*:*:*",
+				"cpe:2.3:a:openssl:openssl:3.0.1:*:*:*:*:*:*:*",
+			}},
+			{Name: "curl", CPEs: []string{"cpe:/a:haxx:curl:8.0.0"}},
+			{Name: "no-cpe"},
+		},
+		Removed: []sbom.Component{
+			{Name: "gone", CPEs: []string{"cpe:2.3:a:gone:gone:1.0:*:*:*:*:*:*:*"}},
+		},
+		Changed: []analysis.ChangedComponent{
+			{
+				Name:   "openssl-libs",
+				Before: sbom.Component{CPEs: []string{"cpe:2.3:a:old:old:1.0:*:*:*:*:*:*:*"}},
+				After: sbom.Component{CPEs: []string{
+					"cpe:2.3:a:openssl:openssl:3.0.2:*:*:*:*:*:*:*",
+					"cpe:2.3:a:zlib:zlib:1.3:*:*:*:*:*:*:*",
+				}},
+			},
+		},
+	}
+
+	got := CPEList(result)
+	want := []string{"cpe:haxx:curl", "cpe:openssl:openssl", "cpe:zlib:zlib"}
+	if len(got) != len(want) {
+		t.Fatalf("CPEList() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CPEList() = %v, want %v", got, want)
+		}
+	}
+}
+
 func TestGenerateJUnit_NoViolations(t *testing.T) {
 	junit := GenerateJUnit(analysis.DiffResult{}, nil)
 	if junit.Failures != 0 {
@@ -493,3 +776,107 @@ func TestGenerateJSONPatch_HashChange(t *testing.T) {
 		t.Error("expected replace op for hashes")
 	}
 }
+
+func TestWriteDiffEvents(t *testing.T) {
+	result := analysis.DiffResult{
+		Added:   []sbom.Component{{ID: "pkg:npm/a", Name: "a", Version: "1.0"}, {ID: "pkg:npm/b", Name: "b", Version: "1.0"}},
+		Removed: []sbom.Component{{ID: "pkg:npm/old", Name: "old", Version: "0.1"}},
+		Changed: []analysis.ChangedComponent{{ID: "pkg:npm/c", Name: "c", Changes: []string{"version: 1 -> 2"}}},
+		Dependencies: &analysis.DependencyDiff{
+			AddedDeps:     map[string][]string{"pkg:npm/z": {"pkg:npm/b"}, "pkg:npm/a": {"pkg:npm/b"}},
+			TransitiveNew: []analysis.TransitiveDep{{Target: "pkg:npm/b", Depth: 2}},
+		},
+	}
+	violations := []policy.Violation{{Rule: "max_added", Message: "added 2 > max 1", Severity: policy.SeverityError}}
+
+	var buf strings.Builder
+	if err := WriteDiffEvents(&buf, result, result.Summary(), violations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var types []string
+	var events []DiffEvent
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		var ev DiffEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i+1, err)
+		}
+		types = append(types, ev.Type)
+		events = append(events, ev)
+	}
+
+	want := []string{
+		EventAdded, EventAdded, EventRemoved, EventChanged,
+		EventDepAdded, EventDepAdded, EventTransitiveNew, EventViolation, EventSummary,
+	}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("event types = %v, want %v", types, want)
+	}
+	if events[0].Component == nil || events[0].Component.ID != "pkg:npm/a" {
+		t.Errorf("expected first added event for pkg:npm/a, got %+v", events[0])
+	}
+	if events[3].Change == nil || events[3].Change.ID != "pkg:npm/c" {
+		t.Errorf("expected changed event for pkg:npm/c, got %+v", events[3])
+	}
+	if events[4].Ref != "pkg:npm/a" || events[5].Ref != "pkg:npm/z" {
+		t.Errorf("expected dependency events sorted by ref, got %q, %q", events[4].Ref, events[5].Ref)
+	}
+	if s := events[len(events)-1].Summary; s == nil || s.Added != 2 || s.Removed != 1 || s.Changed != 1 {
+		t.Errorf("unexpected summary event: %+v", s)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	comps := []sbom.Component{
+		{ID: "pkg:npm/a", Name: "a", Version: "1.0", PURL: "pkg:npm/a@1.0", Licenses: []string{"MIT"}, RawJSON: []byte(`{"x":1}`)},
+		{ID: "pkg:npm/b", Name: "b", Version: "2.0"},
+	}
+
+	var buf strings.Builder
+	if err := WriteJSONL(&buf, comps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(comps) {
+		t.Fatalf("expected %d lines, got %d", len(comps), len(lines))
+	}
+	for i, line := range lines {
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		}
+		if got["name"] != comps[i].Name || got["id"] != comps[i].ID {
+			t.Errorf("line %d: unexpected object %v", i, got)
+		}
+		if _, ok := got["RawJSON"]; ok {
+			t.Errorf("line %d: raw JSON should not be emitted", i)
+		}
+	}
+}
+
+func TestGenerateMarkdown_GroupByType(t *testing.T) {
+	result := analysis.DiffResult{
+		Added: []sbom.Component{
+			{Name: "left-pad", Version: "1.3.0", PURL: "pkg:npm/left-pad@1.3.0"},
+			{Name: "requests", Version: "2.31.0", PURL: "pkg:pypi/requests@2.31.0"},
+		},
+	}
+
+	md := GenerateMarkdown(result, nil, TextOptions{GroupByType: true})
+
+	npm := strings.Index(md, "### `npm` (+1 -0 ~0)")
+	pypi := strings.Index(md, "### `pypi` (+1 -0 ~0)")
+	if npm < 0 || pypi < 0 || npm > pypi {
+		t.Fatalf("expected npm then pypi headings, got:\n%s", md)
+	}
+	if at := strings.Index(md, "| left-pad | 1.3.0 |"); at < npm || at > pypi {
+		t.Errorf("expected left-pad under npm, got:\n%s", md)
+	}
+	if at := strings.Index(md, "| requests | 2.31.0 |"); at < pypi {
+		t.Errorf("expected requests under pypi, got:\n%s", md)
+	}
+	if !strings.Contains(md, "| Added | 2 |") {
+		t.Error("expected the summary to keep overall counts")
+	}
+}
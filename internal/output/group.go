@@ -0,0 +1,59 @@
+package output
+
+import (
+	"sort"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// typeGroup is the added, removed and changed components of one PURL type.
+type typeGroup struct {
+	Type    string
+	Added   []sbom.Component
+	Removed []sbom.Component
+	Changed []analysis.ChangedComponent
+}
+
+// componentType is c's PURL type, typed the same way as the stats'
+// by-type counts.
+func componentType(c sbom.Component) string {
+	ptype := analysis.ExtractPURLType(c.PURL)
+	if ptype == "unknown" && c.PURL == "" {
+		ptype = analysis.ExtractPURLType(c.ID)
+	}
+	return ptype
+}
+
+// groupDiffByType splits the component sections of result by PURL type,
+// sorted by type. Changed components are typed by their "after" side.
+func groupDiffByType(result analysis.DiffResult) []typeGroup {
+	byType := make(map[string]*typeGroup)
+	group := func(t string) *typeGroup {
+		g, ok := byType[t]
+		if !ok {
+			g = &typeGroup{Type: t}
+			byType[t] = g
+		}
+		return g
+	}
+	for _, c := range result.Added {
+		g := group(componentType(c))
+		g.Added = append(g.Added, c)
+	}
+	for _, c := range result.Removed {
+		g := group(componentType(c))
+		g.Removed = append(g.Removed, c)
+	}
+	for _, c := range result.Changed {
+		g := group(componentType(c.After))
+		g.Changed = append(g.Changed, c)
+	}
+
+	groups := make([]typeGroup, 0, len(byType))
+	for _, g := range byType {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Type < groups[j].Type })
+	return groups
+}
@@ -187,7 +187,11 @@ func GenerateHTMLStats(stats analysis.Stats, info sbom.SBOMInfo, findings analys
 		sb.WriteString("<h2>⚠️ Duplicates</h2>\n<table>\n")
 		sb.WriteString("<tr><th>Package</th><th>Versions</th></tr>\n")
 		for _, d := range stats.Duplicates {
-			writeHTMLRow(&sb, d.Name, strings.Join(d.Versions, ", "))
+			versions := strings.Join(d.Versions, ", ")
+			if d.Exact {
+				versions += " (exact copies)"
+			}
+			writeHTMLRow(&sb, d.Name, versions)
 		}
 		sb.WriteString("</table>\n")
 	}
@@ -256,6 +260,11 @@ func writeHTMLDriftSummary(sb *strings.Builder, result analysis.DiffResult) {
 	}
 	fmt.Fprintf(sb, "<tr><td>Integrity</td><td>%d</td><td>%s</td></tr>\n", ds.IntegrityDrift, intStatus)
 	fmt.Fprintf(sb, "<tr><td>Metadata</td><td>%d</td><td><span class=\"badge badge-ok\">✅ OK</span></td></tr>\n", ds.MetadataDrift)
+	if ds.MetadataDrift > 0 {
+		fmt.Fprintf(sb, "<tr><td>&nbsp;&nbsp;↳ License</td><td>%d</td><td></td></tr>\n", ds.LicenseDrift)
+		fmt.Fprintf(sb, "<tr><td>&nbsp;&nbsp;↳ Supplier</td><td>%d</td><td></td></tr>\n", ds.SupplierDrift)
+		fmt.Fprintf(sb, "<tr><td>&nbsp;&nbsp;↳ Property</td><td>%d</td><td></td></tr>\n", ds.PropertyDrift)
+	}
 	sb.WriteString("</table>\n")
 }
 
@@ -263,14 +272,21 @@ func writeHTMLDepthSummary(sb *strings.Builder, result analysis.DiffResult) {
 	ds := result.Dependencies.DepthSummary
 	sb.WriteString("<h2>New Dependencies by Depth</h2>\n<table>\n")
 	sb.WriteString("<tr><th>Depth</th><th>Count</th><th>Risk</th></tr>\n")
-	fmt.Fprintf(sb, "<tr><td>1 (direct)</td><td>%d</td><td><span class=\"badge badge-ok\">Low</span></td></tr>\n", ds.Depth1)
-	fmt.Fprintf(sb, "<tr><td>2</td><td>%d</td><td><span class=\"badge badge-warn\">Medium</span></td></tr>\n", ds.Depth2)
-
-	risk := "<span class=\"badge badge-warn\">Medium</span>"
-	if ds.Depth3Plus > 0 {
-		risk = "<span class=\"badge badge-error\">High</span>"
+	risk := func(depth, n int) string {
+		switch {
+		case ds.IsDeep(depth) && n > 0:
+			return "<span class=\"badge badge-error\">High</span>"
+		case depth == 1:
+			return "<span class=\"badge badge-ok\">Low</span>"
+		}
+		return "<span class=\"badge badge-warn\">Medium</span>"
+	}
+	fmt.Fprintf(sb, "<tr><td>1 (direct)</td><td>%d</td><td>%s</td></tr>\n", ds.Depth1, risk(1, ds.Depth1))
+	fmt.Fprintf(sb, "<tr><td>2</td><td>%d</td><td>%s</td></tr>\n", ds.Depth2, risk(2, ds.Depth2))
+	fmt.Fprintf(sb, "<tr><td>3+</td><td>%d</td><td>%s</td></tr>\n", ds.Depth3Plus, risk(3, ds.Depth3Plus))
+	if t := ds.DeepThreshold(); t > 3 {
+		fmt.Fprintf(sb, "<tr><td>%d+</td><td>%d</td><td>%s</td></tr>\n", t, ds.Deep, risk(t, ds.Deep))
 	}
-	fmt.Fprintf(sb, "<tr><td>3+</td><td>%d</td><td>%s</td></tr>\n", ds.Depth3Plus, risk)
 	sb.WriteString("</table>\n")
 }
 
@@ -336,6 +352,9 @@ func writeHTMLChangedSection(sb *strings.Builder, changed []analysis.ChangedComp
 				driftClass = "badge-info"
 			case analysis.DriftTypeMetadata:
 				drift = "📝 Metadata"
+				if kinds := c.Drift.MetadataKinds(); len(kinds) > 0 {
+					drift += " (" + strings.Join(kinds, ", ") + ")"
+				}
 				driftClass = "badge-warn"
 			}
 		}
@@ -109,7 +109,7 @@ func TestMarkdownOutput_ValidMarkdown(t *testing.T) {
 		{Rule: "test_rule", Message: "test violation", Severity: policy.SeverityError},
 	}
 
-	md := GenerateMarkdown(result, violations)
+	md := GenerateMarkdown(result, violations, TextOptions{})
 
 	// Check expected markdown structure
 	if !strings.Contains(md, "## ") {
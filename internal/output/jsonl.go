@@ -0,0 +1,20 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// WriteJSONL writes one compact JSON object per component, newline-delimited.
+// Each line uses the sbom.Component JSON field names.
+func WriteJSONL(w io.Writer, comps []sbom.Component) error {
+	enc := json.NewEncoder(w)
+	for _, c := range comps {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -69,9 +69,9 @@ func GenerateJUnit(result analysis.DiffResult, violations []policy.Violation) JU
 	}
 	testCases = append(testCases, tc)
 
-	deepDeps := 0
-	if result.Dependencies != nil && result.Dependencies.DepthSummary != nil {
-		deepDeps = result.Dependencies.DepthSummary.Depth3Plus
+	deepDeps, deepThreshold := 0, analysis.DefaultDeepDepThreshold
+	if ds := result.Dependencies; ds != nil && ds.DepthSummary != nil {
+		deepDeps, deepThreshold = ds.DepthSummary.Deep, ds.DepthSummary.DeepThreshold()
 	}
 	tc = JUnitTestCase{
 		Name:      "No Deep Transitive Dependencies",
@@ -80,7 +80,7 @@ func GenerateJUnit(result analysis.DiffResult, violations []policy.Violation) JU
 	}
 	if deepDeps > 0 {
 		tc.Failure = &JUnitFailure{
-			Message: fmt.Sprintf("%d new dependencies at depth 3+", deepDeps),
+			Message: fmt.Sprintf("%d new dependencies at depth %d+", deepDeps, deepThreshold),
 			Type:    "DeepDependency",
 		}
 		failures++
@@ -110,6 +110,10 @@ func GenerateJUnit(result analysis.DiffResult, violations []policy.Violation) JU
 	}
 	testCases = append(testCases, tc)
 
+	compCases, compFailures := componentTestCases(result)
+	testCases = append(testCases, compCases...)
+	failures += compFailures
+
 	return JUnitTestSuites{
 		Name:     "sbomlyze",
 		Tests:    len(testCases),
@@ -126,3 +130,57 @@ func GenerateJUnit(result analysis.DiffResult, violations []policy.Violation) JU
 		}},
 	}
 }
+
+// componentTestCases emits one case per added/removed/changed component.
+// Changed components fail on integrity drift or a downgrade.
+func componentTestCases(result analysis.DiffResult) ([]JUnitTestCase, int) {
+	cases := make([]JUnitTestCase, 0, len(result.Added)+len(result.Removed)+len(result.Changed))
+	failures := 0
+
+	for _, c := range result.Added {
+		cases = append(cases, JUnitTestCase{
+			Name:      componentCaseName(c.Name, c.Version),
+			ClassName: "sbomlyze.components.added",
+			Time:      0.001,
+		})
+	}
+	for _, c := range result.Removed {
+		cases = append(cases, JUnitTestCase{
+			Name:      componentCaseName(c.Name, c.Version),
+			ClassName: "sbomlyze.components.removed",
+			Time:      0.001,
+		})
+	}
+	for _, ch := range result.Changed {
+		tc := JUnitTestCase{
+			Name:      componentCaseName(ch.Name, ch.After.Version),
+			ClassName: "sbomlyze.components.changed",
+			Time:      0.001,
+		}
+		switch {
+		case ch.Drift != nil && ch.Drift.Type == analysis.DriftTypeIntegrity:
+			tc.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("%s: hash changed without version change", ch.Name),
+				Type:    "IntegrityDrift",
+			}
+		case ch.Before.Version != ch.After.Version && analysis.IsDowngrade(ch.Before.Version, ch.After.Version):
+			tc.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("%s: downgraded %s -> %s", ch.Name, ch.Before.Version, ch.After.Version),
+				Type:    "Downgrade",
+			}
+		}
+		if tc.Failure != nil {
+			failures++
+		}
+		cases = append(cases, tc)
+	}
+
+	return cases, failures
+}
+
+func componentCaseName(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "@" + version
+}
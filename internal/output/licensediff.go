@@ -0,0 +1,115 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/policy"
+)
+
+// versionChange is "1.0 -> 2.0", or the version alone when unchanged.
+func versionChange(before, after string) string {
+	if before == after {
+		return after
+	}
+	return before + " -> " + after
+}
+
+// licenseList joins licenses, or "(none)" for an unlicensed component.
+func licenseList(lics []string) string {
+	if len(lics) == 0 {
+		return "(none)"
+	}
+	return strings.Join(lics, ", ")
+}
+
+// PrintLicenseDiff prints the --diff-licenses report in text format.
+func PrintLicenseDiff(d analysis.LicenseDiff, opts TextOptions) {
+	if !d.HasChanges() {
+		fmt.Println("No license changes")
+		return
+	}
+
+	if len(d.NewCopyleft) > 0 {
+		fmt.Printf("\n%sNew copyleft licenses (%d):\n", opts.icon("⚠️  ", "! "), len(d.NewCopyleft))
+		for _, lic := range d.NewCopyleft {
+			fmt.Printf("  %s\n", lic)
+		}
+	}
+
+	if len(d.Changed) > 0 {
+		fmt.Printf("\n~ License changes (%d):\n", len(d.Changed))
+		shown, more := limitItems(d.Changed, opts.MaxItems)
+		for _, c := range shown {
+			removed := ""
+			if c.LicenseRemoved {
+				removed = " [LICENSE REMOVED]"
+			}
+			fmt.Printf("  ~ %s %s%s\n", c.Name, versionChange(c.VersionBefore, c.VersionAfter), removed)
+			fmt.Printf("      %s\n", strings.Join(c.Diff, " "))
+		}
+		printMore(more)
+	}
+
+	if len(d.Added) > 0 {
+		fmt.Printf("\n+ Added components (%d):\n", len(d.Added))
+		shown, more := limitItems(d.Added, opts.MaxItems)
+		for _, a := range shown {
+			fmt.Printf("  + %s %s: %s [%s]\n", a.Name, a.Version, licenseList(a.Licenses), a.Category)
+		}
+		printMore(more)
+	}
+}
+
+// GenerateLicenseMarkdown renders the --diff-licenses report as Markdown.
+func GenerateLicenseMarkdown(d analysis.LicenseDiff, violations []policy.Violation, opts TextOptions) string {
+	var sb strings.Builder
+	sb.WriteString("## 📜 License Diff Report\n\n")
+
+	sb.WriteString("| Metric | Count |\n")
+	sb.WriteString("|--------|-------|\n")
+	fmt.Fprintf(&sb, "| License changes | %d |\n", len(d.Changed))
+	fmt.Fprintf(&sb, "| Added components | %d |\n", len(d.Added))
+	fmt.Fprintf(&sb, "| New copyleft licenses | %d |\n", len(d.NewCopyleft))
+
+	if len(d.NewCopyleft) > 0 {
+		sb.WriteString("\n### ⚠️ New Copyleft Licenses\n\n")
+		for _, lic := range d.NewCopyleft {
+			fmt.Fprintf(&sb, "- `%s`\n", lic)
+		}
+	}
+
+	writeMarkdownViolations(&sb, violations)
+
+	if len(d.Changed) > 0 {
+		sb.WriteString("\n### 🔄 License Changes\n\n")
+		sb.WriteString("| Name | Version | Before | After |\n")
+		sb.WriteString("|------|---------|--------|-------|\n")
+		shown, more := limitItems(d.Changed, opts.MaxItems)
+		for _, c := range shown {
+			after := licenseList(c.After)
+			if c.LicenseRemoved {
+				after += " 🚫"
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", c.Name, versionChange(c.VersionBefore, c.VersionAfter), licenseList(c.Before), after)
+		}
+		writeMarkdownMore(&sb, more)
+	}
+
+	if len(d.Added) > 0 {
+		sb.WriteString("\n### ➕ Added Components\n\n")
+		sb.WriteString("| Name | Version | Licenses | Category |\n")
+		sb.WriteString("|------|---------|----------|----------|\n")
+		shown, more := limitItems(d.Added, opts.MaxItems)
+		for _, a := range shown {
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", a.Name, a.Version, licenseList(a.Licenses), a.Category)
+		}
+		writeMarkdownMore(&sb, more)
+	}
+
+	if !d.HasChanges() {
+		sb.WriteString("\nNo license changes.\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// limitItems returns the first maxItems entries to show and how many were
+// left out; 0 shows all. Section counts still report the full totals.
+func limitItems[T any](items []T, maxItems int) ([]T, int) {
+	if maxItems <= 0 || len(items) <= maxItems {
+		return items, 0
+	}
+	return items[:maxItems], len(items) - maxItems
+}
+
+// printMore prints the footer of a truncated text listing.
+func printMore(n int) {
+	if n > 0 {
+		fmt.Printf("  ... and %d more\n", n)
+	}
+}
+
+// writeMarkdownMore writes the footer of a truncated Markdown table.
+func writeMarkdownMore(sb *strings.Builder, n int) {
+	if n > 0 {
+		fmt.Fprintf(sb, "\n*...and %d more*\n", n)
+	}
+}
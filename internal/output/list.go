@@ -0,0 +1,60 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// ListEntry is one component in the --list inventory.
+type ListEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"` // PURL type, or "unknown"
+	PURL    string `json:"purl"`
+}
+
+// ListEntries returns the inventory of comps sorted by name, version and
+// PURL.
+func ListEntries(comps []sbom.Component) []ListEntry {
+	entries := make([]ListEntry, 0, len(comps))
+	for _, c := range comps {
+		entries = append(entries, ListEntry{
+			Name:    c.Name,
+			Version: c.Version,
+			Type:    componentType(c),
+			PURL:    c.PURL,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.PURL < b.PURL
+	})
+	return entries
+}
+
+// WriteComponentList writes entries as aligned NAME, VERSION, TYPE and
+// PURL columns, with "-" for empty values.
+func WriteComponentList(w io.Writer, entries []ListEntry) error {
+	dash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVERSION\tTYPE\tPURL")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", dash(e.Name), dash(e.Version), e.Type, dash(e.PURL))
+	}
+	return tw.Flush()
+}
@@ -8,10 +8,11 @@ import (
 
 	"github.com/rezmoss/sbomlyze/internal/analysis"
 	"github.com/rezmoss/sbomlyze/internal/policy"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
 )
 
 // GenerateMarkdownWithOverview creates a Markdown diff report.
-func GenerateMarkdownWithOverview(result analysis.DiffResult, violations []policy.Violation, overview analysis.DiffOverview, findings analysis.KeyFindings) string {
+func GenerateMarkdownWithOverview(result analysis.DiffResult, violations []policy.Violation, overview analysis.DiffOverview, findings analysis.KeyFindings, opts TextOptions) string {
 	var sb strings.Builder
 
 	sb.WriteString("## 📦 SBOM Diff Report\n\n")
@@ -108,22 +109,57 @@ func GenerateMarkdownWithOverview(result analysis.DiffResult, violations []polic
 		sb.WriteString("\n</details>\n\n")
 	}
 
-	writeMarkdownDiffBody(&sb, result, violations)
+	writeMarkdownDiffBody(&sb, result, violations, opts)
+	writeMarkdownFooter(&sb, &overview)
 
 	return sb.String()
 }
 
 // GenerateMarkdown creates a Markdown report.
-func GenerateMarkdown(result analysis.DiffResult, violations []policy.Violation) string {
+func GenerateMarkdown(result analysis.DiffResult, violations []policy.Violation, opts TextOptions) string {
 	var sb strings.Builder
 
 	sb.WriteString("## 📦 SBOM Diff Report\n\n")
-	writeMarkdownDiffBody(&sb, result, violations)
+	writeMarkdownDiffBody(&sb, result, violations, opts)
+	writeMarkdownFooter(&sb, nil)
 
 	return sb.String()
 }
 
-func writeMarkdownDiffBody(sb *strings.Builder, result analysis.DiffResult, violations []policy.Violation) {
+// writeMarkdownFooter writes SBOM provenance (when known) and the generator line.
+func writeMarkdownFooter(sb *strings.Builder, overview *analysis.DiffOverview) {
+	sb.WriteString("\n---\n")
+	if overview != nil {
+		if p := formatProvenance(overview.Before.Info); p != "" {
+			fmt.Fprintf(sb, "*Before: %s*  \n", p)
+		}
+		if p := formatProvenance(overview.After.Info); p != "" {
+			fmt.Fprintf(sb, "*After: %s*  \n", p)
+		}
+	}
+	fmt.Fprintf(sb, "*Generated by [sbomlyze](https://github.com/rezmoss/sbomlyze) at %s*\n", time.Now().UTC().Format(time.RFC3339))
+}
+
+// formatProvenance describes who/what generated an SBOM and when.
+func formatProvenance(info sbom.SBOMInfo) string {
+	var parts []string
+	if info.ToolName != "" {
+		tool := info.ToolName
+		if info.ToolVersion != "" {
+			tool += " " + info.ToolVersion
+		}
+		parts = append(parts, "generated by "+tool)
+	}
+	if info.Timestamp != "" {
+		parts = append(parts, "created "+info.Timestamp)
+	}
+	if len(info.Authors) > 0 {
+		parts = append(parts, "authors: "+strings.Join(info.Authors, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func writeMarkdownDiffBody(sb *strings.Builder, result analysis.DiffResult, violations []policy.Violation, opts TextOptions) {
 	sb.WriteString("### Summary\n\n")
 	sb.WriteString("| Metric | Count |\n")
 	sb.WriteString("|--------|-------|\n")
@@ -147,6 +183,18 @@ func writeMarkdownDiffBody(sb *strings.Builder, result analysis.DiffResult, viol
 
 		metadataStatus := "✅"
 		fmt.Fprintf(sb, "| Metadata | %d | %s |\n", result.DriftSummary.MetadataDrift, metadataStatus)
+		if ds := result.DriftSummary; ds.MetadataDrift > 0 {
+			fmt.Fprintf(sb, "| ↳ License | %d | ✅ |\n", ds.LicenseDrift)
+			fmt.Fprintf(sb, "| ↳ Supplier | %d | ✅ |\n", ds.SupplierDrift)
+			fmt.Fprintf(sb, "| ↳ Property | %d | ✅ |\n", ds.PropertyDrift)
+		}
+
+		if result.DriftSummary.LicenseRemoved > 0 {
+			fmt.Fprintf(sb, "| License removed | %d | 🚫 **Review Required** |\n", result.DriftSummary.LicenseRemoved)
+		}
+		if result.DriftSummary.SuspiciousVersionJump > 0 {
+			fmt.Fprintf(sb, "| Suspicious version jump | %d | 🚩 **Review Required** |\n", result.DriftSummary.SuspiciousVersionJump)
+		}
 	}
 
 	if result.Dependencies != nil && result.Dependencies.DepthSummary != nil {
@@ -154,69 +202,104 @@ func writeMarkdownDiffBody(sb *strings.Builder, result analysis.DiffResult, viol
 		sb.WriteString("\n### New Dependencies by Depth\n\n")
 		sb.WriteString("| Depth | Count | Risk |\n")
 		sb.WriteString("|-------|-------|------|\n")
-		fmt.Fprintf(sb, "| 1 (direct) | %d | Low |\n", ds.Depth1)
-		fmt.Fprintf(sb, "| 2 | %d | Medium |\n", ds.Depth2)
-
-		depth3Risk := "Medium"
-		if ds.Depth3Plus > 0 {
-			depth3Risk = "⚠️ **High**"
+		risk := func(depth, n int) string {
+			switch {
+			case ds.IsDeep(depth) && n > 0:
+				return "⚠️ **High**"
+			case depth == 1:
+				return "Low"
+			}
+			return "Medium"
+		}
+		fmt.Fprintf(sb, "| 1 (direct) | %d | %s |\n", ds.Depth1, risk(1, ds.Depth1))
+		fmt.Fprintf(sb, "| 2 | %d | %s |\n", ds.Depth2, risk(2, ds.Depth2))
+		fmt.Fprintf(sb, "| 3+ | %d | %s |\n", ds.Depth3Plus, risk(3, ds.Depth3Plus))
+		if t := ds.DeepThreshold(); t > 3 {
+			fmt.Fprintf(sb, "| %d+ | %d | %s |\n", t, ds.Deep, risk(t, ds.Deep))
 		}
-		fmt.Fprintf(sb, "| 3+ | %d | %s |\n", ds.Depth3Plus, depth3Risk)
 	}
 
-	if len(violations) > 0 {
-		var errors, warnings []policy.Violation
-		for _, v := range violations {
-			if v.Severity == policy.SeverityError {
-				errors = append(errors, v)
-			} else {
-				warnings = append(warnings, v)
-			}
-		}
+	if result.Dependencies != nil {
+		writeMarkdownDeepDeps(sb, result.Dependencies, opts)
+	}
 
-		if len(errors) > 0 {
-			sb.WriteString("\n### ❌ Policy Errors\n\n")
-			for _, v := range errors {
-				fmt.Fprintf(sb, "- **%s**: %s\n", v.Rule, v.Message)
-			}
+	writeMarkdownViolations(sb, violations)
+
+	if opts.GroupByType {
+		for _, g := range groupDiffByType(result) {
+			fmt.Fprintf(sb, "\n### `%s` (+%d -%d ~%d)\n", g.Type, len(g.Added), len(g.Removed), len(g.Changed))
+			writeMarkdownComponentTables(sb, g.Added, g.Removed, g.Changed, opts)
 		}
+	} else {
+		writeMarkdownComponentTables(sb, result.Added, result.Removed, result.Changed, opts)
+	}
+}
 
-		if len(warnings) > 0 {
-			sb.WriteString("\n### ⚠️ Policy Warnings\n\n")
-			for _, v := range warnings {
-				fmt.Fprintf(sb, "- **%s**: %s\n", v.Rule, v.Message)
-			}
+// writeMarkdownDeepDeps lists the new transitive dependencies at or beyond
+// the deep threshold, with the path that pulls each one in.
+func writeMarkdownDeepDeps(sb *strings.Builder, deps *analysis.DependencyDiff, opts TextOptions) {
+	threshold := analysis.DefaultDeepDepThreshold
+	if deps.DepthSummary != nil {
+		threshold = deps.DepthSummary.DeepThreshold()
+	}
+	var deep []analysis.TransitiveDep
+	for _, td := range deps.TransitiveNew {
+		if td.Depth >= threshold {
+			deep = append(deep, td)
 		}
 	}
+	if len(deep) == 0 {
+		return
+	}
+
+	sb.WriteString("\n<details>\n")
+	fmt.Fprintf(sb, "<summary>⚠️ Deep Dependencies, depth %d+ (%d)</summary>\n\n", threshold, len(deep))
+	sb.WriteString("| Target | Depth | Via |\n")
+	sb.WriteString("|--------|-------|-----|\n")
+	shown, more := limitItems(deep, opts.MaxItems)
+	for _, td := range shown {
+		fmt.Fprintf(sb, "| %s | %d | %s |\n", td.Target, td.Depth, strings.Join(td.Via, " → "))
+	}
+	writeMarkdownMore(sb, more)
+	sb.WriteString("\n</details>\n")
+}
 
-	if len(result.Added) > 0 {
+// writeMarkdownComponentTables writes the added, removed and changed
+// tables of a Markdown diff.
+func writeMarkdownComponentTables(sb *strings.Builder, added, removed []sbom.Component, changed []analysis.ChangedComponent, opts TextOptions) {
+	if len(added) > 0 {
 		sb.WriteString("\n<details>\n")
-		fmt.Fprintf(sb, "<summary>➕ Added Components (%d)</summary>\n\n", len(result.Added))
+		fmt.Fprintf(sb, "<summary>➕ Added Components (%d)</summary>\n\n", len(added))
 		sb.WriteString("| Name | Version |\n")
 		sb.WriteString("|------|--------|\n")
-		for _, c := range result.Added {
+		shown, more := limitItems(added, opts.MaxItems)
+		for _, c := range shown {
 			fmt.Fprintf(sb, "| %s | %s |\n", c.Name, c.Version)
 		}
+		writeMarkdownMore(sb, more)
 		sb.WriteString("\n</details>\n")
 	}
 
-	if len(result.Removed) > 0 {
+	if len(removed) > 0 {
 		sb.WriteString("\n<details>\n")
-		fmt.Fprintf(sb, "<summary>➖ Removed Components (%d)</summary>\n\n", len(result.Removed))
+		fmt.Fprintf(sb, "<summary>➖ Removed Components (%d)</summary>\n\n", len(removed))
 		sb.WriteString("| Name | Version |\n")
 		sb.WriteString("|------|--------|\n")
-		for _, c := range result.Removed {
+		shown, more := limitItems(removed, opts.MaxItems)
+		for _, c := range shown {
 			fmt.Fprintf(sb, "| %s | %s |\n", c.Name, c.Version)
 		}
+		writeMarkdownMore(sb, more)
 		sb.WriteString("\n</details>\n")
 	}
 
-	if len(result.Changed) > 0 {
+	if len(changed) > 0 {
 		sb.WriteString("\n<details>\n")
-		fmt.Fprintf(sb, "<summary>🔄 Changed Components (%d)</summary>\n\n", len(result.Changed))
+		fmt.Fprintf(sb, "<summary>🔄 Changed Components (%d)</summary>\n\n", len(changed))
 		sb.WriteString("| Name | Before | After | Drift |\n")
 		sb.WriteString("|------|--------|-------|-------|\n")
-		for _, c := range result.Changed {
+		shown, more := limitItems(changed, opts.MaxItems)
+		for _, c := range shown {
 			drift := ""
 			if c.Drift != nil {
 				switch c.Drift.Type {
@@ -226,13 +309,46 @@ func writeMarkdownDiffBody(sb *strings.Builder, result analysis.DiffResult, viol
 					drift = "📦 Version"
 				case analysis.DriftTypeMetadata:
 					drift = "📝 Metadata"
+					if kinds := c.Drift.MetadataKinds(); len(kinds) > 0 {
+						drift += " (" + strings.Join(kinds, ", ") + ")"
+					}
+				}
+				if c.Drift.LicenseRemoved {
+					drift += " 🚫 License removed"
+				}
+				if c.Drift.SuspiciousJump {
+					drift += " 🚩 Suspicious jump"
 				}
 			}
 			fmt.Fprintf(sb, "| %s | %s | %s | %s |\n", c.Name, c.Before.Version, c.After.Version, drift)
 		}
+		writeMarkdownMore(sb, more)
 		sb.WriteString("\n</details>\n")
 	}
+}
 
-	sb.WriteString("\n---\n")
-	fmt.Fprintf(sb, "*Generated by [sbomlyze](https://github.com/rezmoss/sbomlyze) at %s*\n", time.Now().UTC().Format(time.RFC3339))
+// writeMarkdownViolations writes the policy errors and warnings sections.
+func writeMarkdownViolations(sb *strings.Builder, violations []policy.Violation) {
+	var errors, warnings []policy.Violation
+	for _, v := range violations {
+		if v.Severity == policy.SeverityError {
+			errors = append(errors, v)
+		} else {
+			warnings = append(warnings, v)
+		}
+	}
+
+	if len(errors) > 0 {
+		sb.WriteString("\n### ❌ Policy Errors\n\n")
+		for _, v := range errors {
+			fmt.Fprintf(sb, "- **%s**: %s\n", v.Rule, v.Message)
+		}
+	}
+
+	if len(warnings) > 0 {
+		sb.WriteString("\n### ⚠️ Policy Warnings\n\n")
+		for _, v := range warnings {
+			fmt.Fprintf(sb, "- **%s**: %s\n", v.Rule, v.Message)
+		}
+	}
 }
@@ -0,0 +1,38 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+)
+
+// prometheusLabel escapes a label value for the text exposition format.
+var prometheusLabel = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus writes the diff counts as Prometheus gauges in the text
+// exposition format read by node_exporter's textfile collector. Each
+// sample is labelled with the basename of sbomFile; components is the
+// size of that SBOM.
+func WritePrometheus(w io.Writer, stats analysis.DiffStats, components int, sbomFile string) error {
+	label := fmt.Sprintf(`{sbom="%s"}`, prometheusLabel.Replace(filepath.Base(sbomFile)))
+	metrics := []struct {
+		name  string
+		help  string
+		value int
+	}{
+		{"sbomlyze_added_total", "Components added since the previous SBOM.", stats.Added},
+		{"sbomlyze_removed_total", "Components removed since the previous SBOM.", stats.Removed},
+		{"sbomlyze_changed_total", "Components changed since the previous SBOM.", stats.Changed},
+		{"sbomlyze_integrity_drift_total", "Changed components whose hashes changed without a version change.", stats.IntegrityDrift},
+		{"sbomlyze_components_total", "Components in the SBOM.", components},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s%s %d\n", m.name, m.help, m.name, m.name, label, m.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want []string
+	}{
+		{
+			name: "counts",
+			file: "/builds/42/after.json",
+			want: []string{
+				"# HELP sbomlyze_added_total Components added since the previous SBOM.\n# TYPE sbomlyze_added_total gauge\n",
+				`sbomlyze_added_total{sbom="after.json"} 3` + "\n",
+				`sbomlyze_removed_total{sbom="after.json"} 1` + "\n",
+				`sbomlyze_changed_total{sbom="after.json"} 2` + "\n",
+				`sbomlyze_integrity_drift_total{sbom="after.json"} 1` + "\n",
+				`sbomlyze_components_total{sbom="after.json"} 40` + "\n",
+			},
+		},
+		{
+			name: "label escaping",
+			file: `odd"name\.json`,
+			want: []string{`sbomlyze_added_total{sbom="odd\"name\\.json"} 3` + "\n"},
+		},
+	}
+
+	stats := analysis.DiffStats{Added: 3, Removed: 1, Changed: 2, IntegrityDrift: 1, VersionDrift: 1}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WritePrometheus(&buf, stats, 40, tt.file); err != nil {
+				t.Fatal(err)
+			}
+			out := buf.String()
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("expected %q in output:\n%s", w, out)
+				}
+			}
+			if n := strings.Count(out, "# TYPE "); n != 5 {
+				t.Errorf("expected 5 metrics, got %d:\n%s", n, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package output
+
+import "fmt"
+
+// riskMarker labels a component with its score in riskScores; nil scores
+// label nothing.
+func riskMarker(riskScores map[string]int, id string) string {
+	if riskScores == nil {
+		return ""
+	}
+	return fmt.Sprintf(" [risk %d]", riskScores[id])
+}
@@ -2,6 +2,7 @@ package output
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/rezmoss/sbomlyze/internal/analysis"
 	"github.com/rezmoss/sbomlyze/internal/policy"
@@ -73,6 +74,11 @@ type SARIFArtifactLocation struct {
 
 // GenerateSARIF creates a SARIF report.
 func GenerateSARIF(result analysis.DiffResult, violations []policy.Violation, sbomFile string) SARIFReport {
+	deepThreshold := analysis.DefaultDeepDepThreshold
+	if result.Dependencies != nil && result.Dependencies.DepthSummary != nil {
+		deepThreshold = result.Dependencies.DepthSummary.DeepThreshold()
+	}
+
 	rules := []SARIFRule{
 		{
 			ID:               "integrity-drift",
@@ -99,10 +105,30 @@ func GenerateSARIF(result analysis.DiffResult, violations []policy.Violation, sb
 			ShortDescription: SARIFMessage{Text: "Component version was updated"},
 			DefaultConfig:    SARIFRuleConfig{Level: "note"},
 		},
+		{
+			ID:               "license-change",
+			Name:             "License Changed",
+			ShortDescription: SARIFMessage{Text: "Component licenses were added or removed"},
+			DefaultConfig:    SARIFRuleConfig{Level: "note"},
+			Properties:       &SARIFProperties{Tags: []string{"license"}},
+		},
+		{
+			ID:               "supplier-change",
+			Name:             "Supplier Changed",
+			ShortDescription: SARIFMessage{Text: "Component supplier changed"},
+			DefaultConfig:    SARIFRuleConfig{Level: "warning"},
+			Properties:       &SARIFProperties{Tags: []string{"supply-chain"}},
+		},
+		{
+			ID:               "property-change",
+			Name:             "Property Changed",
+			ShortDescription: SARIFMessage{Text: "Component properties were added, removed or changed"},
+			DefaultConfig:    SARIFRuleConfig{Level: "note"},
+		},
 		{
 			ID:               "deep-dependency",
 			Name:             "Deep Transitive Dependency",
-			ShortDescription: SARIFMessage{Text: "New dependency introduced at depth 3 or greater"},
+			ShortDescription: SARIFMessage{Text: fmt.Sprintf("New dependency introduced at depth %d or greater", deepThreshold)},
 			DefaultConfig:    SARIFRuleConfig{Level: "warning"},
 			Properties:       &SARIFProperties{Tags: []string{"security", "supply-chain"}},
 		},
@@ -133,11 +159,15 @@ func GenerateSARIF(result analysis.DiffResult, violations []policy.Violation, sb
 
 	if result.Dependencies != nil {
 		for _, td := range result.Dependencies.TransitiveNew {
-			if td.Depth >= 3 {
+			if td.Depth >= deepThreshold {
+				msg := fmt.Sprintf("New transitive dependency %s at depth %d", td.Target, td.Depth)
+				if len(td.Via) > 0 {
+					msg += ", introduced via " + strings.Join(td.Via, " → ")
+				}
 				results = append(results, SARIFResult{
 					RuleID:  "deep-dependency",
 					Level:   "warning",
-					Message: SARIFMessage{Text: fmt.Sprintf("New transitive dependency %s at depth %d", td.Target, td.Depth)},
+					Message: SARIFMessage{Text: msg},
 					Locations: []SARIFLocation{{
 						PhysicalLocation: SARIFPhysicalLocation{
 							ArtifactLocation: SARIFArtifactLocation{URI: sbomFile},
@@ -189,6 +219,36 @@ func GenerateSARIF(result analysis.DiffResult, violations []policy.Violation, sb
 		}
 	}
 
+	for _, changed := range result.Changed {
+		if changed.Drift == nil {
+			continue
+		}
+		for _, kind := range changed.Drift.MetadataKinds() {
+			var ruleID, level, text string
+			switch kind {
+			case analysis.MetadataLicense:
+				ruleID, level = "license-change", "note"
+				text = fmt.Sprintf("Component %s licenses changed: %s", changed.Name, strings.Join(changed.Drift.LicensesDiff, " "))
+			case analysis.MetadataSupplier:
+				ruleID, level = "supplier-change", "warning"
+				text = fmt.Sprintf("Component %s supplier changed: %s -> %s", changed.Name, changed.Drift.SupplierFrom, changed.Drift.SupplierTo)
+			case analysis.MetadataProperty:
+				ruleID, level = "property-change", "note"
+				text = fmt.Sprintf("Component %s properties changed: %s", changed.Name, strings.Join(changed.Drift.Properties, ", "))
+			}
+			results = append(results, SARIFResult{
+				RuleID:  ruleID,
+				Level:   level,
+				Message: SARIFMessage{Text: text},
+				Locations: []SARIFLocation{{
+					PhysicalLocation: SARIFPhysicalLocation{
+						ArtifactLocation: SARIFArtifactLocation{URI: sbomFile},
+					},
+				}},
+			})
+		}
+	}
+
 	for _, v := range violations {
 		level := "error"
 		if v.Severity == policy.SeverityWarning {
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/policy"
+)
+
+// DiffSummary is the --format summary-json object: the diff's headline
+// counts and violation counts, with no component data.
+type DiffSummary struct {
+	analysis.DiffStats
+	Violations ViolationCounts `json:"violations"`
+}
+
+// ViolationCounts counts policy violations by severity.
+type ViolationCounts struct {
+	Error   int `json:"error"`
+	Warning int `json:"warning"`
+}
+
+// NewDiffSummary builds the summary from a diff's stats and violations.
+func NewDiffSummary(stats analysis.DiffStats, violations []policy.Violation) DiffSummary {
+	s := DiffSummary{DiffStats: stats}
+	for _, v := range violations {
+		switch v.Severity {
+		case policy.SeverityError:
+			s.Violations.Error++
+		case policy.SeverityWarning:
+			s.Violations.Warning++
+		}
+	}
+	return s
+}
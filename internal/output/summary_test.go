@@ -0,0 +1,51 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/policy"
+)
+
+func TestNewDiffSummary(t *testing.T) {
+	violations := []policy.Violation{
+		{Rule: "deny_licenses", Severity: policy.SeverityError},
+		{Rule: "max_added", Severity: policy.SeverityError},
+		{Rule: "warn_new_transitive", Severity: policy.SeverityWarning},
+	}
+	summary := NewDiffSummary(analysis.DiffStats{Added: 2, Removed: 1, Changed: 3, Downgrades: 1}, violations)
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"added": 2.0, "removed": 1.0, "changed": 3.0,
+		"version_drift": 0.0, "integrity_drift": 0.0, "metadata_drift": 0.0,
+		"downgrades": 1.0, "deep_deps": 0.0,
+		"violations": map[string]any{"error": 2.0, "warning": 1.0},
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d keys, got %d: %s", len(want), len(got), data)
+	}
+	for key, w := range want {
+		if sub, ok := w.(map[string]any); ok {
+			g, _ := got[key].(map[string]any)
+			for k, v := range sub {
+				if g[k] != v {
+					t.Errorf("%s.%s = %v, want %v", key, k, g[k], v)
+				}
+			}
+			continue
+		}
+		if got[key] != w {
+			t.Errorf("%s = %v, want %v", key, got[key], w)
+		}
+	}
+}
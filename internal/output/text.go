@@ -2,7 +2,10 @@ package output
 
 import (
 	"fmt"
+	"maps"
+	"math"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/rezmoss/sbomlyze/internal/analysis"
@@ -163,15 +166,72 @@ func PrintScanContext(overview analysis.DiffOverview) {
 	}
 }
 
+// PrintStatsDelta prints how the aggregate stats moved between the SBOMs.
+func PrintStatsDelta(d analysis.StatsDelta) {
+	count := func(label string, c analysis.CountDelta) {
+		change := ""
+		if c.Change != 0 {
+			change = fmt.Sprintf("%+d", c.Change)
+		}
+		fmt.Printf("%-24s%-24d%-16d%s\n", label, c.Before, c.After, change)
+	}
+	percent := func(label string, p analysis.PercentDelta) {
+		change := ""
+		if math.Abs(p.Change) >= 0.05 {
+			change = fmt.Sprintf("%+.1f pp", p.Change)
+		}
+		fmt.Printf("%-24s%-24s%-16s%s\n", label, fmt.Sprintf("%.1f%%", p.Before), fmt.Sprintf("%.1f%%", p.After), change)
+	}
+
+	fmt.Printf("\nStats Delta:\n")
+	fmt.Printf("%-24s%-24s%-16s%s\n", "", "Before", "After", "Change")
+	count("Total Components:", d.TotalComponents)
+	types := slices.Sorted(maps.Keys(d.ByType))
+	slices.SortStableFunc(types, func(a, b string) int {
+		return d.ByType[b].After - d.ByType[a].After
+	})
+	for _, t := range types {
+		count(fmt.Sprintf("  %s:", t), d.ByType[t])
+	}
+	fmt.Printf("Licenses:\n")
+	count("  Copyleft:", d.LicenseCategories.Copyleft)
+	count("  Permissive:", d.LicenseCategories.Permissive)
+	count("  Public Domain:", d.LicenseCategories.PublicDomain)
+	count("  Unknown:", d.LicenseCategories.Unknown)
+	fmt.Printf("Coverage:\n")
+	percent("  PURL:", d.Coverage.PURL)
+	percent("  License:", d.Coverage.License)
+	percent("  Hash:", d.Coverage.Hash)
+	percent("  CPE:", d.Coverage.CPE)
+	fmt.Printf("Dependencies:\n")
+	count("  Total Edges:", d.TotalDependencies)
+	count("  With Deps:", d.WithDependencies)
+	count("  Max Depth:", d.MaxDepth)
+}
+
+// PrintHealthChecks prints the --baseline-stats coverage checks.
+func PrintHealthChecks(checks []analysis.HealthCheck, opts TextOptions) {
+	fmt.Printf("Baseline Check:\n")
+	fmt.Printf("  %-18s%-12s%-12s%-12s%s\n", "Metric", "Kind", "Expected", "Current", "Status")
+	for _, c := range checks {
+		status := "ok"
+		if c.Regressed {
+			status = opts.icon("❌ REGRESSED", "! REGRESSED")
+		}
+		fmt.Printf("  %-18s%-12s%-12s%-12s%s\n", c.Metric, c.Kind, fmt.Sprintf("%.1f%%", c.Expected), fmt.Sprintf("%.1f%%", c.Current), status)
+	}
+	fmt.Println()
+}
+
 // PrintKeyFindings prints key findings.
-func PrintKeyFindings(findings analysis.KeyFindings) {
+func PrintKeyFindings(findings analysis.KeyFindings, opts TextOptions) {
 	if len(findings.Findings) == 0 {
 		return
 	}
 
 	fmt.Printf("\nKey Findings:\n")
 	for _, f := range findings.Findings {
-		fmt.Printf("  %s %s\n", f.Icon, f.Message)
+		fmt.Printf("  %s %s\n", opts.icon(f.Icon, "*"), f.Message)
 	}
 }
 
@@ -212,73 +272,228 @@ func PrintPackageSamples(added, removed []analysis.PackageSamplesByType) {
 	}
 }
 
-// PrintTextDiff prints the diff in text format.
-func PrintTextDiff(result analysis.DiffResult) {
-	if len(result.Added) == 0 && len(result.Removed) == 0 && len(result.Changed) == 0 && result.Duplicates == nil && result.Dependencies == nil {
+// PrintTextSummary prints only the diff counts and drift summary.
+func PrintTextSummary(result analysis.DiffResult, opts TextOptions) {
+	if len(result.Added) == 0 && len(result.Removed) == 0 && len(result.Changed) == 0 {
 		fmt.Println("No differences found")
 		return
 	}
 
-	if result.DriftSummary != nil {
-		fmt.Println("\n📊 Drift Summary:")
-		if result.DriftSummary.VersionDrift > 0 {
-			fmt.Printf("  📦 Version drift:   %d components\n", result.DriftSummary.VersionDrift)
-		}
-		if result.DriftSummary.IntegrityDrift > 0 {
-			fmt.Printf("  ⚠️  Integrity drift: %d components (hash changed without version change!)\n", result.DriftSummary.IntegrityDrift)
+	fmt.Printf("\n%sDiff Summary:\n", opts.icon("📋 ", ""))
+	fmt.Printf("  Added:   %d\n", len(result.Added))
+	fmt.Printf("  Removed: %d\n", len(result.Removed))
+	fmt.Printf("  Changed: %d\n", len(result.Changed))
+
+	printDriftSummary(result.DriftSummary, opts)
+	fmt.Println()
+}
+
+// metadataKindCounts lists the non-zero metadata drift counts by kind,
+// e.g. "license 2, supplier 1".
+func metadataKindCounts(ds *analysis.DriftSummary) string {
+	var parts []string
+	for _, k := range []struct {
+		kind string
+		n    int
+	}{
+		{analysis.MetadataLicense, ds.LicenseDrift},
+		{analysis.MetadataSupplier, ds.SupplierDrift},
+		{analysis.MetadataProperty, ds.PropertyDrift},
+	} {
+		if k.n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", k.kind, k.n))
 		}
-		if result.DriftSummary.MetadataDrift > 0 {
-			fmt.Printf("  📝 Metadata drift:  %d components\n", result.DriftSummary.MetadataDrift)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printDriftSummary(ds *analysis.DriftSummary, opts TextOptions) {
+	if ds == nil {
+		return
+	}
+	fmt.Printf("\n%sDrift Summary:\n", opts.icon("📊 ", ""))
+	if ds.VersionDrift > 0 {
+		fmt.Printf("  %sVersion drift:   %d components\n", opts.icon("📦 ", "~ "), ds.VersionDrift)
+	}
+	if ds.IntegrityDrift > 0 {
+		fmt.Printf("  %sIntegrity drift: %d components (hash changed without version change!)\n", opts.icon("⚠️  ", "! "), ds.IntegrityDrift)
+	}
+	if ds.MetadataDrift > 0 {
+		kinds := ""
+		if counts := metadataKindCounts(ds); counts != "" {
+			kinds = " (" + counts + ")"
 		}
+		fmt.Printf("  %sMetadata drift:  %d components%s\n", opts.icon("📝 ", "* "), ds.MetadataDrift, kinds)
+	}
+	if ds.LicenseRemoved > 0 {
+		fmt.Printf("  %sLicense removed: %d components (licensed before, unlicensed now)\n", opts.icon("🚫 ", "x "), ds.LicenseRemoved)
+	}
+	if ds.SuspiciousVersionJump > 0 {
+		fmt.Printf("  %sSuspicious jump: %d components (major version leap, possible dependency confusion)\n", opts.icon("🚩 ", "! "), ds.SuspiciousVersionJump)
+	}
+}
+
+// exactMarker flags duplicate groups that repeat the same version.
+func exactMarker(d analysis.DuplicateGroup) string {
+	if !d.Exact {
+		return ""
+	}
+	return " [exact copies]"
+}
+
+// printVendored lists vendored copies, which are not counted as duplicates.
+func printVendored(side string, vendored []analysis.VendoredDuplicate, opts TextOptions) {
+	if len(vendored) == 0 {
+		return
+	}
+	fmt.Printf("\n= Vendored copies in %s SBOM (%d):\n", side, len(vendored))
+	shown, more := limitItems(vendored, opts.MaxItems)
+	for _, v := range shown {
+		fmt.Printf("  = %s %s: %s\n", v.Name, v.Version, strings.Join(v.Subpaths, ", "))
 	}
+	printMore(more)
+}
 
-	if len(result.Added) > 0 {
-		fmt.Printf("\n+ Added (%d):\n", len(result.Added))
-		for _, c := range result.Added {
-			fmt.Printf("  + %s %s\n", c.Name, c.Version)
+// printComponentSections prints the added, removed and changed sections
+// of a text diff.
+func printComponentSections(added, removed []sbom.Component, changed []analysis.ChangedComponent, opts TextOptions) {
+	if len(added) > 0 {
+		fmt.Printf("\n+ Added (%d):\n", len(added))
+		shown, more := limitItems(added, opts.MaxItems)
+		for _, c := range shown {
+			fmt.Printf("  + %s %s%s\n", c.Name, c.Version, riskMarker(opts.RiskScores, c.ID))
+			printIDBasis(c)
 		}
+		printMore(more)
 	}
 
-	if len(result.Removed) > 0 {
-		fmt.Printf("\n- Removed (%d):\n", len(result.Removed))
-		for _, c := range result.Removed {
+	if len(removed) > 0 {
+		fmt.Printf("\n- Removed (%d):\n", len(removed))
+		shown, more := limitItems(removed, opts.MaxItems)
+		for _, c := range shown {
 			fmt.Printf("  - %s %s\n", c.Name, c.Version)
+			printIDBasis(c)
 		}
+		printMore(more)
 	}
 
-	if len(result.Changed) > 0 {
-		fmt.Printf("\n~ Changed (%d):\n", len(result.Changed))
-		for _, c := range result.Changed {
-			driftIndicator := ""
-			if c.Drift != nil {
-				switch c.Drift.Type {
-				case analysis.DriftTypeIntegrity:
-					driftIndicator = " ⚠️  [INTEGRITY]"
-				case analysis.DriftTypeVersion:
-					driftIndicator = ""
-				case analysis.DriftTypeMetadata:
-					driftIndicator = " [metadata]"
+	if len(changed) > 0 {
+		fmt.Printf("\n~ Changed (%d):\n", len(changed))
+		shown, more := limitItems(changed, opts.MaxItems)
+		if opts.Wide {
+			printChangedTable(shown)
+		} else {
+			for _, c := range shown {
+				driftIndicator := ""
+				if c.Drift != nil {
+					switch c.Drift.Type {
+					case analysis.DriftTypeIntegrity:
+						driftIndicator = opts.icon(" ⚠️  [INTEGRITY]", " ! [INTEGRITY]")
+					case analysis.DriftTypeVersion:
+						if c.Drift.VersionChange == analysis.VersionChangeRelease {
+							driftIndicator = " [release-only]"
+						}
+					case analysis.DriftTypeMetadata:
+						driftIndicator = " [metadata]"
+						if kinds := c.Drift.MetadataKinds(); len(kinds) > 0 {
+							driftIndicator = " [metadata: " + strings.Join(kinds, ", ") + "]"
+						}
+					}
+					if c.Drift.LicenseRemoved {
+						driftIndicator += " [LICENSE REMOVED]"
+					}
+					if c.Drift.SuspiciousJump {
+						driftIndicator += " [SUSPICIOUS JUMP]"
+					}
+				}
+				fmt.Printf("  ~ %s%s%s\n", c.Name, driftIndicator, riskMarker(opts.RiskScores, c.ID))
+				printIDBasis(c.After)
+				for _, ch := range c.Changes {
+					fmt.Printf("      %s\n", ch)
 				}
 			}
-			fmt.Printf("  ~ %s%s\n", c.Name, driftIndicator)
-			for _, ch := range c.Changes {
-				fmt.Printf("      %s\n", ch)
-			}
 		}
+		printMore(more)
+	}
+}
+
+// TextOptions controls text and Markdown output.
+type TextOptions struct {
+	// NoColor swaps emoji for ASCII markers.
+	NoColor bool
+	// MaxItems caps the entries shown per listing; 0 shows all.
+	MaxItems int
+	// Wide prints the Changed section as one aligned row per component.
+	Wide bool
+	// GroupByType splits the added, removed and changed sections into one
+	// block per PURL type.
+	GroupByType bool
+	// RiskScores, keyed by component ID, labels added and changed
+	// components with their risk score; nil hides the scores.
+	RiskScores map[string]int
+}
+
+// PrintTextDiff prints the diff in text format.
+func PrintTextDiff(result analysis.DiffResult, opts TextOptions) {
+	if len(result.Added) == 0 && len(result.Removed) == 0 && len(result.Changed) == 0 && result.Duplicates == nil && result.Dependencies == nil {
+		fmt.Println("No differences found")
+		return
+	}
+
+	printDriftSummary(result.DriftSummary, opts)
+
+	if opts.GroupByType {
+		for _, g := range groupDiffByType(result) {
+			fmt.Printf("\n== %s (+%d -%d ~%d) ==\n", g.Type, len(g.Added), len(g.Removed), len(g.Changed))
+			printComponentSections(g.Added, g.Removed, g.Changed, opts)
+		}
+	} else {
+		printComponentSections(result.Added, result.Removed, result.Changed, opts)
+	}
+
+	if len(result.TypeChanged) > 0 {
+		fmt.Printf("\n%sPURL type changed (%d):\n", opts.icon("🔀 ", "<> "), len(result.TypeChanged))
+		shown, more := limitItems(result.TypeChanged, opts.MaxItems)
+		for _, tc := range shown {
+			fmt.Printf("  %s: pkg:%s %s -> pkg:%s %s\n", tc.Name, tc.FromType, tc.Before.Version, tc.ToType, tc.After.Version)
+		}
+		printMore(more)
+	}
+
+	if len(result.Shadows) > 0 {
+		fmt.Printf("\n%sCross-ecosystem name reuse (%d):\n", opts.icon("⚠️  ", "!! "), len(result.Shadows))
+		shown, more := limitItems(result.Shadows, opts.MaxItems)
+		for _, s := range shown {
+			fmt.Printf("  %s: pkg:%s %s added; name already used by pkg:%s\n", s.Name, s.Type, s.Added.Version, strings.Join(s.ExistingTypes, ", pkg:"))
+		}
+		printMore(more)
+	}
+
+	if result.Churn != nil {
+		fmt.Printf("\n%sChurn (%d upgrades, %d replacements):\n", opts.icon("♻️  ", "<> "), result.Churn.Upgrades, result.Churn.Replacements)
+		shown, more := limitItems(result.Churn.Entries, opts.MaxItems)
+		for _, e := range shown {
+			fmt.Printf("  %s: %s -> %s (%s)\n", e.Name, e.Before.Version, e.After.Version, e.Kind)
+		}
+		printMore(more)
 	}
 
 	if result.Duplicates != nil {
 		if len(result.Duplicates.Before) > 0 {
 			fmt.Printf("\n! Duplicates in first SBOM (%d):\n", len(result.Duplicates.Before))
-			for _, d := range result.Duplicates.Before {
-				fmt.Printf("  ! %s: %v\n", d.Name, d.Versions)
+			shown, more := limitItems(result.Duplicates.Before, opts.MaxItems)
+			for _, d := range shown {
+				fmt.Printf("  ! %s: %v%s\n", d.Name, d.Versions, exactMarker(d))
 			}
+			printMore(more)
 		}
 		if len(result.Duplicates.After) > 0 {
 			fmt.Printf("\n! Duplicates in second SBOM (%d):\n", len(result.Duplicates.After))
-			for _, d := range result.Duplicates.After {
-				fmt.Printf("  ! %s: %v\n", d.Name, d.Versions)
+			shown, more := limitItems(result.Duplicates.After, opts.MaxItems)
+			for _, d := range shown {
+				fmt.Printf("  ! %s: %v%s\n", d.Name, d.Versions, exactMarker(d))
 			}
+			printMore(more)
 		}
 		if result.Duplicates.VersionDiff != nil {
 			vd := result.Duplicates.VersionDiff
@@ -296,19 +511,21 @@ func PrintTextDiff(result analysis.DiffResult) {
 			}
 			if len(vd.VersionsAdded) > 0 {
 				fmt.Printf("\n+v Versions added to duplicates:\n")
-				for id, versions := range vd.VersionsAdded {
-					fmt.Printf("  %s: +%v\n", id, versions)
+				for _, id := range slices.Sorted(maps.Keys(vd.VersionsAdded)) {
+					fmt.Printf("  %s: +%v\n", id, vd.VersionsAdded[id])
 				}
 			}
 			if len(vd.VersionsRemoved) > 0 {
 				fmt.Printf("\n-v Versions removed from duplicates:\n")
-				for id, versions := range vd.VersionsRemoved {
-					fmt.Printf("  %s: -%v\n", id, versions)
+				for _, id := range slices.Sorted(maps.Keys(vd.VersionsRemoved)) {
+					fmt.Printf("  %s: -%v\n", id, vd.VersionsRemoved[id])
 				}
 			}
 		}
+		printVendored("first", result.Duplicates.VendoredBefore, opts)
+		printVendored("second", result.Duplicates.VendoredAfter, opts)
 		if len(result.Duplicates.Collisions) > 0 {
-			fmt.Printf("\n⚠️  Identity Collisions (%d):\n", len(result.Duplicates.Collisions))
+			fmt.Printf("\n%sIdentity Collisions (%d):\n", opts.icon("⚠️  ", "! "), len(result.Duplicates.Collisions))
 			for _, c := range result.Duplicates.Collisions {
 				fmt.Printf("  [%s] %s\n", c.Reason, c.ID)
 				for _, comp := range c.Components {
@@ -321,45 +538,71 @@ func PrintTextDiff(result analysis.DiffResult) {
 	if result.Dependencies != nil {
 		if len(result.Dependencies.AddedDeps) > 0 {
 			fmt.Printf("\n>> Added dependencies:\n")
-			for comp, deps := range result.Dependencies.AddedDeps {
-				fmt.Printf("  %s: +%v\n", comp, deps)
+			shown, more := limitItems(slices.Sorted(maps.Keys(result.Dependencies.AddedDeps)), opts.MaxItems)
+			for _, comp := range shown {
+				fmt.Printf("  %s: +%v\n", comp, result.Dependencies.AddedDeps[comp])
 			}
+			printMore(more)
 		}
 		if len(result.Dependencies.RemovedDeps) > 0 {
 			fmt.Printf("\n<< Removed dependencies:\n")
-			for comp, deps := range result.Dependencies.RemovedDeps {
-				fmt.Printf("  %s: -%v\n", comp, deps)
+			shown, more := limitItems(slices.Sorted(maps.Keys(result.Dependencies.RemovedDeps)), opts.MaxItems)
+			for _, comp := range shown {
+				fmt.Printf("  %s: -%v\n", comp, result.Dependencies.RemovedDeps[comp])
 			}
+			printMore(more)
 		}
 
 		if len(result.Dependencies.TransitiveNew) > 0 {
-			fmt.Printf("\n🔗 New transitive dependencies (%d):\n", len(result.Dependencies.TransitiveNew))
-			for _, td := range result.Dependencies.TransitiveNew {
+			fmt.Printf("\n%sNew transitive dependencies (%d):\n", opts.icon("🔗 ", "+ "), len(result.Dependencies.TransitiveNew))
+			shown, more := limitItems(result.Dependencies.TransitiveNew, opts.MaxItems)
+			for _, td := range shown {
 				fmt.Printf("  + %s (depth %d)\n", td.Target, td.Depth)
 				if len(td.Via) > 0 {
 					fmt.Printf("    via: %v\n", td.Via)
 				}
 			}
+			printMore(more)
 		}
 		if len(result.Dependencies.TransitiveLost) > 0 {
-			fmt.Printf("\n🔓 Removed transitive dependencies (%d):\n", len(result.Dependencies.TransitiveLost))
-			for _, td := range result.Dependencies.TransitiveLost {
-				fmt.Printf("  - %s (depth %d)\n", td.Target, td.Depth)
+			fmt.Printf("\n%sRemoved transitive dependencies (%d):\n", opts.icon("🔓 ", "- "), len(result.Dependencies.TransitiveLost))
+			shown, more := limitItems(result.Dependencies.SeveredChains(), opts.MaxItems)
+			for _, chain := range shown {
+				if len(chain.Lost) == 1 {
+					td := chain.Lost[0]
+					fmt.Printf("  - %s (depth %d)\n", td.Target, td.Depth)
+					if len(td.Via) > 0 {
+						fmt.Printf("    via: %v\n", td.Via)
+					}
+					continue
+				}
+				fmt.Printf("  - %s -> %s removed, cutting off %d:\n", chain.From, chain.To, len(chain.Lost))
+				for _, td := range chain.Lost {
+					fmt.Printf("      %s (depth %d)\n", td.Target, td.Depth)
+				}
 			}
+			printMore(more)
 		}
 
 		if result.Dependencies.DepthSummary != nil {
 			ds := result.Dependencies.DepthSummary
 			if ds.Depth1 > 0 || ds.Depth2 > 0 || ds.Depth3Plus > 0 {
-				fmt.Printf("\n📊 New deps by depth:\n")
-				if ds.Depth1 > 0 {
-					fmt.Printf("  Depth 1 (direct):     %d\n", ds.Depth1)
-				}
-				if ds.Depth2 > 0 {
-					fmt.Printf("  Depth 2:              %d\n", ds.Depth2)
+				fmt.Printf("\n%sNew deps by depth:\n", opts.icon("📊 ", ""))
+				printDepthBucket := func(label string, depth, n int) {
+					if n == 0 {
+						return
+					}
+					if ds.IsDeep(depth) {
+						fmt.Printf("  %-22s%d %s\n", label+" (risky):", n, opts.icon("⚠️", "!"))
+					} else {
+						fmt.Printf("  %-22s%d\n", label+":", n)
+					}
 				}
-				if ds.Depth3Plus > 0 {
-					fmt.Printf("  Depth 3+ (risky):     %d ⚠️\n", ds.Depth3Plus)
+				printDepthBucket("Depth 1 (direct)", 1, ds.Depth1)
+				printDepthBucket("Depth 2", 2, ds.Depth2)
+				printDepthBucket("Depth 3+", 3, ds.Depth3Plus)
+				if t := ds.DeepThreshold(); t > 3 && ds.Deep > 0 {
+					printDepthBucket(fmt.Sprintf("Depth %d+", t), t, ds.Deep)
 				}
 			}
 		}
@@ -368,8 +611,45 @@ func PrintTextDiff(result analysis.DiffResult) {
 	fmt.Println()
 }
 
+// PrintDirectoryDiff prints a directory rollup followed by one section per paired file.
+func PrintDirectoryDiff(dir analysis.DirDiffResult, summaryOnly bool, opts TextOptions) {
+	r := dir.Rollup
+	fmt.Printf("%sDirectory Diff\n", opts.icon("📂 ", ""))
+	fmt.Println("==================")
+	fmt.Printf("  Files compared:  %d\n", r.FilesCompared)
+	fmt.Printf("  Files changed:   %d\n", r.FilesChanged)
+	fmt.Printf("  Added:           %d\n", r.Added)
+	fmt.Printf("  Removed:         %d\n", r.Removed)
+	fmt.Printf("  Changed:         %d\n", r.Changed)
+	if r.IntegrityDrift > 0 {
+		fmt.Printf("  %sIntegrity drift: %d\n", opts.icon("⚠️  ", "! "), r.IntegrityDrift)
+	}
+
+	if len(dir.OnlyBefore) > 0 {
+		fmt.Printf("\nSkipped, only in before (%d):\n", len(dir.OnlyBefore))
+		for _, name := range dir.OnlyBefore {
+			fmt.Printf("  - %s\n", name)
+		}
+	}
+	if len(dir.OnlyAfter) > 0 {
+		fmt.Printf("\nSkipped, only in after (%d):\n", len(dir.OnlyAfter))
+		for _, name := range dir.OnlyAfter {
+			fmt.Printf("  + %s\n", name)
+		}
+	}
+
+	for _, f := range dir.Files {
+		fmt.Printf("\n=== %s ===\n", f.Name)
+		if summaryOnly {
+			PrintTextSummary(f.Diff, opts)
+		} else {
+			PrintTextDiff(f.Diff, opts)
+		}
+	}
+}
+
 // PrintViolations prints policy violations.
-func PrintViolations(violations []policy.Violation) {
+func PrintViolations(violations []policy.Violation, opts TextOptions) {
 	if len(violations) == 0 {
 		return
 	}
@@ -384,13 +664,13 @@ func PrintViolations(violations []policy.Violation) {
 	}
 
 	if len(errors) > 0 {
-		fmt.Printf("\n❌ Policy Errors (%d):\n", len(errors))
+		fmt.Printf("\n%sPolicy Errors (%d):\n", opts.icon("❌ ", "! "), len(errors))
 		for _, v := range errors {
 			fmt.Printf("  [%s] %s\n", v.Rule, v.Message)
 		}
 	}
 	if len(warnings) > 0 {
-		fmt.Printf("\n⚠️  Policy Warnings (%d):\n", len(warnings))
+		fmt.Printf("\n%sPolicy Warnings (%d):\n", opts.icon("⚠️  ", "! "), len(warnings))
 		for _, v := range warnings {
 			fmt.Printf("  [%s] %s\n", v.Rule, v.Message)
 		}
@@ -3,6 +3,7 @@ package output
 import (
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -24,7 +25,7 @@ func captureOutput(f func()) string {
 
 func TestPrintTextDiff_NoDifferences(t *testing.T) {
 	out := captureOutput(func() {
-		PrintTextDiff(analysis.DiffResult{})
+		PrintTextDiff(analysis.DiffResult{}, TextOptions{})
 	})
 	if !strings.Contains(out, "No differences found") {
 		t.Errorf("expected 'No differences found', got: %s", out)
@@ -38,7 +39,7 @@ func TestPrintTextDiff_Added(t *testing.T) {
 		},
 	}
 	out := captureOutput(func() {
-		PrintTextDiff(result)
+		PrintTextDiff(result, TextOptions{})
 	})
 	if !strings.Contains(out, "+ Added") {
 		t.Error("expected '+ Added' section")
@@ -55,7 +56,7 @@ func TestPrintTextDiff_Removed(t *testing.T) {
 		},
 	}
 	out := captureOutput(func() {
-		PrintTextDiff(result)
+		PrintTextDiff(result, TextOptions{})
 	})
 	if !strings.Contains(out, "- Removed") {
 		t.Error("expected '- Removed' section")
@@ -76,7 +77,7 @@ func TestPrintTextDiff_Changed(t *testing.T) {
 		},
 	}
 	out := captureOutput(func() {
-		PrintTextDiff(result)
+		PrintTextDiff(result, TextOptions{})
 	})
 	if !strings.Contains(out, "~ Changed") {
 		t.Error("expected '~ Changed' section")
@@ -97,13 +98,35 @@ func TestPrintTextDiff_IntegrityDrift(t *testing.T) {
 		},
 	}
 	out := captureOutput(func() {
-		PrintTextDiff(result)
+		PrintTextDiff(result, TextOptions{})
 	})
 	if !strings.Contains(out, "[INTEGRITY]") {
 		t.Error("expected [INTEGRITY] indicator")
 	}
 }
 
+func TestPrintTextDiff_LicenseRemoved(t *testing.T) {
+	result := analysis.DiffResult{
+		Changed: []analysis.ChangedComponent{
+			{Name: "unlicensed-pkg", Drift: &analysis.DriftInfo{Type: analysis.DriftTypeMetadata, LicenseRemoved: true}},
+			{Name: "licensed-pkg", Drift: &analysis.DriftInfo{Type: analysis.DriftTypeMetadata}},
+		},
+		DriftSummary: &analysis.DriftSummary{MetadataDrift: 2, LicenseRemoved: 1},
+	}
+	out := captureOutput(func() {
+		PrintTextDiff(result, TextOptions{})
+	})
+	if !strings.Contains(out, "unlicensed-pkg [metadata] [LICENSE REMOVED]") {
+		t.Errorf("expected [LICENSE REMOVED] marker, got:\n%s", out)
+	}
+	if strings.Count(out, "[LICENSE REMOVED]") != 1 {
+		t.Errorf("expected marker only on the unlicensed component, got:\n%s", out)
+	}
+	if !strings.Contains(out, "License removed: 1 components") {
+		t.Errorf("expected license removed count in drift summary, got:\n%s", out)
+	}
+}
+
 func TestPrintTextDiff_MetadataDrift(t *testing.T) {
 	result := analysis.DiffResult{
 		Changed: []analysis.ChangedComponent{
@@ -115,7 +138,7 @@ func TestPrintTextDiff_MetadataDrift(t *testing.T) {
 		},
 	}
 	out := captureOutput(func() {
-		PrintTextDiff(result)
+		PrintTextDiff(result, TextOptions{})
 	})
 	if !strings.Contains(out, "[metadata]") {
 		t.Error("expected [metadata] indicator")
@@ -131,13 +154,33 @@ func TestPrintTextDiff_Duplicates(t *testing.T) {
 		},
 	}
 	out := captureOutput(func() {
-		PrintTextDiff(result)
+		PrintTextDiff(result, TextOptions{})
 	})
 	if !strings.Contains(out, "Duplicates") {
 		t.Error("expected Duplicates section")
 	}
 }
 
+func TestPrintTextDiff_ExactDuplicates(t *testing.T) {
+	result := analysis.DiffResult{
+		Duplicates: &analysis.DuplicateReport{
+			After: []analysis.DuplicateGroup{
+				{Name: "copied-pkg", Versions: []string{"1.0"}, Counts: map[string]int{"1.0": 2}, Exact: true},
+				{Name: "split-pkg", Versions: []string{"1.0", "2.0"}},
+			},
+		},
+	}
+	out := captureOutput(func() {
+		PrintTextDiff(result, TextOptions{})
+	})
+	if !strings.Contains(out, "copied-pkg: [1.0] [exact copies]") {
+		t.Errorf("expected exact copies marker, got:\n%s", out)
+	}
+	if strings.Count(out, "[exact copies]") != 1 {
+		t.Errorf("expected marker only on the exact group, got:\n%s", out)
+	}
+}
+
 func TestPrintTextDiff_Dependencies(t *testing.T) {
 	result := analysis.DiffResult{
 		Dependencies: &analysis.DependencyDiff{
@@ -147,7 +190,7 @@ func TestPrintTextDiff_Dependencies(t *testing.T) {
 		},
 	}
 	out := captureOutput(func() {
-		PrintTextDiff(result)
+		PrintTextDiff(result, TextOptions{})
 	})
 	if !strings.Contains(out, "Added dependencies") {
 		t.Error("expected 'Added dependencies' section")
@@ -163,7 +206,7 @@ func TestPrintTextDiff_TransitiveDeps(t *testing.T) {
 		},
 	}
 	out := captureOutput(func() {
-		PrintTextDiff(result)
+		PrintTextDiff(result, TextOptions{})
 	})
 	if !strings.Contains(out, "transitive") {
 		t.Error("expected transitive section")
@@ -173,6 +216,31 @@ func TestPrintTextDiff_TransitiveDeps(t *testing.T) {
 	}
 }
 
+func TestPrintTextDiff_TransitiveLost(t *testing.T) {
+	result := analysis.DiffResult{
+		Dependencies: &analysis.DependencyDiff{
+			RemovedDeps: map[string][]string{"app": {"web"}, "cli": {"flags"}},
+			TransitiveLost: []analysis.TransitiveDep{
+				{Target: "flags", Via: []string{"app", "cli", "flags"}, Depth: 2},
+				{Target: "path", Via: []string{"app", "web", "router", "path"}, Depth: 3},
+				{Target: "router", Via: []string{"app", "web", "router"}, Depth: 2},
+			},
+		},
+	}
+	out := captureOutput(func() {
+		PrintTextDiff(result, TextOptions{NoColor: true})
+	})
+	for _, want := range []string{
+		"Removed transitive dependencies (3):",
+		"  - flags (depth 2)\n    via: [app cli flags]",
+		"  - app -> web removed, cutting off 2:\n      path (depth 3)\n      router (depth 2)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
 func TestPrintTextDiff_DepthSummary(t *testing.T) {
 	result := analysis.DiffResult{
 		Dependencies: &analysis.DependencyDiff{
@@ -185,7 +253,7 @@ func TestPrintTextDiff_DepthSummary(t *testing.T) {
 		},
 	}
 	out := captureOutput(func() {
-		PrintTextDiff(result)
+		PrintTextDiff(result, TextOptions{})
 	})
 	if !strings.Contains(out, "Depth") || !strings.Contains(out, "depth") {
 		t.Error("expected depth summary section")
@@ -194,7 +262,7 @@ func TestPrintTextDiff_DepthSummary(t *testing.T) {
 
 func TestPrintViolations_Empty(t *testing.T) {
 	out := captureOutput(func() {
-		PrintViolations(nil)
+		PrintViolations(nil, TextOptions{})
 	})
 	if len(strings.TrimSpace(out)) > 0 {
 		t.Errorf("expected no output for empty violations, got: %q", out)
@@ -207,7 +275,7 @@ func TestPrintViolations_ErrorsAndWarnings(t *testing.T) {
 		{Rule: "warn_supplier", Message: "supplier changed", Severity: policy.SeverityWarning},
 	}
 	out := captureOutput(func() {
-		PrintViolations(violations)
+		PrintViolations(violations, TextOptions{})
 	})
 	if !strings.Contains(out, "Policy Errors") {
 		t.Error("expected Policy Errors section")
@@ -222,7 +290,7 @@ func TestPrintViolations_ErrorsOnly(t *testing.T) {
 		{Rule: "deny_licenses", Message: "denied GPL", Severity: policy.SeverityError},
 	}
 	out := captureOutput(func() {
-		PrintViolations(violations)
+		PrintViolations(violations, TextOptions{})
 	})
 	if !strings.Contains(out, "Policy Errors") {
 		t.Error("expected Policy Errors section")
@@ -237,7 +305,7 @@ func TestPrintViolations_WarningsOnly(t *testing.T) {
 		{Rule: "warn_supplier", Message: "supplier changed", Severity: policy.SeverityWarning},
 	}
 	out := captureOutput(func() {
-		PrintViolations(violations)
+		PrintViolations(violations, TextOptions{})
 	})
 	if strings.Contains(out, "Policy Errors") {
 		t.Error("expected NO Policy Errors section")
@@ -246,3 +314,212 @@ func TestPrintViolations_WarningsOnly(t *testing.T) {
 		t.Error("expected Policy Warnings section")
 	}
 }
+
+func TestPrintTextDiff_DeterministicOrder(t *testing.T) {
+	result := analysis.DiffResult{
+		Changed: []analysis.ChangedComponent{{
+			ID:   "pkg:npm/a",
+			Name: "a",
+			Before: sbom.Component{Hashes: map[string]string{
+				"SHA1": "1", "SHA256": "2", "SHA512": "3", "MD5": "4",
+			}},
+			After: sbom.Component{Hashes: map[string]string{
+				"SHA1": "5", "SHA256": "6", "SHA512": "7", "MD5": "8",
+			}},
+		}},
+		Duplicates: &analysis.DuplicateReport{
+			VersionDiff: &analysis.DuplicateVersionDiff{
+				VersionsAdded:   map[string][]string{"z": {"2"}, "a": {"2"}, "m": {"2"}, "c": {"2"}},
+				VersionsRemoved: map[string][]string{"z": {"1"}, "a": {"1"}, "m": {"1"}, "c": {"1"}},
+			},
+		},
+		Dependencies: &analysis.DependencyDiff{
+			AddedDeps:   map[string][]string{"z": {"x"}, "a": {"x"}, "m": {"x"}, "c": {"x"}},
+			RemovedDeps: map[string][]string{"z": {"y"}, "a": {"y"}, "m": {"y"}, "c": {"y"}},
+		},
+	}
+	result.Changed[0].Changes = sbom.CompareComponents(result.Changed[0].Before, result.Changed[0].After)
+
+	first := captureOutput(func() { PrintTextDiff(result, TextOptions{}) })
+	for i := 0; i < 20; i++ {
+		if out := captureOutput(func() { PrintTextDiff(result, TextOptions{}) }); out != first {
+			t.Fatalf("text diff output differs between runs:\n%s\n---\n%s", first, out)
+		}
+	}
+	if strings.Index(first, "  a: +[x]") > strings.Index(first, "  z: +[x]") {
+		t.Error("expected added dependencies sorted by component ID")
+	}
+}
+
+func TestNoColor_ASCIIOnly(t *testing.T) {
+	opts := TextOptions{NoColor: true}
+	result := analysis.DiffResult{
+		Added: []sbom.Component{{Name: "new", Version: "1.0"}},
+		Changed: []analysis.ChangedComponent{{
+			Name:    "lib",
+			Changes: []string{"hash[SHA256]: a -> b"},
+			Drift:   &analysis.DriftInfo{Type: analysis.DriftTypeIntegrity},
+		}},
+		DriftSummary: &analysis.DriftSummary{IntegrityDrift: 1, VersionDrift: 1},
+		Dependencies: &analysis.DependencyDiff{
+			TransitiveNew: []analysis.TransitiveDep{{Target: "deep", Depth: 3}},
+			DepthSummary:  &analysis.DepthSummary{Depth3Plus: 1},
+		},
+	}
+	violations := []policy.Violation{
+		{Rule: "deny_licenses", Message: "denied GPL", Severity: policy.SeverityError},
+		{Rule: "warn_supplier", Message: "supplier changed", Severity: policy.SeverityWarning},
+	}
+	out := captureOutput(func() {
+		PrintTextSummary(result, opts)
+		PrintTextDiff(result, opts)
+		PrintViolations(violations, opts)
+	})
+
+	for i, r := range out {
+		if r == 0x1b || r > 0x7f {
+			t.Fatalf("unexpected non-ASCII or escape byte %q at offset %d in:\n%s", r, i, out)
+		}
+	}
+	for _, want := range []string{"! Integrity drift:", "~ lib ! [INTEGRITY]", "! Policy Errors (1)", "! Policy Warnings (1)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintTextDiff_MaxItems(t *testing.T) {
+	var added []sbom.Component
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		added = append(added, sbom.Component{Name: name, Version: "1.0"})
+	}
+	result := analysis.DiffResult{
+		Added:   added,
+		Removed: []sbom.Component{{Name: "old", Version: "1.0"}},
+	}
+
+	tests := []struct {
+		name     string
+		max      int
+		want     []string
+		dontWant []string
+	}{
+		{"unlimited", 0, []string{"+ Added (5):", "  + e 1.0"}, []string{"more"}},
+		{"truncated", 2, []string{"+ Added (5):", "  + b 1.0", "  ... and 3 more", "  - old 1.0"}, []string{"  + c 1.0", "... and 0 more"}},
+		{"exact fit", 5, []string{"  + e 1.0"}, []string{"more"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := captureOutput(func() {
+				PrintTextDiff(result, TextOptions{MaxItems: tt.max})
+			})
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("expected %q in output:\n%s", w, out)
+				}
+			}
+			for _, w := range tt.dontWant {
+				if strings.Contains(out, w) {
+					t.Errorf("unexpected %q in output:\n%s", w, out)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintTextDiff_Wide(t *testing.T) {
+	result := analysis.DiffResult{
+		Changed: []analysis.ChangedComponent{
+			{
+				Name:    "express",
+				Before:  sbom.Component{Version: "4.18.0"},
+				After:   sbom.Component{Version: "4.18.2"},
+				Changes: []string{"version: 4.18.0 -> 4.18.2"},
+				Drift:   &analysis.DriftInfo{Type: analysis.DriftTypeVersion},
+			},
+			{
+				Name:    "openssl",
+				Before:  sbom.Component{Version: "1.1.1w"},
+				After:   sbom.Component{Version: "3.0.13"},
+				Changes: []string{"version: 1.1.1w -> 3.0.13"},
+				Drift:   &analysis.DriftInfo{Type: analysis.DriftTypeVersion, LicensesDiff: []string{"-OpenSSL", "+Apache-2.0"}},
+			},
+		},
+	}
+
+	out := captureOutput(func() {
+		PrintTextDiff(result, TextOptions{Wide: true})
+	})
+
+	lines := strings.Split(out, "\n")
+	header := slices.IndexFunc(lines, func(l string) bool { return strings.HasPrefix(l, "  NAME") })
+	if header < 0 || header+2 >= len(lines) {
+		t.Fatalf("expected a header and two rows, got:\n%s", out)
+	}
+	rows := lines[header : header+3]
+	for _, col := range []string{"VERSION", "DRIFT", "LICENSES"} {
+		at := strings.Index(rows[0], col)
+		if at < 0 {
+			t.Fatalf("expected %s column in header %q", col, rows[0])
+		}
+		for _, row := range rows[1:] {
+			if len(row) <= at || row[at-1] != ' ' || row[at] == ' ' {
+				t.Errorf("%s column not aligned at %d in %q", col, at, row)
+			}
+		}
+	}
+	if !strings.Contains(rows[2], "1.1.1w -> 3.0.13") || !strings.Contains(rows[2], "+Apache-2.0 -OpenSSL") {
+		t.Errorf("unexpected row %q", rows[2])
+	}
+	if strings.Contains(out, "      version:") {
+		t.Errorf("expected no free-text change lines, got:\n%s", out)
+	}
+}
+
+func TestPrintTextDiff_GroupByType(t *testing.T) {
+	result := analysis.DiffResult{
+		Added: []sbom.Component{
+			{ID: "pkg:npm/left-pad", Name: "left-pad", Version: "1.3.0", PURL: "pkg:npm/left-pad@1.3.0"},
+			{ID: "pkg:apk/alpine/musl", Name: "musl", Version: "1.2.4", PURL: "pkg:apk/alpine/musl@1.2.4"},
+		},
+		Removed: []sbom.Component{
+			{ID: "pkg:npm/lodash", Name: "lodash", Version: "4.17.20", PURL: "pkg:npm/lodash@4.17.20"},
+		},
+		Changed: []analysis.ChangedComponent{
+			{
+				Name:    "busybox",
+				Before:  sbom.Component{Version: "1.36.0", PURL: "pkg:apk/alpine/busybox@1.36.0"},
+				After:   sbom.Component{Version: "1.36.1", PURL: "pkg:apk/alpine/busybox@1.36.1"},
+				Changes: []string{"version: 1.36.0 -> 1.36.1"},
+			},
+		},
+	}
+
+	out := captureOutput(func() {
+		PrintTextDiff(result, TextOptions{GroupByType: true})
+	})
+
+	apk := strings.Index(out, "== apk (+1 -0 ~1) ==")
+	npm := strings.Index(out, "== npm (+1 -1 ~0) ==")
+	if apk < 0 || npm < 0 || apk > npm {
+		t.Fatalf("expected apk then npm headings, got:\n%s", out)
+	}
+	for _, tt := range []struct {
+		line  string
+		inApk bool
+	}{
+		{"+ musl 1.2.4", true},
+		{"~ busybox", true},
+		{"+ left-pad 1.3.0", false},
+		{"- lodash 4.17.20", false},
+	} {
+		at := strings.Index(out, tt.line)
+		if at < 0 {
+			t.Errorf("missing %q", tt.line)
+			continue
+		}
+		if got := at > apk && at < npm; got != tt.inApk {
+			t.Errorf("%q under apk = %v, want %v", tt.line, got, tt.inApk)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+)
+
+// printChangedTable prints changed components as NAME, VERSION, DRIFT and
+// LICENSES columns.
+func printChangedTable(changed []analysis.ChangedComponent) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  NAME\tVERSION\tDRIFT\tLICENSES")
+	for _, c := range changed {
+		version := c.After.Version
+		if c.Before.Version != c.After.Version {
+			version = c.Before.Version + " -> " + c.After.Version
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", c.Name, orNone(version), wideDrift(c.Drift), wideLicenses(c.Drift))
+	}
+	tw.Flush()
+}
+
+// wideDrift is the drift type followed by any markers the default layout
+// shows in brackets.
+func wideDrift(d *analysis.DriftInfo) string {
+	if d == nil {
+		return "-"
+	}
+	parts := []string{string(d.Type)}
+	if d.VersionChange == analysis.VersionChangeRelease {
+		parts = append(parts, "release-only")
+	}
+	if d.SuspiciousJump {
+		parts = append(parts, "suspicious jump")
+	}
+	if d.LicenseRemoved {
+		parts = append(parts, "license removed")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func wideLicenses(d *analysis.DriftInfo) string {
+	if d == nil || len(d.LicensesDiff) == 0 {
+		return "-"
+	}
+	return strings.Join(slices.Sorted(slices.Values(d.LicensesDiff)), " ")
+}
@@ -0,0 +1,88 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+)
+
+// FailCondition is a single --fail-on threshold.
+type FailCondition struct {
+	Name      string // integrity-drift, added, removed, changed, deep-deps, downgrade
+	Threshold int    // fail when count > Threshold
+}
+
+var failOnNames = map[string]bool{
+	"integrity-drift": true,
+	"added":           true,
+	"removed":         true,
+	"changed":         true,
+	"deep-deps":       true,
+	"downgrade":       true,
+}
+
+// ParseFailOn parses a comma-separated --fail-on spec such as
+// "integrity-drift,added>5". A bare name means "> 0".
+func ParseFailOn(spec string) ([]FailCondition, error) {
+	var conds []FailCondition
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, threshold := part, 0
+		if i := strings.Index(part, ">"); i >= 0 {
+			name = strings.TrimSpace(part[:i])
+			n, err := strconv.Atoi(strings.TrimSpace(part[i+1:]))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid threshold in %q", part)
+			}
+			threshold = n
+		}
+		if !failOnNames[name] {
+			return nil, fmt.Errorf("unknown condition %q", name)
+		}
+		conds = append(conds, FailCondition{Name: name, Threshold: threshold})
+	}
+	if len(conds) == 0 {
+		return nil, fmt.Errorf("no conditions given")
+	}
+	return conds, nil
+}
+
+// EvaluateFailOn checks a diff against --fail-on conditions.
+func EvaluateFailOn(conds []FailCondition, result analysis.DiffResult) []Violation {
+	var violations []Violation
+	summary := result.Summary()
+	for _, c := range conds {
+		count := failOnCount(c.Name, summary)
+		if count > c.Threshold {
+			violations = append(violations, Violation{
+				Rule:     "fail_on:" + c.Name,
+				Message:  fmt.Sprintf("%s: %d > %d", c.Name, count, c.Threshold),
+				Severity: SeverityError,
+			})
+		}
+	}
+	return violations
+}
+
+func failOnCount(name string, s analysis.DiffStats) int {
+	switch name {
+	case "added":
+		return s.Added
+	case "removed":
+		return s.Removed
+	case "changed":
+		return s.Changed
+	case "integrity-drift":
+		return s.IntegrityDrift
+	case "deep-deps":
+		return s.DeepDeps
+	case "downgrade":
+		return s.Downgrades
+	}
+	return 0
+}
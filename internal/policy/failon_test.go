@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestParseFailOn(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []FailCondition
+		wantErr bool
+	}{
+		{"integrity-drift", []FailCondition{{"integrity-drift", 0}}, false},
+		{"added>5", []FailCondition{{"added", 5}}, false},
+		{"removed > 0, deep-deps", []FailCondition{{"removed", 0}, {"deep-deps", 0}}, false},
+		{"downgrade,", []FailCondition{{"downgrade", 0}}, false},
+		{"bogus", nil, true},
+		{"added>x", nil, true},
+		{"added>-1", nil, true},
+		{"", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseFailOn(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFailOn(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseFailOn(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseFailOn(%q)[%d] = %v, want %v", tt.spec, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateFailOn(t *testing.T) {
+	result := analysis.DiffResult{
+		Added:   []sbom.Component{{Name: "a"}, {Name: "b"}},
+		Removed: []sbom.Component{{Name: "c"}},
+		Changed: []analysis.ChangedComponent{
+			{Name: "d", Before: sbom.Component{Version: "2.0.0"}, After: sbom.Component{Version: "1.9.0"}},
+			{Name: "e", Before: sbom.Component{Version: "1.0.0"}, After: sbom.Component{Version: "1.1.0"}},
+		},
+		DriftSummary: &analysis.DriftSummary{IntegrityDrift: 1},
+		Dependencies: &analysis.DependencyDiff{DepthSummary: &analysis.DepthSummary{Depth3Plus: 2, Deep: 2}},
+	}
+
+	tests := []struct {
+		cond FailCondition
+		fail bool
+	}{
+		{FailCondition{"added", 1}, true},
+		{FailCondition{"added", 2}, false},
+		{FailCondition{"removed", 0}, true},
+		{FailCondition{"changed", 2}, false},
+		{FailCondition{"integrity-drift", 0}, true},
+		{FailCondition{"deep-deps", 0}, true},
+		{FailCondition{"deep-deps", 2}, false},
+		{FailCondition{"downgrade", 0}, true},
+		{FailCondition{"downgrade", 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cond.Name, func(t *testing.T) {
+			v := EvaluateFailOn([]FailCondition{tt.cond}, result)
+			if (len(v) > 0) != tt.fail {
+				t.Errorf("EvaluateFailOn(%v) = %v, want fail=%v", tt.cond, v, tt.fail)
+			}
+			if len(v) > 0 && v[0].Severity != SeverityError {
+				t.Errorf("expected error severity, got %s", v[0].Severity)
+			}
+		})
+	}
+
+	t.Run("empty diff never fails", func(t *testing.T) {
+		conds, _ := ParseFailOn("integrity-drift,added,removed,deep-deps,downgrade")
+		if v := EvaluateFailOn(conds, analysis.DiffResult{}); len(v) != 0 {
+			t.Errorf("expected no violations, got %v", v)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package policy
+
+import (
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/identity"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// ignores reports whether c matches an IgnorePackages pattern.
+func (p Policy) ignores(c sbom.Component) bool {
+	return MatchesAny(p.IgnorePackages, c)
+}
+
+// MatchesAny reports whether c matches one of the package patterns.
+// Patterns starting with "pkg:" match the PURL: a bare type ("pkg:npm")
+// matches every package of that type, anything else is a glob over the
+// versionless PURL. Other patterns are globs over the component name.
+func MatchesAny(patterns []string, c sbom.Component) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if strings.HasPrefix(pattern, "pkg:") {
+			if c.PURL == "" {
+				continue
+			}
+			purl := versionlessPURL(c.PURL)
+			if !strings.Contains(pattern, "/") {
+				if strings.HasPrefix(purl, pattern+"/") {
+					return true
+				}
+				continue
+			}
+			if ok, _ := path.Match(pattern, purl); ok {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(c.Name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterPackages keeps the components matching an include pattern (all of
+// them when include is empty) and no exclude pattern. Patterns are as for
+// MatchesAny.
+func FilterPackages(comps []sbom.Component, include, exclude []string) []sbom.Component {
+	var kept []sbom.Component
+	for _, c := range comps {
+		if (len(include) == 0 || MatchesAny(include, c)) && !MatchesAny(exclude, c) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// versionlessPURL strips version, qualifiers and subpath from a PURL.
+func versionlessPURL(purl string) string {
+	if i := strings.IndexAny(purl, "?#"); i >= 0 {
+		purl = purl[:i]
+	}
+	if i := strings.LastIndex(purl, "@"); i > strings.LastIndex(purl, "/") {
+		purl = purl[:i]
+	}
+	return purl
+}
+
+// withoutIgnored drops ignored components from the added, removed and
+// changed lists. Other parts of the result are left as-is.
+func (p Policy) withoutIgnored(result analysis.DiffResult) analysis.DiffResult {
+	if len(p.IgnorePackages) == 0 {
+		return result
+	}
+
+	var added, removed []sbom.Component
+	for _, c := range result.Added {
+		if !p.ignores(c) {
+			added = append(added, c)
+		}
+	}
+	for _, c := range result.Removed {
+		if !p.ignores(c) {
+			removed = append(removed, c)
+		}
+	}
+	var changed []analysis.ChangedComponent
+	for _, ch := range result.Changed {
+		if !p.ignores(ch.After) && !p.ignores(ch.Before) {
+			changed = append(changed, ch)
+		}
+	}
+
+	result.Added = added
+	result.Removed = removed
+	result.Changed = changed
+	return result
+}
+
+// deniedVersion finds the DenyVersions pin c falls under. Keys starting
+// with "pkg:" match the versionless PURL, others the name (ignoring case).
+// Both the version field and the PURL version are checked, since SBOMs do
+// not always keep them in sync.
+func deniedVersion(pins map[string][]string, c sbom.Component) (pin, version string, ok bool) {
+	versions := []string{strings.TrimSpace(c.Version)}
+	if v := identity.ExtractPURLVersion(c.PURL); v != "" && v != versions[0] {
+		versions = append(versions, v)
+	}
+	for _, key := range slices.Sorted(maps.Keys(pins)) {
+		k := strings.TrimSpace(key)
+		if strings.HasPrefix(k, "pkg:") {
+			if c.PURL == "" || versionlessPURL(c.PURL) != k {
+				continue
+			}
+		} else if !strings.EqualFold(k, c.Name) {
+			continue
+		}
+		for _, banned := range pins[key] {
+			banned = strings.TrimSpace(banned)
+			if banned != "" && slices.Contains(versions, banned) {
+				return k, banned, true
+			}
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,95 @@
+package policy
+
+import "slices"
+
+// Merge combines policies so each rule is at least as strict as in any
+// input. Limits (and the deep-dependency threshold) take the smallest
+// non-zero value, boolean rules are OR'd, and deny lists (DenyLicenses and
+// each DenyVersions entry) are unioned. Risk weights take the largest value
+// per signal.
+//
+// Allowlists loosen rules, so they merge the other way: IgnorePackages and
+// AllowIntegrityDrift keep only the patterns every input lists, compared as
+// written, and AllowInlineWaivers is kept only if every input sets it.
+func Merge(policies ...Policy) Policy {
+	var merged Policy
+	for i, p := range policies {
+		merged.MaxAdded = strictestLimit(merged.MaxAdded, p.MaxAdded)
+		merged.MaxRemoved = strictestLimit(merged.MaxRemoved, p.MaxRemoved)
+		merged.MaxChanged = strictestLimit(merged.MaxChanged, p.MaxChanged)
+		merged.MaxDepth = strictestLimit(merged.MaxDepth, p.MaxDepth)
+		merged.DeepDepThreshold = strictestLimit(merged.DeepDepThreshold, p.DeepDepThreshold)
+
+		merged.DenyLicenses = union(merged.DenyLicenses, p.DenyLicenses)
+		if i == 0 {
+			merged.IgnorePackages = p.IgnorePackages
+			merged.AllowIntegrityDrift = p.AllowIntegrityDrift
+		} else {
+			merged.IgnorePackages = intersect(merged.IgnorePackages, p.IgnorePackages)
+			merged.AllowIntegrityDrift = intersect(merged.AllowIntegrityDrift, p.AllowIntegrityDrift)
+		}
+
+		for key, versions := range p.DenyVersions {
+			if merged.DenyVersions == nil {
+				merged.DenyVersions = make(map[string][]string)
+			}
+			merged.DenyVersions[key] = union(merged.DenyVersions[key], versions)
+		}
+
+		for signal, w := range p.RiskWeights {
+			if merged.RiskWeights == nil {
+				merged.RiskWeights = make(map[string]int)
+			}
+			if prev, ok := merged.RiskWeights[signal]; !ok || w > prev {
+				merged.RiskWeights[signal] = w
+			}
+		}
+
+		merged.RequireLicenses = merged.RequireLicenses || p.RequireLicenses
+		merged.DenyDuplicates = merged.DenyDuplicates || p.DenyDuplicates
+		merged.DenyIntegrityDrift = merged.DenyIntegrityDrift || p.DenyIntegrityDrift
+		merged.DenyWeakHashes = merged.DenyWeakHashes || p.DenyWeakHashes
+		merged.DenyNewSuppliers = merged.DenyNewSuppliers || p.DenyNewSuppliers
+		merged.WarnSupplierChange = merged.WarnSupplierChange || p.WarnSupplierChange
+		merged.WarnNewTransitive = merged.WarnNewTransitive || p.WarnNewTransitive
+		merged.WarnVersionJump = merged.WarnVersionJump || p.WarnVersionJump
+
+		// inline waivers loosen every rule, so all inputs must allow them
+		merged.AllowInlineWaivers = p.AllowInlineWaivers && (i == 0 || merged.AllowInlineWaivers)
+	}
+	return merged
+}
+
+// strictestLimit picks the lower limit; 0 means unlimited.
+func strictestLimit(a, b int) int {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// union appends the items of b missing from a, keeping first-seen order.
+func union(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, s := range a {
+		seen[s] = true
+	}
+	for _, s := range b {
+		if !seen[s] {
+			seen[s] = true
+			a = append(a, s)
+		}
+	}
+	return a
+}
+
+// intersect keeps the items of a that are also in b, in a's order.
+func intersect(a, b []string) []string {
+	var out []string
+	for _, s := range a {
+		if slices.Contains(b, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
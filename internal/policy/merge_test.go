@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	t.Run("limits take the strictest non-zero value", func(t *testing.T) {
+		tests := []struct {
+			name string
+			a, b int
+			want int
+		}{
+			{"both unset", 0, 0, 0},
+			{"base only", 10, 0, 10},
+			{"override only", 0, 5, 5},
+			{"override stricter", 10, 5, 5},
+			{"base stricter", 3, 5, 3},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := Merge(
+					Policy{MaxAdded: tt.a, MaxDepth: tt.a, DeepDepThreshold: tt.a},
+					Policy{MaxAdded: tt.b, MaxDepth: tt.b, DeepDepThreshold: tt.b},
+				)
+				if got.MaxAdded != tt.want || got.MaxDepth != tt.want || got.DeepDepThreshold != tt.want {
+					t.Errorf("MaxAdded=%d MaxDepth=%d DeepDepThreshold=%d, want %d", got.MaxAdded, got.MaxDepth, got.DeepDepThreshold, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("license lists are unioned", func(t *testing.T) {
+		got := Merge(
+			Policy{DenyLicenses: []string{"GPL-3.0", "AGPL-3.0"}},
+			Policy{DenyLicenses: []string{"AGPL-3.0", "SSPL-1.0"}},
+		)
+		want := []string{"GPL-3.0", "AGPL-3.0", "SSPL-1.0"}
+		if !reflect.DeepEqual(got.DenyLicenses, want) {
+			t.Errorf("DenyLicenses = %v, want %v", got.DenyLicenses, want)
+		}
+	})
+
+	t.Run("boolean rules are OR'd", func(t *testing.T) {
+		got := Merge(Policy{DenyIntegrityDrift: true}, Policy{WarnSupplierChange: true})
+		if !got.DenyIntegrityDrift || !got.WarnSupplierChange || got.DenyDuplicates {
+			t.Errorf("unexpected merged flags: %+v", got)
+		}
+	})
+
+	t.Run("ignored packages are intersected", func(t *testing.T) {
+		got := Merge(
+			Policy{IgnorePackages: []string{"internal-*", "pkg:golang", "lodash"}},
+			Policy{IgnorePackages: []string{"lodash", "internal-*"}},
+		)
+		want := []string{"internal-*", "lodash"}
+		if !reflect.DeepEqual(got.IgnorePackages, want) {
+			t.Errorf("IgnorePackages = %v, want %v", got.IgnorePackages, want)
+		}
+		if got := Merge(Policy{IgnorePackages: []string{"lodash"}}, Policy{}); len(got.IgnorePackages) != 0 {
+			t.Errorf("expected a policy without ignores to ignore nothing, got %v", got.IgnorePackages)
+		}
+	})
+
+	t.Run("integrity drift allowlists are intersected", func(t *testing.T) {
+		got := Merge(
+			Policy{AllowIntegrityDrift: []string{"openssl", "pkg:golang/github.com/acme/*"}},
+			Policy{AllowIntegrityDrift: []string{"openssl"}},
+			Policy{AllowIntegrityDrift: []string{"zlib", "openssl"}},
+		)
+		want := []string{"openssl"}
+		if !reflect.DeepEqual(got.AllowIntegrityDrift, want) {
+			t.Errorf("AllowIntegrityDrift = %v, want %v", got.AllowIntegrityDrift, want)
+		}
+	})
+
+	t.Run("inline waivers need every policy to allow them", func(t *testing.T) {
+		if got := Merge(Policy{AllowInlineWaivers: true}, Policy{}); got.AllowInlineWaivers {
+			t.Error("expected inline waivers to be disallowed")
+		}
+		if got := Merge(Policy{AllowInlineWaivers: true}, Policy{AllowInlineWaivers: true}); !got.AllowInlineWaivers {
+			t.Error("expected inline waivers to be allowed")
+		}
+	})
+
+	t.Run("risk weights take the largest value", func(t *testing.T) {
+		got := Merge(
+			Policy{RiskWeights: map[string]int{"new_supplier": 40, "missing_hashes": 0}},
+			Policy{RiskWeights: map[string]int{"new_supplier": 20, "missing_license": 30}},
+		)
+		want := map[string]int{"new_supplier": 40, "missing_hashes": 0, "missing_license": 30}
+		if !reflect.DeepEqual(got.RiskWeights, want) {
+			t.Errorf("RiskWeights = %v, want %v", got.RiskWeights, want)
+		}
+	})
+
+	t.Run("denied versions are unioned per package", func(t *testing.T) {
+		got := Merge(
+			Policy{DenyVersions: map[string][]string{"lodash": {"4.17.20"}}},
+			Policy{DenyVersions: map[string][]string{"lodash": {"4.17.20", "4.17.19"}, "pkg:npm/colors": {"1.4.1"}}},
+		)
+		want := map[string][]string{"lodash": {"4.17.20", "4.17.19"}, "pkg:npm/colors": {"1.4.1"}}
+		if !reflect.DeepEqual(got.DenyVersions, want) {
+			t.Errorf("DenyVersions = %v, want %v", got.DenyVersions, want)
+		}
+	})
+
+	t.Run("single policy is unchanged", func(t *testing.T) {
+		p := Policy{MaxAdded: 5, DenyLicenses: []string{"GPL-3.0"}, RequireLicenses: true}
+		if got := Merge(p); !reflect.DeepEqual(got, p) {
+			t.Errorf("Merge(p) = %+v, want %+v", got, p)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
 )
 
 // Policy defines SBOM diff rules.
@@ -18,16 +19,35 @@ type Policy struct {
 	DenyLicenses    []string `json:"deny_licenses,omitempty"`
 	RequireLicenses bool     `json:"require_licenses,omitempty"`
 
+	// Banned versions, keyed by package name or versionless PURL
+	DenyVersions map[string][]string `json:"deny_versions,omitempty"`
+
 	// Duplicate detection
 	DenyDuplicates bool `json:"deny_duplicates,omitempty"`
 
 	// Integrity/Security rules
 	DenyIntegrityDrift bool `json:"deny_integrity_drift,omitempty"` // Fail if hash changed without version
 	MaxDepth           int  `json:"max_depth,omitempty"`            // Fail if new transitive deps at depth >= N
+	DeepDepThreshold   int  `json:"deep_dep_threshold,omitempty"`   // Report new deps at depth >= N as risky (default 3)
+	DenyWeakHashes     bool `json:"deny_weak_hashes,omitempty"`     // Fail if a component is only hashed with MD5/SHA-1
+	DenyNewSuppliers   bool `json:"deny_new_suppliers,omitempty"`   // Fail if an added component's supplier is not in the "before" SBOM
+
+	// Components whose integrity drift is accepted (same patterns as IgnorePackages)
+	AllowIntegrityDrift []string `json:"allow_integrity_drift,omitempty"`
 
 	// Warning rules - these produce warnings, not failures
 	WarnSupplierChange bool `json:"warn_supplier_change,omitempty"` // Warn if supplier/author changed
 	WarnNewTransitive  bool `json:"warn_new_transitive,omitempty"`  // Warn on any new transitive deps
+	WarnVersionJump    bool `json:"warn_version_jump,omitempty"`    // Warn on suspicious major version leaps
+
+	// Overrides of analysis.DefaultRiskWeights, keyed by risk signal
+	RiskWeights map[string]int `json:"risk_weights,omitempty"`
+
+	// Components to leave out of rule evaluation (name globs or PURL types)
+	IgnorePackages []string `json:"ignore_packages,omitempty"`
+
+	// Honor sbomlyze:waive properties in the SBOMs under test
+	AllowInlineWaivers bool `json:"allow_inline_waivers,omitempty"`
 }
 
 type Severity string
@@ -50,13 +70,39 @@ func Load(data []byte) (Policy, error) {
 	if err := json.Unmarshal(data, &policy); err != nil {
 		return Policy{}, err
 	}
+	defaults := analysis.DefaultRiskWeights()
+	for signal := range policy.RiskWeights {
+		if _, ok := defaults[signal]; !ok {
+			return Policy{}, fmt.Errorf("risk_weights: unknown signal %q", signal)
+		}
+	}
 	return policy, nil
 }
 
+// EvalContext carries data about the inputs that the diff itself does not.
+type EvalContext struct {
+	// BeforeSuppliers is the supplier set of the "before" SBOM, as built by
+	// SupplierSet. deny_new_suppliers is skipped when it is nil.
+	BeforeSuppliers map[string]bool
+}
+
+// SupplierSet returns the normalized suppliers of comps; never nil.
+func SupplierSet(comps []sbom.Component) map[string]bool {
+	return analysis.SupplierSet(comps)
+}
+
 // Evaluate checks a diff against policy rules.
 func Evaluate(policy Policy, result analysis.DiffResult) []Violation {
+	return EvaluateWithContext(policy, result, EvalContext{})
+}
+
+// EvaluateWithContext is Evaluate with the input data that rules such as
+// deny_new_suppliers need.
+func EvaluateWithContext(policy Policy, result analysis.DiffResult, ctx EvalContext) []Violation {
 	var violations []Violation
 
+	result = policy.withoutIgnored(result)
+
 	if policy.MaxAdded > 0 && len(result.Added) > policy.MaxAdded {
 		violations = append(violations, Violation{
 			Rule:     "max_added",
@@ -88,6 +134,9 @@ func Evaluate(policy Policy, result analysis.DiffResult) []Violation {
 		}
 
 		for _, comp := range result.Added {
+			if policy.waived(comp, "deny_licenses") {
+				continue
+			}
 			for _, lic := range comp.Licenses {
 				if denySet[lic] {
 					violations = append(violations, Violation{
@@ -100,9 +149,30 @@ func Evaluate(policy Policy, result analysis.DiffResult) []Violation {
 		}
 	}
 
+	if len(policy.DenyVersions) > 0 {
+		check := func(comp sbom.Component) {
+			if policy.waived(comp, "deny_versions") {
+				return
+			}
+			if pin, version, ok := deniedVersion(policy.DenyVersions, comp); ok {
+				violations = append(violations, Violation{
+					Rule:     "deny_versions",
+					Message:  fmt.Sprintf("%s: denied version %s (pin %s@%s)", comp.Name, version, pin, version),
+					Severity: SeverityError,
+				})
+			}
+		}
+		for _, comp := range result.Added {
+			check(comp)
+		}
+		for _, changed := range result.Changed {
+			check(changed.After)
+		}
+	}
+
 	if policy.RequireLicenses {
 		for _, comp := range result.Added {
-			if len(comp.Licenses) == 0 {
+			if len(comp.Licenses) == 0 && !policy.waived(comp, "require_licenses") {
 				violations = append(violations, Violation{
 					Rule:     "require_licenses",
 					Message:  fmt.Sprintf("%s: no license", comp.Name),
@@ -125,7 +195,8 @@ func Evaluate(policy Policy, result analysis.DiffResult) []Violation {
 	if policy.DenyIntegrityDrift && result.DriftSummary != nil {
 		if result.DriftSummary.IntegrityDrift > 0 {
 			for _, changed := range result.Changed {
-				if changed.Drift != nil && changed.Drift.Type == analysis.DriftTypeIntegrity {
+				if changed.Drift != nil && changed.Drift.Type == analysis.DriftTypeIntegrity &&
+					!MatchesAny(policy.AllowIntegrityDrift, changed.After) && !policy.waived(changed.After, "deny_integrity_drift") {
 					violations = append(violations, Violation{
 						Rule:     "deny_integrity_drift",
 						Message:  fmt.Sprintf("%s: hash changed without version change", changed.Name),
@@ -152,10 +223,43 @@ func Evaluate(policy Policy, result analysis.DiffResult) []Violation {
 		}
 	}
 
+	if policy.DenyWeakHashes {
+		for _, comp := range result.Added {
+			if analysis.HasOnlyWeakHashes(comp.Hashes) && !policy.waived(comp, "deny_weak_hashes") {
+				violations = append(violations, Violation{
+					Rule:     "deny_weak_hashes",
+					Message:  fmt.Sprintf("%s: only weak hashes (MD5/SHA-1)", comp.Name),
+					Severity: SeverityError,
+				})
+			}
+		}
+		for _, changed := range result.Changed {
+			if analysis.HasOnlyWeakHashes(changed.After.Hashes) && !analysis.HasOnlyWeakHashes(changed.Before.Hashes) &&
+				!policy.waived(changed.After, "deny_weak_hashes") {
+				violations = append(violations, Violation{
+					Rule:     "deny_weak_hashes",
+					Message:  fmt.Sprintf("%s: now only weak hashes (MD5/SHA-1)", changed.Name),
+					Severity: SeverityError,
+				})
+			}
+		}
+	}
+
+	if policy.DenyNewSuppliers && ctx.BeforeSuppliers != nil {
+		for _, comp := range result.Added {
+			if s := sbom.NormalizeSupplier(comp.Supplier); s != "" && !ctx.BeforeSuppliers[s] && !policy.waived(comp, "deny_new_suppliers") {
+				violations = append(violations, Violation{
+					Rule:     "deny_new_suppliers",
+					Message:  fmt.Sprintf("%s: new supplier %q", comp.Name, comp.Supplier),
+					Severity: SeverityError,
+				})
+			}
+		}
+	}
+
 	if policy.WarnSupplierChange {
 		for _, changed := range result.Changed {
-			if changed.Before.Supplier != changed.After.Supplier &&
-				(changed.Before.Supplier != "" || changed.After.Supplier != "") {
+			if sbom.SupplierChanged(changed.Before.Supplier, changed.After.Supplier) && !policy.waived(changed.After, "warn_supplier_change") {
 				violations = append(violations, Violation{
 					Rule:     "warn_supplier_change",
 					Message:  fmt.Sprintf("%s: supplier %q -> %q", changed.Name, changed.Before.Supplier, changed.After.Supplier),
@@ -175,6 +279,18 @@ func Evaluate(policy Policy, result analysis.DiffResult) []Violation {
 		}
 	}
 
+	if policy.WarnVersionJump {
+		for _, changed := range result.Changed {
+			if changed.Drift != nil && changed.Drift.SuspiciousJump && !policy.waived(changed.After, "warn_version_jump") {
+				violations = append(violations, Violation{
+					Rule:     "warn_version_jump",
+					Message:  fmt.Sprintf("%s: suspicious version jump %s -> %s", changed.Name, changed.Before.Version, changed.After.Version),
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	}
+
 	return violations
 }
 
@@ -186,3 +302,39 @@ func HasErrors(violations []Violation) bool {
 	}
 	return false
 }
+
+// ParseSeverity parses a --min-severity value, "error" or "warning".
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(s); sev {
+	case SeverityError, SeverityWarning:
+		return sev, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (want error or warning)", s)
+}
+
+// AtLeast returns the violations of severity sev or higher. Errors are
+// always kept, so HasErrors gives the same answer on the result.
+func AtLeast(violations []Violation, sev Severity) []Violation {
+	if sev != SeverityError {
+		return violations
+	}
+	var out []Violation
+	for _, v := range violations {
+		if v.Severity == SeverityError {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// LicenseViolations returns the violations of the license rules,
+// deny_licenses and require_licenses.
+func LicenseViolations(violations []Violation) []Violation {
+	var out []Violation
+	for _, v := range violations {
+		if v.Rule == "deny_licenses" || v.Rule == "require_licenses" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
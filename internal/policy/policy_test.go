@@ -1,6 +1,8 @@
 package policy
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/rezmoss/sbomlyze/internal/analysis"
@@ -42,6 +44,23 @@ func TestLoadPolicy(t *testing.T) {
 			t.Error("expected error for invalid JSON")
 		}
 	})
+
+	t.Run("loads risk weights", func(t *testing.T) {
+		policy, err := Load([]byte(`{"risk_weights": {"missing_hashes": 0, "new_supplier": 40}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if policy.RiskWeights["new_supplier"] != 40 || len(policy.RiskWeights) != 2 {
+			t.Errorf("unexpected RiskWeights %v", policy.RiskWeights)
+		}
+	})
+
+	t.Run("returns error for unknown risk signal", func(t *testing.T) {
+		_, err := Load([]byte(`{"risk_weights": {"missing_hash": 10}}`))
+		if err == nil || !strings.Contains(err.Error(), `unknown signal "missing_hash"`) {
+			t.Errorf("expected unknown signal error, got %v", err)
+		}
+	})
 }
 
 func TestEvaluatePolicy(t *testing.T) {
@@ -317,6 +336,44 @@ func TestWarnSupplierChange(t *testing.T) {
 		}
 	})
 
+	t.Run("no warning for case or spacing differences", func(t *testing.T) {
+		policy := Policy{WarnSupplierChange: true}
+		result := analysis.DiffResult{
+			Changed: []analysis.ChangedComponent{
+				{
+					Name:   "pkg",
+					Before: sbom.Component{Version: "1.0", Supplier: "Acme Inc"},
+					After:  sbom.Component{Version: "1.1", Supplier: "ACME  INC"},
+				},
+			},
+		}
+
+		violations := Evaluate(policy, result)
+
+		if len(violations) != 0 {
+			t.Errorf("expected no violations, got %+v", violations)
+		}
+	})
+
+	t.Run("no warning when only one side has a supplier", func(t *testing.T) {
+		policy := Policy{WarnSupplierChange: true}
+		result := analysis.DiffResult{
+			Changed: []analysis.ChangedComponent{
+				{
+					Name:   "pkg",
+					Before: sbom.Component{Version: "1.0"},
+					After:  sbom.Component{Version: "1.1", Supplier: "Acme Inc"},
+				},
+			},
+		}
+
+		violations := Evaluate(policy, result)
+
+		if len(violations) != 0 {
+			t.Errorf("expected no violations, got %+v", violations)
+		}
+	})
+
 	t.Run("no warning when supplier unchanged", func(t *testing.T) {
 		policy := Policy{WarnSupplierChange: true}
 		result := analysis.DiffResult{
@@ -359,6 +416,39 @@ func TestWarnNewTransitive(t *testing.T) {
 	})
 }
 
+func TestWarnVersionJump(t *testing.T) {
+	policy := Policy{WarnVersionJump: true}
+	result := analysis.DiffResult{
+		Changed: []analysis.ChangedComponent{
+			{
+				Name:   "internal-lib",
+				Before: sbom.Component{Version: "1.2.0"},
+				After:  sbom.Component{Version: "99.0.0"},
+				Drift:  &analysis.DriftInfo{Type: analysis.DriftTypeVersion, SuspiciousJump: true},
+			},
+			{
+				Name:   "lodash",
+				Before: sbom.Component{Version: "4.17.20"},
+				After:  sbom.Component{Version: "4.17.21"},
+				Drift:  &analysis.DriftInfo{Type: analysis.DriftTypeVersion},
+			},
+		},
+	}
+
+	violations := Evaluate(policy, result)
+
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %+v", violations)
+	}
+	v := violations[0]
+	if v.Rule != "warn_version_jump" || v.Severity != SeverityWarning || !strings.Contains(v.Message, "1.2.0 -> 99.0.0") {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+	if len(Evaluate(Policy{}, result)) != 0 {
+		t.Error("expected no violations with the rule off")
+	}
+}
+
 func TestHasErrors(t *testing.T) {
 	t.Run("returns true when errors present", func(t *testing.T) {
 		violations := []Violation{
@@ -389,3 +479,402 @@ func TestHasErrors(t *testing.T) {
 	})
 }
 
+func TestAtLeast(t *testing.T) {
+	violations := []Violation{
+		{Rule: "warn_version_jump", Severity: SeverityWarning},
+		{Rule: "deny_licenses", Severity: SeverityError},
+		{Rule: "warn_supplier_change", Severity: SeverityWarning},
+	}
+
+	tests := []struct {
+		name string
+		min  string
+		want []string
+	}{
+		{"warning keeps all", "warning", []string{"warn_version_jump", "deny_licenses", "warn_supplier_change"}},
+		{"error hides warnings", "error", []string{"deny_licenses"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sev, err := ParseSeverity(tt.min)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, v := range AtLeast(violations, sev) {
+				got = append(got, v.Rule)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AtLeast(%s) = %v, want %v", tt.min, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("rejects unknown severity", func(t *testing.T) {
+		if _, err := ParseSeverity("info"); err == nil {
+			t.Error("expected an error for an unknown severity")
+		}
+	})
+}
+
+func TestDenyWeakHashes(t *testing.T) {
+	policy := Policy{DenyWeakHashes: true}
+
+	t.Run("fails on added component with only weak hashes", func(t *testing.T) {
+		result := analysis.DiffResult{
+			Added: []sbom.Component{
+				{Name: "md5-only", Hashes: map[string]string{"MD5": "aa"}},
+				{Name: "sha1-only", Hashes: map[string]string{"SHA-1": "bb"}},
+				{Name: "strong", Hashes: map[string]string{"SHA-1": "cc", "SHA-256": "dd"}},
+				{Name: "no-hashes"},
+			},
+		}
+
+		violations := Evaluate(policy, result)
+
+		if len(violations) != 2 {
+			t.Fatalf("expected 2 violations, got %d: %v", len(violations), violations)
+		}
+		for _, v := range violations {
+			if v.Rule != "deny_weak_hashes" || v.Severity != SeverityError {
+				t.Errorf("unexpected violation %+v", v)
+			}
+		}
+	})
+
+	t.Run("fails when changed component drops its strong hash", func(t *testing.T) {
+		result := analysis.DiffResult{
+			Changed: []analysis.ChangedComponent{
+				{
+					Name:   "downgraded",
+					Before: sbom.Component{Hashes: map[string]string{"SHA-256": "aa"}},
+					After:  sbom.Component{Hashes: map[string]string{"MD5": "bb"}},
+				},
+				{
+					Name:   "already-weak",
+					Before: sbom.Component{Hashes: map[string]string{"MD5": "aa"}},
+					After:  sbom.Component{Hashes: map[string]string{"MD5": "bb"}},
+				},
+			},
+		}
+
+		violations := Evaluate(policy, result)
+
+		if len(violations) != 1 {
+			t.Fatalf("expected 1 violation, got %d: %v", len(violations), violations)
+		}
+	})
+}
+
+func TestIgnorePackages(t *testing.T) {
+	result := analysis.DiffResult{
+		Added: []sbom.Component{
+			{Name: "gpl-lib", Version: "1.0", Licenses: []string{"GPL-3.0"}},
+			{Name: "internal-tool", Version: "2.0", Licenses: []string{"GPL-3.0"}},
+			{Name: "dev-helper", PURL: "pkg:npm/dev-helper@1.0.0", Licenses: []string{"GPL-3.0"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		ignore []string
+		want   int
+	}{
+		{"no ignore list", nil, 3},
+		{"exact name", []string{"gpl-lib"}, 2},
+		{"name glob", []string{"internal-*"}, 2},
+		{"name match is case-insensitive", []string{"GPL-LIB"}, 2},
+		{"purl type", []string{"pkg:npm"}, 2},
+		{"purl glob", []string{"pkg:npm/dev-*"}, 2},
+		{"purl type does not match name", []string{"pkg:pypi"}, 3},
+		{"all ignored", []string{"gpl-lib", "internal-*", "pkg:npm"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := Policy{DenyLicenses: []string{"GPL-3.0"}, IgnorePackages: tt.ignore}
+			violations := Evaluate(policy, result)
+			if len(violations) != tt.want {
+				t.Errorf("expected %d deny_licenses violations, got %d: %v", tt.want, len(violations), violations)
+			}
+		})
+	}
+
+	t.Run("ignored changed component skips integrity drift", func(t *testing.T) {
+		result := analysis.DiffResult{
+			Changed: []analysis.ChangedComponent{
+				{Name: "vendored", Before: sbom.Component{Name: "vendored"}, After: sbom.Component{Name: "vendored"},
+					Drift: &analysis.DriftInfo{Type: analysis.DriftTypeIntegrity}},
+			},
+			DriftSummary: &analysis.DriftSummary{IntegrityDrift: 1},
+		}
+		policy := Policy{DenyIntegrityDrift: true, IgnorePackages: []string{"vendored"}}
+		if violations := Evaluate(policy, result); len(violations) != 0 {
+			t.Errorf("expected no violations, got %v", violations)
+		}
+	})
+
+	t.Run("does not modify the diff", func(t *testing.T) {
+		Evaluate(Policy{IgnorePackages: []string{"*"}}, result)
+		if len(result.Added) != 3 {
+			t.Errorf("expected diff to keep 3 added, got %d", len(result.Added))
+		}
+	})
+}
+
+func TestDenyVersions(t *testing.T) {
+	lodash := func(version, purl string) sbom.Component {
+		return sbom.Component{Name: "lodash", Version: version, PURL: purl}
+	}
+	pins := map[string][]string{
+		"lodash":         {"4.17.20"},
+		"pkg:npm/colors": {"1.4.1", "1.4.2"},
+	}
+
+	tests := []struct {
+		name    string
+		result  analysis.DiffResult
+		wantMsg string // empty for no violation
+	}{
+		{"banned exact version added", analysis.DiffResult{Added: []sbom.Component{lodash("4.17.20", "pkg:npm/lodash@4.17.20")}},
+			"lodash: denied version 4.17.20 (pin lodash@4.17.20)"},
+		{"adjacent version allowed", analysis.DiffResult{Added: []sbom.Component{lodash("4.17.21", "pkg:npm/lodash@4.17.21")}}, ""},
+		{"name match ignores case", analysis.DiffResult{Added: []sbom.Component{{Name: "LoDash", Version: "4.17.20"}}},
+			"LoDash: denied version 4.17.20 (pin lodash@4.17.20)"},
+		{"purl key", analysis.DiffResult{Added: []sbom.Component{{Name: "colors", Version: "1.4.1", PURL: "pkg:npm/colors@1.4.1"}}},
+			"colors: denied version 1.4.1 (pin pkg:npm/colors@1.4.1)"},
+		{"purl key needs same type", analysis.DiffResult{Added: []sbom.Component{{Name: "colors", Version: "1.4.1", PURL: "pkg:pypi/colors@1.4.1"}}}, ""},
+		{"version from purl", analysis.DiffResult{Added: []sbom.Component{lodash("", "pkg:npm/lodash@4.17.20")}},
+			"lodash: denied version 4.17.20 (pin lodash@4.17.20)"},
+		{"changed to banned version", analysis.DiffResult{Changed: []analysis.ChangedComponent{
+			{Name: "colors", Before: sbom.Component{Name: "colors", Version: "1.4.0", PURL: "pkg:npm/colors@1.4.0"},
+				After: sbom.Component{Name: "colors", Version: "1.4.2", PURL: "pkg:npm/colors@1.4.2"}},
+		}}, "colors: denied version 1.4.2 (pin pkg:npm/colors@1.4.2)"},
+		{"changed away from banned version", analysis.DiffResult{Changed: []analysis.ChangedComponent{
+			{Name: "lodash", Before: lodash("4.17.20", "pkg:npm/lodash@4.17.20"), After: lodash("4.17.21", "pkg:npm/lodash@4.17.21")},
+		}}, ""},
+		{"removed banned version", analysis.DiffResult{Removed: []sbom.Component{lodash("4.17.20", "pkg:npm/lodash@4.17.20")}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := Evaluate(Policy{DenyVersions: pins}, tt.result)
+			if tt.wantMsg == "" {
+				if len(violations) != 0 {
+					t.Errorf("expected no violations, got %v", violations)
+				}
+				return
+			}
+			if len(violations) != 1 || violations[0].Rule != "deny_versions" || violations[0].Severity != SeverityError || violations[0].Message != tt.wantMsg {
+				t.Errorf("expected one deny_versions error %q, got %v", tt.wantMsg, violations)
+			}
+		})
+	}
+}
+
+func TestFilterPackages(t *testing.T) {
+	comps := []sbom.Component{
+		{Name: "lodash", PURL: "pkg:npm/lodash@4.17.21"},
+		{Name: "left-pad", PURL: "pkg:npm/left-pad@1.3.0"},
+		{Name: "requests", PURL: "pkg:pypi/requests@2.31.0"},
+		{Name: "zlib"},
+	}
+
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             []string
+	}{
+		{"no filters", nil, nil, []string{"lodash", "left-pad", "requests", "zlib"}},
+		{"include type", []string{"pkg:npm"}, nil, []string{"lodash", "left-pad"}},
+		{"include name glob", []string{"l*"}, nil, []string{"lodash", "left-pad"}},
+		{"exclude", nil, []string{"pkg:npm/left-*", "zlib"}, []string{"lodash", "requests"}},
+		{"exclude wins", []string{"pkg:npm"}, []string{"lodash"}, []string{"left-pad"}},
+		{"nothing matches", []string{"pkg:gem"}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range FilterPackages(comps, tt.include, tt.exclude) {
+				got = append(got, c.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FilterPackages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowIntegrityDrift(t *testing.T) {
+	drifted := func(name, purl string) analysis.ChangedComponent {
+		c := sbom.Component{Name: name, PURL: purl}
+		return analysis.ChangedComponent{Name: name, Before: c, After: c,
+			Drift: &analysis.DriftInfo{Type: analysis.DriftTypeIntegrity}}
+	}
+	result := analysis.DiffResult{
+		Changed: []analysis.ChangedComponent{
+			drifted("openssl", "pkg:apk/alpine/openssl@3.1.0"),
+			drifted("zlib", "pkg:apk/alpine/zlib@1.3"),
+		},
+		DriftSummary: &analysis.DriftSummary{IntegrityDrift: 2},
+	}
+
+	tests := []struct {
+		name  string
+		allow []string
+		want  []string
+	}{
+		{"no allowlist", nil, []string{"openssl", "zlib"}},
+		{"name allowlisted", []string{"openssl"}, []string{"zlib"}},
+		{"purl glob allowlisted", []string{"pkg:apk/alpine/zl*"}, []string{"openssl"}},
+		{"all allowlisted", []string{"pkg:apk"}, nil},
+		{"no match", []string{"curl"}, []string{"openssl", "zlib"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := Policy{DenyIntegrityDrift: true, AllowIntegrityDrift: tt.allow}
+			violations := Evaluate(policy, result)
+			if len(violations) != len(tt.want) {
+				t.Fatalf("expected %d violations, got %d: %v", len(tt.want), len(violations), violations)
+			}
+			for i, name := range tt.want {
+				if violations[i].Rule != "deny_integrity_drift" || !strings.HasPrefix(violations[i].Message, name+":") {
+					t.Errorf("violation %d = %+v, want deny_integrity_drift for %s", i, violations[i], name)
+				}
+			}
+		})
+	}
+}
+
+func TestDenyNewSuppliers(t *testing.T) {
+	before := []sbom.Component{
+		{Name: "lodash", Supplier: "OpenJS Foundation"},
+		{Name: "left-pad"},
+	}
+	result := analysis.DiffResult{
+		Added: []sbom.Component{
+			{Name: "express", Supplier: "openjs  foundation"},
+			{Name: "evil-pkg", Supplier: "Unknown Corp"},
+			{Name: "no-supplier"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		ctx    EvalContext
+		want   []string // violation messages
+	}{
+		{"new supplier flagged", Policy{DenyNewSuppliers: true}, EvalContext{BeforeSuppliers: SupplierSet(before)},
+			[]string{`evil-pkg: new supplier "Unknown Corp"`}},
+		{"no suppliers before", Policy{DenyNewSuppliers: true}, EvalContext{BeforeSuppliers: SupplierSet(nil)},
+			[]string{`express: new supplier "openjs  foundation"`, `evil-pkg: new supplier "Unknown Corp"`}},
+		{"ignored package", Policy{DenyNewSuppliers: true, IgnorePackages: []string{"evil-*"}}, EvalContext{BeforeSuppliers: SupplierSet(before)}, nil},
+		{"without before suppliers", Policy{DenyNewSuppliers: true}, EvalContext{}, nil},
+		{"rule off", Policy{}, EvalContext{BeforeSuppliers: SupplierSet(before)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := EvaluateWithContext(tt.policy, result, tt.ctx)
+			if len(violations) != len(tt.want) {
+				t.Fatalf("expected %d violations, got %+v", len(tt.want), violations)
+			}
+			for i, v := range violations {
+				if v.Rule != "deny_new_suppliers" || v.Severity != SeverityError || v.Message != tt.want[i] {
+					t.Errorf("violation %d = %+v, want deny_new_suppliers error %q", i, v, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestComponentWaivers(t *testing.T) {
+	waive := func(rules string) map[string]string {
+		return map[string]string{WaiveProperty: rules}
+	}
+	jumped := func(name string, props map[string]string) analysis.ChangedComponent {
+		return analysis.ChangedComponent{
+			Name:   name,
+			Before: sbom.Component{Name: name, Version: "1.0.0"},
+			After:  sbom.Component{Name: name, Version: "99.0.0", Properties: props},
+			Drift:  &analysis.DriftInfo{Type: analysis.DriftTypeVersion, SuspiciousJump: true},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		result analysis.DiffResult
+		want   []string // violation messages
+	}{
+		{
+			name:   "denied license waived",
+			policy: Policy{DenyLicenses: []string{"GPL-3.0-only"}, AllowInlineWaivers: true},
+			result: analysis.DiffResult{Added: []sbom.Component{
+				{Name: "readline", Licenses: []string{"GPL-3.0-only"}, Properties: waive("deny_licenses")},
+				{Name: "bash", Licenses: []string{"GPL-3.0-only"}},
+			}},
+			want: []string{"bash: denied license GPL-3.0-only"},
+		},
+		{
+			name:   "one of several rules waived",
+			policy: Policy{DenyLicenses: []string{"GPL-3.0-only"}, RequireLicenses: true, AllowInlineWaivers: true},
+			result: analysis.DiffResult{Added: []sbom.Component{
+				{Name: "readline", Licenses: []string{"GPL-3.0-only"}, Properties: waive("require_licenses, deny_licenses")},
+				{Name: "blob", Properties: waive("require_licenses")},
+				{Name: "unlicensed"},
+			}},
+			want: []string{"unlicensed: no license"},
+		},
+		{
+			name:   "waiver for another rule",
+			policy: Policy{DenyLicenses: []string{"GPL-3.0-only"}, AllowInlineWaivers: true},
+			result: analysis.DiffResult{Added: []sbom.Component{
+				{Name: "readline", Licenses: []string{"GPL-3.0-only"}, Properties: waive("require_licenses")},
+			}},
+			want: []string{"readline: denied license GPL-3.0-only"},
+		},
+		{
+			name:   "changed component waived on its new side",
+			policy: Policy{WarnVersionJump: true, AllowInlineWaivers: true},
+			result: analysis.DiffResult{Changed: []analysis.ChangedComponent{
+				jumped("waived-pkg", waive("warn_version_jump")),
+				jumped("evil-pkg", nil),
+			}},
+			want: []string{"evil-pkg: suspicious version jump 1.0.0 -> 99.0.0"},
+		},
+		{
+			name:   "waivers ignored unless the policy allows them",
+			policy: Policy{DenyLicenses: []string{"GPL-3.0-only"}},
+			result: analysis.DiffResult{Added: []sbom.Component{
+				{Name: "readline", Licenses: []string{"GPL-3.0-only"}, Properties: waive("deny_licenses")},
+			}},
+			want: []string{"readline: denied license GPL-3.0-only"},
+		},
+		{
+			name:   "count limits are not waivable",
+			policy: Policy{MaxAdded: 1, AllowInlineWaivers: true},
+			result: analysis.DiffResult{Added: []sbom.Component{
+				{Name: "a", Properties: waive("max_added")},
+				{Name: "b", Properties: waive("max_added")},
+			}},
+			want: []string{"added 2 > max 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := Evaluate(tt.policy, tt.result)
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
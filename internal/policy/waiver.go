@@ -0,0 +1,25 @@
+package policy
+
+import (
+	"strings"
+
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// WaiveProperty is the component property that waives policy rules for
+// that component, e.g. sbomlyze:waive=deny_licenses.
+const WaiveProperty = "sbomlyze:waive"
+
+// waived reports whether c's WaiveProperty lists rule and the policy allows
+// inline waivers. The value is a comma-separated list of rule names.
+func (p Policy) waived(c sbom.Component, rule string) bool {
+	if !p.AllowInlineWaivers {
+		return false
+	}
+	for _, r := range strings.Split(c.Properties[WaiveProperty], ",") {
+		if strings.TrimSpace(r) == rule {
+			return true
+		}
+	}
+	return false
+}
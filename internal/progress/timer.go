@@ -0,0 +1,40 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Timer prints the elapsed time of each phase to stderr. A disabled Timer
+// is a no-op.
+type Timer struct {
+	enabled bool
+	w       io.Writer
+	last    time.Time
+	start   time.Time
+}
+
+func NewTimer(enabled bool) *Timer {
+	now := time.Now()
+	return &Timer{enabled: enabled, w: os.Stderr, last: now, start: now}
+}
+
+// Phase reports the time since the previous phase (or the timer's start).
+func (t *Timer) Phase(name string) {
+	if !t.enabled {
+		return
+	}
+	now := time.Now()
+	fmt.Fprintf(t.w, "timing: %-9s %s\n", name, now.Sub(t.last).Round(time.Microsecond))
+	t.last = now
+}
+
+// Total reports the time since the timer's start.
+func (t *Timer) Total() {
+	if !t.enabled {
+		return
+	}
+	fmt.Fprintf(t.w, "timing: %-9s %s\n", "total", time.Since(t.start).Round(time.Microsecond))
+}
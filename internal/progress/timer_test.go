@@ -0,0 +1,40 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTimerPhases(t *testing.T) {
+	var buf bytes.Buffer
+	timer := NewTimer(true)
+	timer.w = &buf
+
+	timer.Phase("parse")
+	timer.Phase("diff")
+	timer.Total()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", buf.String())
+	}
+	for i, name := range []string{"parse", "diff", "total"} {
+		if !strings.HasPrefix(lines[i], "timing: "+name) {
+			t.Errorf("line %d = %q, want phase %s", i, lines[i], name)
+		}
+	}
+}
+
+func TestDisabledTimerIsNoop(t *testing.T) {
+	var buf bytes.Buffer
+	timer := NewTimer(false)
+	timer.w = &buf
+
+	timer.Phase("parse")
+	timer.Total()
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,84 @@
+package sbom
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// dsseEnvelope is a DSSE envelope as written by in-toto and cosign.
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+}
+
+// inTotoStatement is the in-toto statement carried in a DSSE payload.
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// IsAttestation detects a DSSE envelope (in-toto / cosign attestation).
+func IsAttestation(data []byte) bool {
+	keys := decodeTopLevelKeys(data)
+	if keys == nil {
+		return false
+	}
+	_, hasType := keys["payloadType"].(string)
+	_, hasPayload := keys["payload"].(string)
+	return hasType && hasPayload
+}
+
+// UnwrapAttestation decodes a DSSE envelope and returns the SBOM inside:
+// the predicate of an in-toto statement, or the payload itself when it is
+// already an SBOM. It fails if neither is a recognized SBOM format.
+func UnwrapAttestation(data []byte) ([]byte, error) {
+	var env dsseEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	return env.unwrap()
+}
+
+func (env dsseEnvelope) unwrap() ([]byte, error) {
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		// DSSE allows either base64 alphabet
+		if payload, err = base64.URLEncoding.DecodeString(env.Payload); err != nil {
+			return nil, fmt.Errorf("decode payload: %w", err)
+		}
+	}
+
+	inner := payload
+	predicateType := ""
+	var stmt inTotoStatement
+	if json.Unmarshal(payload, &stmt) == nil && len(stmt.Predicate) > 0 {
+		inner = stmt.Predicate
+		predicateType = stmt.PredicateType
+	}
+
+	if IsCycloneDX(inner) || IsSPDX(inner) || IsSyft(inner) {
+		return inner, nil
+	}
+	if predicateType != "" {
+		return nil, fmt.Errorf("attestation predicate %q is not a recognized SBOM", predicateType)
+	}
+	return nil, fmt.Errorf("attestation payload (%s) is not a recognized SBOM", env.PayloadType)
+}
+
+// parseAttestation parses the SBOM inside env.
+func parseAttestation(env dsseEnvelope, keepRaw bool) ([]Component, SBOMInfo, error) {
+	inner, err := env.unwrap()
+	if err != nil {
+		return nil, SBOMInfo{}, err
+	}
+	switch {
+	case IsCycloneDX(inner):
+		return parseCycloneDX(inner, keepRaw)
+	case IsSPDX(inner):
+		return parseSPDXBytes(inner, keepRaw)
+	default: // unwrap only returns recognized formats
+		return parseSyft(inner, keepRaw)
+	}
+}
@@ -0,0 +1,141 @@
+package sbom
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeEnvelope wraps payload in a DSSE envelope file.
+func writeEnvelope(t *testing.T, payload []byte) string {
+	t.Helper()
+	env, err := json.Marshal(map[string]any{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString(payload),
+		"signatures":  []any{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "envelope.json")
+	if err := os.WriteFile(path, env, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// statement wraps the SBOM at name in an in-toto statement.
+func statement(t *testing.T, name, predicateType string) []byte {
+	t.Helper()
+	sbomData, err := os.ReadFile(testdataPath(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]any{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": predicateType,
+		"subject":       []any{},
+		"predicate":     json.RawMessage(sbomData),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestIsAttestation(t *testing.T) {
+	data, err := os.ReadFile(testdataPath("attestation-cyclonedx.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsAttestation(data) {
+		t.Error("expected envelope to be detected")
+	}
+	for _, name := range []string{"cyclonedx-before.json", "spdx-sample.json", "syft-sample.json"} {
+		data, err := os.ReadFile(testdataPath(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if IsAttestation(data) {
+			t.Errorf("%s: expected plain SBOM not to be detected as attestation", name)
+		}
+	}
+}
+
+func TestParseFile_Attestation(t *testing.T) {
+	tests := []struct {
+		name string
+		path func(t *testing.T) string
+		want string // plain SBOM with the same components
+	}{
+		{"cyclonedx testdata envelope", func(t *testing.T) string {
+			return testdataPath("attestation-cyclonedx.json")
+		}, "cyclonedx-before.json"},
+		{"spdx predicate", func(t *testing.T) string {
+			return writeEnvelope(t, statement(t, "spdx-sample.json", "https://spdx.dev/Document"))
+		}, "spdx-sample.json"},
+		{"bare sbom payload", func(t *testing.T) string {
+			data, err := os.ReadFile(testdataPath("syft-sample.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return writeEnvelope(t, data)
+		}, "syft-sample.json"},
+	}
+
+	for _, streaming := range []bool{false, true} {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/streaming=%v", tt.name, streaming), func(t *testing.T) {
+				if streaming {
+					// envelopes at or above StreamThreshold are unwrapped too
+					withStreamThreshold(t, 0)
+				}
+				got, err := ParseFile(tt.path(t))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want, err := ParseFile(testdataPath(tt.want))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got) != len(want) || len(got) == 0 {
+					t.Fatalf("expected %d components, got %d", len(want), len(got))
+				}
+				for i := range want {
+					if got[i].Name != want[i].Name || got[i].Version != want[i].Version {
+						t.Errorf("component %d = %s@%s, want %s@%s", i, got[i].Name, got[i].Version, want[i].Name, want[i].Version)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestParseFile_AttestationNotSBOM(t *testing.T) {
+	provenance := []byte(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v0.2","subject":[],"predicate":{"builder":{"id":"ci"}}}`)
+
+	tests := []struct {
+		name    string
+		payload []byte
+		wantErr string
+	}{
+		{"provenance predicate", provenance, "https://slsa.dev/provenance/v0.2"},
+		{"non-json payload", []byte("hello"), "not a recognized SBOM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFile(writeEnvelope(t, tt.payload))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
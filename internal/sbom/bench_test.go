@@ -3,6 +3,8 @@ package sbom
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -99,6 +101,36 @@ func BenchmarkParseSyft(b *testing.B) {
 	}
 }
 
+// BenchmarkParseFile_LargeSyft compares whole-file and streaming parsing of a
+// large Syft document; watch B/op to see the memory difference.
+func BenchmarkParseFile_LargeSyft(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "large-syft.json")
+	data := generateSyntheticSyft(50000)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		b.Fatal(err)
+	}
+
+	defer func(old int64) { StreamThreshold = old }(StreamThreshold)
+	for _, mode := range []struct {
+		name      string
+		threshold int64
+	}{
+		{"whole", 1 << 62},
+		{"streaming", 0},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			StreamThreshold = mode.threshold
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ParseFileWithInfo(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkFormatDetection_LargeFile(b *testing.B) {
 	data := generateSyntheticCycloneDX(10000)
 	b.ReportAllocs()
@@ -6,8 +6,16 @@ import (
 	"github.com/rezmoss/sbomlyze/internal/identity"
 )
 
+// Document formats recorded in SBOMInfo.Format.
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+	FormatSyft      = "syft"
+)
+
 // SBOMInfo holds SBOM source metadata.
 type SBOMInfo struct {
+	Format             string         `json:"format,omitempty"` // FormatCycloneDX, FormatSPDX or FormatSyft
 	OSName             string         `json:"os_name,omitempty"`
 	OSVersion          string         `json:"os_version,omitempty"`
 	OSPrettyName       string         `json:"os_pretty_name,omitempty"`
@@ -21,11 +29,24 @@ type SBOMInfo struct {
 	SchemaVersion      string         `json:"schema_version,omitempty"`
 	SearchScope        string         `json:"search_scope,omitempty"`
 	FilesCount         int            `json:"files_count,omitempty"`
+	Timestamp          string         `json:"timestamp,omitempty"` // document creation time
+	Authors            []string       `json:"authors,omitempty"`
+
+	// ParseIssues are problems the parser worked around, e.g. dependency
+	// edges it dropped. Callers report them alongside Validate's issues.
+	ParseIssues []Issue `json:"-"`
+}
+
+// Reference is an external reference such as a security advisory URL.
+type Reference struct {
+	Type    string `json:"type"` // e.g. advisory, fix, url
+	Locator string `json:"locator"`
 }
 
 // Component is a normalized SBOM component.
 type Component struct {
 	ID           string            `json:"id"`
+	IDBasis      string            `json:"id_basis,omitempty"` // set by ExplainIDs
 	Name         string            `json:"name"`
 	Version      string            `json:"version"`
 	PURL         string            `json:"purl,omitempty"`
@@ -41,6 +62,8 @@ type Component struct {
 	FoundBy      string            `json:"foundBy,omitempty"`  // scanner
 	Type         string            `json:"type,omitempty"`     // pkg type
 	Locations    []string          `json:"locations,omitempty"` // file paths
+	References   []Reference       `json:"references,omitempty"` // SPDX security refs other than CPEs
+	Properties   map[string]string `json:"properties,omitempty"` // CycloneDX name/value properties
 	RawJSON      json.RawMessage   `json:"-"`                  // original JSON, excluded from output
 }
 
@@ -60,3 +83,11 @@ func (c Component) ToIdentity() identity.ComponentIdentity {
 func (c *Component) ComputeID() string {
 	return identity.ComputeID(c.ToIdentity())
 }
+
+// ExplainIDs records on each component which identity field its ID came
+// from. Run it after NormalizeComponents, which recomputes IDs.
+func ExplainIDs(comps []Component) {
+	for i := range comps {
+		comps[i].IDBasis = string(identity.ExplainID(comps[i].ToIdentity()))
+	}
+}
@@ -2,6 +2,7 @@ package sbom
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	cdx "github.com/CycloneDX/cyclonedx-go"
@@ -16,89 +17,282 @@ func ParseCycloneDX(data []byte) ([]Component, error) {
 
 // ParseCycloneDXWithInfo parses CycloneDX JSON with metadata.
 func ParseCycloneDXWithInfo(data []byte) ([]Component, SBOMInfo, error) {
+	return parseCycloneDX(data, true)
+}
+
+func parseCycloneDX(data []byte, keepRaw bool) ([]Component, SBOMInfo, error) {
 	var rawDoc struct {
 		Components []json.RawMessage `json:"components"`
 	}
-	_ = json.Unmarshal(data, &rawDoc) // Ignore error, rawDoc.Components may be nil
+	if keepRaw {
+		_ = json.Unmarshal(data, &rawDoc) // Ignore error, rawDoc.Components may be nil
+	}
 
 	var bom cdx.BOM
 	if err := json.Unmarshal(data, &bom); err != nil {
 		return nil, SBOMInfo{}, err
 	}
 
-	info := SBOMInfo{}
-	if bom.Metadata != nil {
-		if bom.Metadata.Component != nil {
-			mc := bom.Metadata.Component
-			switch mc.Type {
-			case cdx.ComponentTypeOS, cdx.ComponentTypeContainer:
-				info.OSName = mc.Name
-				info.OSVersion = mc.Version
-				info.SourceType = string(mc.Type)
-			case cdx.ComponentTypeApplication, cdx.ComponentTypeFile:
-				info.SourceName = mc.Name
-				info.SourceType = string(mc.Type)
+	info := cdxInfo(bom.Metadata)
+
+	var comps []Component
+	if bom.Components == nil {
+		return comps, info, nil
+	}
+
+	for i, c := range *bom.Components {
+		var raw json.RawMessage
+		if i < len(rawDoc.Components) {
+			raw = rawDoc.Components[i]
+		}
+		comps = append(comps, cdxComponents(c, raw)...)
+	}
+	if bom.Dependencies != nil && len(comps) > 0 {
+		info.ParseIssues = linkCycloneDXDependencies(comps, *bom.Dependencies, cdxOtherRefs(bom.Metadata, bom.Services))
+	}
+	return comps, info, nil
+}
+
+// cdxOtherRefs returns the bom-refs of elements that may appear in the
+// dependency graph but are not parsed as components: the metadata
+// component and services.
+func cdxOtherRefs(meta *cdx.Metadata, services *[]cdx.Service) map[string]bool {
+	refs := make(map[string]bool)
+	if meta != nil && meta.Component != nil && meta.Component.BOMRef != "" {
+		refs[meta.Component.BOMRef] = true
+	}
+	var walk func(svcs *[]cdx.Service)
+	walk = func(svcs *[]cdx.Service) {
+		if svcs == nil {
+			return
+		}
+		for _, s := range *svcs {
+			if s.BOMRef != "" {
+				refs[s.BOMRef] = true
 			}
+			walk(s.Services)
 		}
-		if bom.Metadata.Properties != nil {
-			for _, prop := range *bom.Metadata.Properties {
-				switch strings.ToLower(prop.Name) {
-				case "syft:distro:name", "distro:name", "os:name":
-					if info.OSName == "" {
-						info.OSName = prop.Value
-					}
-				case "syft:distro:version", "distro:version", "os:version":
-					if info.OSVersion == "" {
-						info.OSVersion = prop.Value
-					}
-				case "syft:image:tag", "image:tag":
-					if info.SourceName == "" {
-						info.SourceName = prop.Value
-					}
-				}
+	}
+	walk(services)
+	return refs
+}
+
+// linkCycloneDXDependencies adds the dependencies section to comps. Edges
+// are keyed by bom-ref, so each ref is translated to the component's
+// identity ID. Edges to or from the root or a service (other) are skipped;
+// edges naming an unknown bom-ref are dropped and returned as issues.
+func linkCycloneDXDependencies(comps []Component, deps []cdx.Dependency, other map[string]bool) []Issue {
+	refToIdx := make(map[string]int, len(comps))
+	for i, c := range comps {
+		if c.BOMRef == "" {
+			continue
+		}
+		if _, dup := refToIdx[c.BOMRef]; !dup {
+			refToIdx[c.BOMRef] = i
+		}
+	}
+
+	var issues []Issue
+	dangling := func(source, message string) {
+		issues = append(issues, Issue{
+			Code:      IssueDanglingDependency,
+			Component: source,
+			Field:     "dependencies",
+			Message:   message,
+		})
+	}
+
+	for _, d := range deps {
+		if d.Dependencies == nil || len(*d.Dependencies) == 0 || other[d.Ref] {
+			continue
+		}
+		parent, ok := refToIdx[d.Ref]
+		if !ok {
+			dangling("", fmt.Sprintf("dependencies of unknown bom-ref %q dropped", d.Ref))
+			continue
+		}
+		have := make(map[string]bool, len(comps[parent].Dependencies))
+		for _, id := range comps[parent].Dependencies {
+			have[id] = true
+		}
+		for _, ref := range *d.Dependencies {
+			if other[ref] {
+				continue
+			}
+			child, ok := refToIdx[ref]
+			if !ok {
+				label := componentLabel(comps[parent])
+				dangling(label, fmt.Sprintf("%s depends on unknown bom-ref %q; edge dropped", label, ref))
+				continue
+			}
+			if id := comps[child].ID; !have[id] {
+				have[id] = true
+				comps[parent].Dependencies = append(comps[parent].Dependencies, id)
 			}
 		}
 	}
+	return issues
+}
 
-	var comps []Component
-	if bom.Components == nil {
-		return comps, info, nil
+// cdxComponents flattens c and its nested components, parent first.
+// Each parent gets a dependency edge to its direct children.
+func cdxComponents(c cdx.Component, raw json.RawMessage) []Component {
+	out := []Component{cdxComponent(c, raw)}
+	if c.Components == nil {
+		return out
 	}
 
-	for i, c := range *bom.Components {
-		comp := Component{
-			Name:      c.Name,
-			Version:   c.Version,
-			Hashes:    make(map[string]string),
-			BOMRef:    c.BOMRef,
-			Namespace: c.Group,
-		}
-		if c.PackageURL != "" {
-			comp.PURL = c.PackageURL
-		}
-		if c.CPE != "" {
-			comp.CPEs = append(comp.CPEs, c.CPE)
-		}
-		if c.Licenses != nil {
-			for _, lic := range *c.Licenses {
-				if lic.License != nil && lic.License.ID != "" {
-					comp.Licenses = append(comp.Licenses, lic.License.ID)
+	var rawChildren []json.RawMessage
+	if raw != nil {
+		var rawComp struct {
+			Components []json.RawMessage `json:"components"`
+		}
+		_ = json.Unmarshal(raw, &rawComp)
+		rawChildren = rawComp.Components
+	}
+
+	for i, child := range *c.Components {
+		var childRaw json.RawMessage
+		if i < len(rawChildren) {
+			childRaw = rawChildren[i]
+		}
+		nested := cdxComponents(child, childRaw)
+		out[0].Dependencies = append(out[0].Dependencies, nested[0].ID)
+		out = append(out, nested...)
+	}
+	return out
+}
+
+// cdxInfo extracts SBOM metadata from a CycloneDX metadata block.
+func cdxInfo(meta *cdx.Metadata) SBOMInfo {
+	info := SBOMInfo{Format: FormatCycloneDX}
+	if meta == nil {
+		return info
+	}
+	info.Timestamp = meta.Timestamp
+	info.ToolName, info.ToolVersion = cdxTool(meta.Tools)
+	if meta.Authors != nil {
+		for _, a := range *meta.Authors {
+			name := a.Name
+			if name == "" {
+				name = a.Email
+			}
+			if name != "" {
+				info.Authors = append(info.Authors, name)
+			}
+		}
+	}
+	if meta.Component != nil {
+		mc := meta.Component
+		switch mc.Type {
+		case cdx.ComponentTypeOS, cdx.ComponentTypeContainer:
+			info.OSName = mc.Name
+			info.OSVersion = mc.Version
+			info.SourceType = string(mc.Type)
+		case cdx.ComponentTypeApplication, cdx.ComponentTypeFile:
+			info.SourceName = mc.Name
+			info.SourceType = string(mc.Type)
+		}
+	}
+	if meta.Properties != nil {
+		for _, prop := range *meta.Properties {
+			switch strings.ToLower(prop.Name) {
+			case "syft:distro:name", "distro:name", "os:name":
+				if info.OSName == "" {
+					info.OSName = prop.Value
+				}
+			case "syft:distro:version", "distro:version", "os:version":
+				if info.OSVersion == "" {
+					info.OSVersion = prop.Value
 				}
+			case "syft:image:tag", "image:tag":
+				if info.SourceName == "" {
+					info.SourceName = prop.Value
+				}
+			}
+		}
+	}
+	return info
+}
+
+// cdxComponent converts a CycloneDX component; raw is kept as RawJSON.
+func cdxComponent(c cdx.Component, raw json.RawMessage) Component {
+	comp := Component{
+		Name:      c.Name,
+		Version:   c.Version,
+		Hashes:    make(map[string]string),
+		BOMRef:    c.BOMRef,
+		Namespace: c.Group,
+		RawJSON:   raw,
+	}
+	if c.PackageURL != "" {
+		comp.PURL = c.PackageURL
+	}
+	if c.CPE != "" {
+		comp.CPEs = append(comp.CPEs, c.CPE)
+	}
+	if c.Licenses != nil {
+		for _, lic := range *c.Licenses {
+			if l := cdxLicense(lic); l != "" {
+				comp.Licenses = append(comp.Licenses, l)
 			}
 		}
-		if c.Hashes != nil {
-			for _, h := range *c.Hashes {
-				comp.Hashes[string(h.Algorithm)] = h.Value
+	}
+	if c.Hashes != nil {
+		for _, h := range *c.Hashes {
+			comp.Hashes[string(h.Algorithm)] = h.Value
+		}
+	}
+	if c.Supplier != nil && c.Supplier.Name != "" {
+		comp.Supplier = c.Supplier.Name
+	}
+	if c.Properties != nil && len(*c.Properties) > 0 {
+		// a name may repeat; its values are joined in document order
+		comp.Properties = make(map[string]string, len(*c.Properties))
+		for _, p := range *c.Properties {
+			if v, ok := comp.Properties[p.Name]; ok {
+				comp.Properties[p.Name] = v + ", " + p.Value
+			} else {
+				comp.Properties[p.Name] = p.Value
 			}
 		}
-		if c.Supplier != nil && c.Supplier.Name != "" {
-			comp.Supplier = c.Supplier.Name
+	}
+	comp.ID = identity.ComputeID(comp.ToIdentity())
+	return comp
+}
+
+// cdxLicense returns the SPDX ID of a license entry, falling back to its
+// free-text name and then to an expression.
+func cdxLicense(lic cdx.LicenseChoice) string {
+	if lic.License != nil {
+		if lic.License.ID != "" {
+			return lic.License.ID
 		}
-		if i < len(rawDoc.Components) {
-			comp.RawJSON = rawDoc.Components[i]
+		if lic.License.Name != "" {
+			return lic.License.Name
 		}
-		comp.ID = identity.ComputeID(comp.ToIdentity())
-		comps = append(comps, comp)
 	}
-	return comps, info, nil
+	return lic.Expression
+}
+
+// cdxTool returns the first generating tool's name and version.
+func cdxTool(tools *cdx.ToolsChoice) (string, string) {
+	if tools == nil {
+		return "", ""
+	}
+	if tools.Components != nil {
+		for _, t := range *tools.Components {
+			if t.Name != "" {
+				return t.Name, t.Version
+			}
+		}
+	}
+	if tools.Tools != nil {
+		for _, t := range *tools.Tools {
+			if t.Name != "" {
+				return t.Name, t.Version
+			}
+		}
+	}
+	return "", ""
 }
@@ -3,6 +3,7 @@ package sbom
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 // CompareComponents returns a list of field changes.
@@ -14,14 +15,65 @@ func CompareComponents(before, after Component) []string {
 	if !equalSlices(before.Licenses, after.Licenses) {
 		changes = append(changes, fmt.Sprintf("licenses: %v -> %v", before.Licenses, after.Licenses))
 	}
-	for algo, hash := range before.Hashes {
+	algos := make([]string, 0, len(before.Hashes))
+	for algo := range before.Hashes {
+		algos = append(algos, algo)
+	}
+	sort.Strings(algos)
+	for _, algo := range algos {
+		hash := before.Hashes[algo]
 		if newHash, exists := after.Hashes[algo]; exists && hash != newHash {
 			changes = append(changes, fmt.Sprintf("hash[%s]: %s -> %s", algo, hash, newHash))
 		}
 	}
+	if SupplierChanged(before.Supplier, after.Supplier) {
+		changes = append(changes, fmt.Sprintf("supplier: %s -> %s", before.Supplier, after.Supplier))
+	}
+	for _, name := range ChangedProperties(before.Properties, after.Properties) {
+		changes = append(changes, fmt.Sprintf("property[%s]: %s -> %s", name, propertyValue(before.Properties, name), propertyValue(after.Properties, name)))
+	}
 	return changes
 }
 
+// SupplierChanged reports whether both sides name a supplier and the
+// names differ in more than case and spacing. A supplier only one side
+// records is not a change, since not every format carries one.
+func SupplierChanged(before, after string) bool {
+	before, after = NormalizeSupplier(before), NormalizeSupplier(after)
+	return before != "" && after != "" && before != after
+}
+
+// NormalizeSupplier folds case and collapses whitespace so suppliers can be
+// compared and used as set keys.
+func NormalizeSupplier(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// ChangedProperties returns the sorted names of properties that were
+// added, removed or changed between before and after.
+func ChangedProperties(before, after map[string]string) []string {
+	var names []string
+	for name, v := range before {
+		if av, ok := after[name]; !ok || av != v {
+			names = append(names, name)
+		}
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func propertyValue(props map[string]string, name string) string {
+	if v, ok := props[name]; ok {
+		return v
+	}
+	return "(none)"
+}
+
 func equalSlices(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
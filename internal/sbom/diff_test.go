@@ -1,6 +1,9 @@
 package sbom
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestCompareComponents_NoChanges(t *testing.T) {
 	c := Component{
@@ -50,6 +53,41 @@ func TestCompareComponents_HashChange(t *testing.T) {
 	}
 }
 
+func TestCompareComponents_PropertyChange(t *testing.T) {
+	before := Component{Properties: map[string]string{"build:compiler": "gcc-12", "build:ci": "true"}}
+	after := Component{Properties: map[string]string{"build:compiler": "gcc-13", "build:runner": "self-hosted"}}
+	changes := CompareComponents(before, after)
+	want := []string{
+		"property[build:ci]: true -> (none)",
+		"property[build:compiler]: gcc-12 -> gcc-13",
+		"property[build:runner]: (none) -> self-hosted",
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+}
+
+func TestCompareComponents_SupplierChange(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          []string
+	}{
+		{"renamed", "OpenJS Foundation", "Evil Corp", []string{"supplier: OpenJS Foundation -> Evil Corp"}},
+		{"case and spacing", "OpenJS Foundation", " openjs  foundation", nil},
+		{"only after", "", "Evil Corp", nil},
+		{"only before", "OpenJS Foundation", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := CompareComponents(Component{Supplier: tt.before}, Component{Supplier: tt.after})
+			if !slices.Equal(changes, tt.want) {
+				t.Errorf("changes = %v, want %v", changes, tt.want)
+			}
+		})
+	}
+}
+
 func TestCompareComponents_MultipleChanges(t *testing.T) {
 	before := Component{
 		Version:  "1.0.0",
@@ -0,0 +1,47 @@
+//go:build ignore
+
+// gen_spdx_licenses writes spdx_licenses.txt from the SPDX license and
+// exception IDs in the CycloneDX module's JSON schema, so the list moves
+// with the cyclonedx-go version in go.mod.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+func main() {
+	dir, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/CycloneDX/cyclonedx-go").Output()
+	if err != nil {
+		log.Fatalf("locate cyclonedx-go: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(strings.TrimSpace(string(dir)), "schema", "spdx.schema.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var schema struct {
+		Comment string   `json:"$comment"`
+		Enum    []string `json:"enum"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		log.Fatal(err)
+	}
+	slices.Sort(schema.Enum)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Code generated by gen_spdx_licenses.go; DO NOT EDIT.\n")
+	fmt.Fprintf(&buf, "# SPDX license and exception IDs, license list %s.\n", schema.Comment)
+	for _, id := range slices.Compact(schema.Enum) {
+		fmt.Fprintln(&buf, id)
+	}
+	if err := os.WriteFile("spdx_licenses.txt", buf.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
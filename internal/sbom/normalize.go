@@ -1,6 +1,9 @@
 package sbom
 
 import (
+	"maps"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/rezmoss/sbomlyze/internal/identity"
@@ -10,21 +13,120 @@ func normalizeString(s string) string {
 	return strings.ToLower(strings.TrimSpace(s))
 }
 
+// IsPlaceholderLicense reports whether s is SPDX NOASSERTION/NONE (or
+// "unknown") rather than an actual license.
+func IsPlaceholderLicense(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "noassertion", "none", "unknown":
+		return true
+	}
+	return false
+}
+
+// licenseAliases maps common non-SPDX spellings, keyed lowercase with
+// single spaces, to their SPDX identifier. Only unambiguous names are
+// listed: "BSD", "GPL" or "Apache Software License" could be several
+// licenses and are left as written.
+var licenseAliases = map[string]string{
+	"mit":             "MIT",
+	"mit license":     "MIT",
+	"the mit license": "MIT",
+
+	"apache-2.0":                      "Apache-2.0",
+	"apache-2":                        "Apache-2.0",
+	"apache 2":                        "Apache-2.0",
+	"apache 2.0":                      "Apache-2.0",
+	"apache2":                         "Apache-2.0",
+	"apache license 2.0":              "Apache-2.0",
+	"apache license version 2.0":      "Apache-2.0",
+	"apache license, version 2.0":     "Apache-2.0",
+	"the apache license, version 2.0": "Apache-2.0",
+
+	"bsd-2-clause":    "BSD-2-Clause",
+	"bsd 2-clause":    "BSD-2-Clause",
+	"simplified bsd":  "BSD-2-Clause",
+	"bsd-3-clause":    "BSD-3-Clause",
+	"bsd 3-clause":    "BSD-3-Clause",
+	"new bsd":         "BSD-3-Clause",
+	"new bsd license": "BSD-3-Clause",
+
+	"isc":                        "ISC",
+	"isc license":                "ISC",
+	"mpl-2.0":                    "MPL-2.0",
+	"mpl 2.0":                    "MPL-2.0",
+	"mozilla public license 2.0": "MPL-2.0",
+	"unlicense":                  "Unlicense",
+	"the unlicense":              "Unlicense",
+	"cc0":                        "CC0-1.0",
+	"cc0 1.0":                    "CC0-1.0",
+	"zlib":                       "Zlib",
+}
+
 func normalizeLicense(s string) string {
 	s = strings.TrimSpace(s)
-
-	lower := strings.ToLower(s)
-	if lower == "noassertion" || lower == "none" || lower == "unknown" {
+	if IsPlaceholderLicense(s) {
 		return ""
 	}
 
-	if lower == "mit" {
-		return "MIT"
+	key := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	if id, ok := licenseAliases[key]; ok {
+		return id
 	}
 
 	return s
 }
 
+// NormalizeHashAlgorithm returns a canonical algorithm name so CycloneDX
+// ("SHA-256"), SPDX ("SHA256") and Syft ("sha256") spellings compare equal.
+func NormalizeHashAlgorithm(algo string) string {
+	upper := strings.ToUpper(strings.TrimSpace(algo))
+	upper = strings.ReplaceAll(upper, "_", "-")
+	if rest, ok := strings.CutPrefix(upper, "SHA-"); ok {
+		return "SHA" + rest
+	}
+	return upper
+}
+
+// PlaceholderHashPatterns match hash values some tools emit in place of a
+// real digest. Values are matched lowercase, without an "algo:" prefix.
+var PlaceholderHashPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^$`),
+	regexp.MustCompile(`^0+$`),
+	regexp.MustCompile(`^(noassertion|none|null|unknown|n/a)$`),
+}
+
+// IsPlaceholderHash reports whether value carries no integrity data.
+func IsPlaceholderHash(value string) bool {
+	v := strings.ToLower(strings.TrimSpace(value))
+	if _, digest, ok := strings.Cut(v, ":"); ok {
+		v = strings.TrimSpace(digest)
+	}
+	for _, re := range PlaceholderHashPatterns {
+		if re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// realHashes drops placeholder values, so such components count as
+// having no hashes. hashes is returned as is when nothing is dropped.
+func realHashes(hashes map[string]string) map[string]string {
+	if !slices.ContainsFunc(slices.Collect(maps.Values(hashes)), IsPlaceholderHash) {
+		return hashes
+	}
+	out := make(map[string]string, len(hashes))
+	for algo, v := range hashes {
+		if !IsPlaceholderHash(v) {
+			out[algo] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
 // NormalizeComponent normalizes a component.
 func NormalizeComponent(c Component) Component {
 	normalized := Component{
@@ -32,7 +134,7 @@ func NormalizeComponent(c Component) Component {
 		Name:         normalizeString(c.Name),
 		Version:      strings.TrimSpace(c.Version),
 		PURL:         strings.TrimSpace(c.PURL),
-		Hashes:       c.Hashes,
+		Hashes:       realHashes(c.Hashes),
 		Dependencies: c.Dependencies,
 		CPEs:         c.CPEs,
 		BOMRef:       strings.TrimSpace(c.BOMRef),
@@ -43,6 +145,8 @@ func NormalizeComponent(c Component) Component {
 		FoundBy:      c.FoundBy,
 		Type:         c.Type,
 		Locations:    c.Locations,
+		References:   c.References,
+		Properties:   c.Properties,
 		RawJSON:      c.RawJSON,
 	}
 
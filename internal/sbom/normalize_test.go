@@ -1,6 +1,9 @@
 package sbom
 
-import "testing"
+import (
+	"maps"
+	"testing"
+)
 
 func TestNormalizeLicense(t *testing.T) {
 	tests := []struct {
@@ -15,6 +18,15 @@ func TestNormalizeLicense(t *testing.T) {
 		{"noassertion normalized", "noassertion", ""},
 		{"empty string", "", ""},
 		{"complex license", "GPL-2.0-or-later", "GPL-2.0-or-later"},
+		{"apache alias", "Apache 2.0", "Apache-2.0"},
+		{"apache long name", "Apache License, Version 2.0", "Apache-2.0"},
+		{"lowercase spdx id", "apache-2.0", "Apache-2.0"},
+		{"collapses inner whitespace", "The  MIT   License", "MIT"},
+		{"new bsd", "New BSD License", "BSD-3-Clause"},
+		{"mpl name", "Mozilla Public License 2.0", "MPL-2.0"},
+		{"ambiguous bsd untouched", "BSD", "BSD"},
+		{"ambiguous gpl untouched", "GPL", "GPL"},
+		{"expression untouched", "MIT OR Apache-2.0", "MIT OR Apache-2.0"},
 	}
 
 	for _, tt := range tests {
@@ -27,6 +39,77 @@ func TestNormalizeLicense(t *testing.T) {
 	}
 }
 
+func TestNormalizeHashAlgorithm(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"SHA-256", "SHA256"},
+		{"SHA256", "SHA256"},
+		{"sha256", "SHA256"},
+		{"SHA-1", "SHA1"},
+		{"md5", "MD5"},
+		{"SHA3-256", "SHA3-256"},
+		{"BLAKE2b-256", "BLAKE2B-256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeHashAlgorithm(tt.input); got != tt.expected {
+				t.Errorf("NormalizeHashAlgorithm(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsPlaceholderHash(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000000", true},
+		{"00000000", true},
+		{"sha256:0000000000000000", true},
+		{"", true},
+		{"  ", true},
+		{"sha256:", true},
+		{"NOASSERTION", true},
+		{"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", false},
+		{"sha256:e3b0c44298fc1c149afbf4c8996fb924", false},
+		{"0000000000000001", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := IsPlaceholderHash(tt.value); got != tt.want {
+				t.Errorf("IsPlaceholderHash(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeComponent_PlaceholderHashes(t *testing.T) {
+	tests := []struct {
+		name   string
+		hashes map[string]string
+		want   map[string]string
+	}{
+		{"all zero", map[string]string{"SHA256": "0000000000000000000000000000000000000000000000000000000000000000"}, nil},
+		{"empty value", map[string]string{"SHA256": ""}, nil},
+		{"keeps real digests", map[string]string{"SHA256": "", "SHA1": "da39a3ee5e6b4b0d3255bfef95601890afd80709"}, map[string]string{"SHA1": "da39a3ee5e6b4b0d3255bfef95601890afd80709"}},
+		{"no placeholders", map[string]string{"SHA1": "da39a3ee5e6b4b0d3255bfef95601890afd80709"}, map[string]string{"SHA1": "da39a3ee5e6b4b0d3255bfef95601890afd80709"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeComponent(Component{Name: "x", Hashes: tt.hashes}).Hashes
+			if !maps.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("Hashes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNormalizeComponent(t *testing.T) {
 	t.Run("normalizes name", func(t *testing.T) {
 		comp := Component{
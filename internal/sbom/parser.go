@@ -2,35 +2,59 @@ package sbom
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"os"
 	"strings"
 )
 
+// ErrUnknownFormat is returned for input that is not CycloneDX, SPDX or Syft JSON.
+var ErrUnknownFormat = errors.New("unknown SBOM format")
+
 // ParseFile parses an SBOM file.
 func ParseFile(path string) ([]Component, error) {
 	comps, _, err := ParseFileWithInfo(path)
 	return comps, err
 }
 
-// ParseFileWithInfo parses an SBOM file with metadata.
+// ReadOptions controls what is kept while parsing.
+type ReadOptions struct {
+	KeepRaw bool // keep per-component RawJSON for the TUI/web JSON views
+}
+
+// ParseFileWithInfo parses an SBOM file with metadata, keeping raw JSON.
 func ParseFileWithInfo(path string) ([]Component, SBOMInfo, error) {
+	return ParseFileWithOptions(path, ReadOptions{KeepRaw: true})
+}
+
+// ParseFileWithOptions parses an SBOM file with metadata.
+func ParseFileWithOptions(path string, opts ReadOptions) ([]Component, SBOMInfo, error) {
+	if fi, err := os.Stat(path); err == nil && fi.Size() >= StreamThreshold {
+		return parseFileStreaming(path, opts.KeepRaw)
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, SBOMInfo{}, err
 	}
 
+	if IsAttestation(data) {
+		var env dsseEnvelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			return nil, SBOMInfo{}, err
+		}
+		return parseAttestation(env, opts.KeepRaw)
+	}
+
 	if IsCycloneDX(data) {
-		return ParseCycloneDXWithInfo(data)
+		return parseCycloneDX(data, opts.KeepRaw)
 	}
 	if IsSPDX(data) {
-		comps, err := ParseSPDX(path)
-		return comps, SBOMInfo{}, err
+		return parseSPDX(path, opts.KeepRaw)
 	}
 	if IsSyft(data) {
-		return ParseSyftWithInfo(data)
+		return parseSyft(data, opts.KeepRaw)
 	}
-	return nil, SBOMInfo{}, fmt.Errorf("unknown SBOM format")
+	return nil, SBOMInfo{}, ErrUnknownFormat
 }
 
 // decodeTopLevelKeys extracts top-level JSON keys.
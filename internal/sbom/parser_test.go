@@ -429,3 +429,44 @@ func TestDecodeTopLevelKeys_MixedTypes(t *testing.T) {
 		t.Errorf("expected items to be json.RawMessage, got %T", result["items"])
 	}
 }
+
+func TestParseFileWithOptions_KeepRaw(t *testing.T) {
+	files := []string{"cyclonedx-before.json", "spdx-sample.json", "syft-sample.json"}
+
+	for _, name := range files {
+		for _, streaming := range []bool{false, true} {
+			mode := "whole"
+			if streaming {
+				mode = "streaming"
+			}
+			t.Run(name+"/"+mode, func(t *testing.T) {
+				if streaming {
+					withStreamThreshold(t, 0)
+				}
+
+				comps, _, err := ParseFileWithOptions(testdataPath(name), ReadOptions{KeepRaw: false})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(comps) == 0 {
+					t.Fatal("expected components")
+				}
+				for _, c := range comps {
+					if c.RawJSON != nil {
+						t.Errorf("%s: expected nil RawJSON with KeepRaw=false", c.Name)
+					}
+				}
+
+				comps, _, err = ParseFileWithOptions(testdataPath(name), ReadOptions{KeepRaw: true})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				for _, c := range comps {
+					if len(c.RawJSON) == 0 {
+						t.Errorf("%s: expected RawJSON with KeepRaw=true", c.Name)
+					}
+				}
+			})
+		}
+	}
+}
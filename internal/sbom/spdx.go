@@ -3,6 +3,7 @@ package sbom
 import (
 	"encoding/json"
 	"os"
+	"strings"
 
 	"github.com/rezmoss/sbomlyze/internal/identity"
 	spdxjson "github.com/spdx/tools-golang/json"
@@ -11,42 +12,75 @@ import (
 
 // ParseSPDXFromBytes parses SPDX from bytes.
 func ParseSPDXFromBytes(data []byte) ([]Component, error) {
+	comps, _, err := parseSPDXBytes(data, true)
+	return comps, err
+}
+
+// parseSPDXBytes parses in-memory SPDX via a temp file, as the SPDX reader
+// works on files.
+func parseSPDXBytes(data []byte, keepRaw bool) ([]Component, SBOMInfo, error) {
 	tmpFile, err := os.CreateTemp("", "sbom-*.json")
 	if err != nil {
-		return nil, err
+		return nil, SBOMInfo{}, err
 	}
 	defer func() { _ = os.Remove(tmpFile.Name()) }()
 	defer func() { _ = tmpFile.Close() }()
 
 	if _, err := tmpFile.Write(data); err != nil {
-		return nil, err
+		return nil, SBOMInfo{}, err
 	}
 	_ = tmpFile.Close()
 
-	return ParseSPDX(tmpFile.Name())
+	return parseSPDX(tmpFile.Name(), keepRaw)
 }
 
 // ParseSPDX parses an SPDX file.
 func ParseSPDX(path string) ([]Component, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
+	comps, _, err := ParseSPDXWithInfo(path)
+	return comps, err
+}
 
+// ParseSPDXWithInfo parses an SPDX file with metadata.
+func ParseSPDXWithInfo(path string) ([]Component, SBOMInfo, error) {
+	return parseSPDX(path, true)
+}
+
+func parseSPDX(path string, keepRaw bool) ([]Component, SBOMInfo, error) {
 	var rawDoc struct {
 		Packages []json.RawMessage `json:"packages"`
 	}
-	_ = json.Unmarshal(data, &rawDoc) // Ignore error, may not have packages array
+	if keepRaw {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, SBOMInfo{}, err
+		}
+		_ = json.Unmarshal(data, &rawDoc) // Ignore error, may not have packages array
+	}
 
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return nil, SBOMInfo{}, err
 	}
 	defer func() { _ = f.Close() }()
 
 	doc, err := spdxjson.Read(f)
 	if err != nil {
-		return nil, err
+		return nil, SBOMInfo{}, err
+	}
+
+	info := SBOMInfo{Format: FormatSPDX}
+	if doc.CreationInfo != nil {
+		info.Timestamp = doc.CreationInfo.Created
+		for _, c := range doc.CreationInfo.Creators {
+			switch c.CreatorType {
+			case "Tool":
+				if info.ToolName == "" {
+					info.ToolName, info.ToolVersion = splitSPDXTool(c.Creator)
+				}
+			case "Person", "Organization":
+				info.Authors = append(info.Authors, c.Creator)
+			}
+		}
 	}
 
 	var comps []Component
@@ -63,6 +97,8 @@ func ParseSPDX(path string) ([]Component, error) {
 			}
 			if ref.RefType == "cpe22Type" || ref.RefType == "cpe23Type" {
 				comp.CPEs = append(comp.CPEs, ref.Locator)
+			} else if strings.EqualFold(ref.Category, spdx.CategorySecurity) {
+				comp.References = append(comp.References, Reference{Type: ref.RefType, Locator: ref.Locator})
 			}
 		}
 		if pkg.PackageLicenseConcluded != "" {
@@ -77,5 +113,15 @@ func ParseSPDX(path string) ([]Component, error) {
 		comp.ID = identity.ComputeID(comp.ToIdentity())
 		comps = append(comps, comp)
 	}
-	return comps, nil
+	return comps, info, nil
+}
+
+// splitSPDXTool splits "syft-1.0.0" into name and version.
+func splitSPDXTool(s string) (string, string) {
+	if idx := strings.LastIndex(s, "-"); idx > 0 && idx+1 < len(s) {
+		if v := s[idx+1]; v >= '0' && v <= '9' {
+			return s[:idx], s[idx+1:]
+		}
+	}
+	return s, ""
 }
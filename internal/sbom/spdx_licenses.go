@@ -0,0 +1,63 @@
+package sbom
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+//go:generate go run gen_spdx_licenses.go
+
+//go:embed spdx_licenses.txt
+var spdxLicenseData string
+
+// spdxLicenseIDs is the embedded list keyed lowercase; SPDX IDs match
+// case-insensitively.
+var spdxLicenseIDs = sync.OnceValue(func() map[string]bool {
+	ids := make(map[string]bool)
+	for _, line := range strings.Split(spdxLicenseData, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			ids[strings.ToLower(line)] = true
+		}
+	}
+	return ids
+})
+
+// IsSPDXLicenseID reports whether id is on the SPDX license or exception
+// list, optionally with the "or later" "+" suffix, or is a LicenseRef.
+func IsSPDXLicenseID(id string) bool {
+	lower := strings.ToLower(id)
+	if strings.HasPrefix(lower, "licenseref-") || strings.HasPrefix(lower, "documentref-") {
+		return true
+	}
+	ids := spdxLicenseIDs()
+	return ids[lower] || ids[strings.TrimSuffix(lower, "+")]
+}
+
+// UnknownLicenseIDs returns the identifiers in a license or SPDX license
+// expression that are not on the SPDX list. Known aliases such as
+// "Apache 2.0" and placeholders such as NOASSERTION are accepted. Free
+// text that is not an expression, e.g. "GNU GPL v2", is returned whole.
+func UnknownLicenseIDs(license string) []string {
+	license = normalizeLicense(license)
+	if license == "" {
+		return nil
+	}
+	var unknown []string
+	expression := false
+	fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(license))
+	for _, f := range fields {
+		switch strings.ToUpper(f) {
+		case "AND", "OR", "WITH":
+			expression = true
+			continue
+		}
+		if !IsSPDXLicenseID(f) {
+			unknown = append(unknown, f)
+		}
+	}
+	if len(unknown) > 0 && len(fields) > 1 && !expression {
+		return []string{license}
+	}
+	return unknown
+}
@@ -0,0 +1,48 @@
+package sbom
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUnknownLicenseIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		license string
+		want    []string
+	}{
+		{"valid", "MIT", nil},
+		{"case-insensitive", "apache-2.0", nil},
+		{"alias", "Apache License, Version 2.0", nil},
+		{"or later", "GPL-2.0+", nil},
+		{"licenseref", "LicenseRef-proprietary", nil},
+		{"placeholder", "NOASSERTION", nil},
+		{"misspelled", "Apache-2.O", []string{"Apache-2.O"}},
+		{"expression", "(MIT OR Apache-2.0) AND BSD-3-Clause", nil},
+		{"expression with exception", "GPL-2.0-only WITH Classpath-exception-2.0", nil},
+		{"expression with misspelled atom", "MIT or GPL-3.0-onyl", []string{"GPL-3.0-onyl"}},
+		{"free text", "GNU General Public License", []string{"GNU General Public License"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnknownLicenseIDs(tt.license); !slices.Equal(got, tt.want) {
+				t.Errorf("UnknownLicenseIDs(%q) = %q, want %q", tt.license, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_UnknownLicenseID(t *testing.T) {
+	issues := Validate([]Component{
+		{Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}},
+		{Name: "left-pad", Version: "1.3.0", Licenses: []string{"WTFPL", "BSD-3-Clauze"}},
+	})
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %+v", issues)
+	}
+	want := Issue{Code: IssueUnknownLicenseID, Component: "left-pad@1.3.0", Field: "licenses", Message: `left-pad@1.3.0: unknown SPDX license ID "BSD-3-Clauze"`}
+	if issues[0] != want {
+		t.Errorf("got %+v, want %+v", issues[0], want)
+	}
+}
@@ -2,6 +2,7 @@ package sbom
 
 import (
 	"os"
+	"slices"
 	"testing"
 )
 
@@ -30,6 +31,35 @@ func TestParseSPDX_BasicPackages(t *testing.T) {
 	}
 }
 
+func TestParseSPDXWithInfo_CreationInfo(t *testing.T) {
+	_, info, err := ParseSPDXWithInfo(testdataPath("spdx-sample.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ToolName != "test" {
+		t.Errorf("expected ToolName=test, got %q", info.ToolName)
+	}
+	if info.Timestamp != "2024-01-01T00:00:00Z" {
+		t.Errorf("expected Timestamp=2024-01-01T00:00:00Z, got %q", info.Timestamp)
+	}
+}
+
+func TestSplitSPDXTool(t *testing.T) {
+	tests := []struct {
+		in, name, version string
+	}{
+		{"syft-0.100.0", "syft", "0.100.0"},
+		{"spdx-sbom-generator-v0.0.15", "spdx-sbom-generator-v0.0.15", ""},
+		{"test", "test", ""},
+	}
+	for _, tt := range tests {
+		name, version := splitSPDXTool(tt.in)
+		if name != tt.name || version != tt.version {
+			t.Errorf("splitSPDXTool(%q) = %q, %q; want %q, %q", tt.in, name, version, tt.name, tt.version)
+		}
+	}
+}
+
 func TestParseSPDX_PURLFromExternalRefs(t *testing.T) {
 	comps, err := ParseSPDX(testdataPath("spdx-sample.json"))
 	if err != nil {
@@ -59,6 +89,30 @@ func TestParseSPDX_CPEFromExternalRefs(t *testing.T) {
 	}
 }
 
+func TestParseSPDX_SecurityReferences(t *testing.T) {
+	comps, err := ParseSPDX(testdataPath("spdx-security-refs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comps) != 1 {
+		t.Fatalf("expected 1 component, got %d", len(comps))
+	}
+	c := comps[0]
+	if len(c.CPEs) != 1 || c.PURL != "pkg:generic/curl@8.0.0" {
+		t.Errorf("expected CPE and PURL extraction unchanged, got CPEs %v, PURL %q", c.CPEs, c.PURL)
+	}
+	want := []Reference{
+		{Type: "advisory", Locator: "https://curl.se/docs/CVE-2023-38545.html"},
+		{Type: "fix", Locator: "https://github.com/curl/curl/commit/fb4415d8aee6c1045be932a34fe6107c2f5ed147"},
+	}
+	if !slices.Equal(c.References, want) {
+		t.Errorf("References = %+v, want %+v", c.References, want)
+	}
+	if got := NormalizeComponent(c).References; !slices.Equal(got, want) {
+		t.Errorf("NormalizeComponent dropped references: %+v", got)
+	}
+}
+
 func TestParseSPDX_LicenseConcluded(t *testing.T) {
 	comps, err := ParseSPDX(testdataPath("spdx-sample.json"))
 	if err != nil {
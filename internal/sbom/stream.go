@@ -0,0 +1,273 @@
+package sbom
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+// StreamThreshold is the file size at which Syft and CycloneDX documents are
+// decoded element by element instead of being read into memory whole.
+var StreamThreshold int64 = 64 << 20
+
+// streamDoc collects what the streaming decoder saw at the top level.
+type streamDoc struct {
+	bomFormat   string
+	schemaURL   string
+	spdxVersion string
+
+	// a DSSE envelope's payload is one string, so it is read whole
+	envelope dsseEnvelope
+
+	hasArtifacts  bool
+	hasSource     bool
+	hasDistro     bool
+	hasDescriptor bool
+
+	keepRaw bool
+
+	cdxMeta     *cdx.Metadata
+	cdxComps    []Component
+	cdxDeps     []cdx.Dependency
+	cdxServices *[]cdx.Service
+
+	syftComps      []Component
+	syftListed     int
+	syftIDToIdx    map[string]int
+	syftRels       []syftRelationship
+	syftSource     json.RawMessage
+	syftDistro     json.RawMessage
+	syftDescriptor syftDescriptor
+	syftSchema     string
+	filesCount     int
+}
+
+// parseFileStreaming parses a large SBOM without holding the whole document in memory.
+func parseFileStreaming(path string, keepRaw bool) ([]Component, SBOMInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, SBOMInfo{}, err
+	}
+	defer f.Close()
+
+	doc := &streamDoc{keepRaw: keepRaw, syftIDToIdx: make(map[string]int)}
+	if err := doc.decode(json.NewDecoder(bufio.NewReaderSize(f, 1<<20))); err != nil {
+		return nil, SBOMInfo{}, err
+	}
+
+	// Same precedence as ParseFileWithInfo: attestation, CycloneDX, SPDX, Syft.
+	switch {
+	case doc.envelope.PayloadType != "" && doc.envelope.Payload != "":
+		return parseAttestation(doc.envelope, keepRaw)
+	case doc.bomFormat == "CycloneDX" || strings.Contains(strings.ToLower(doc.schemaURL), "cyclonedx"):
+		info := cdxInfo(doc.cdxMeta)
+		if doc.cdxDeps != nil && len(doc.cdxComps) > 0 {
+			info.ParseIssues = linkCycloneDXDependencies(doc.cdxComps, doc.cdxDeps, cdxOtherRefs(doc.cdxMeta, doc.cdxServices))
+		}
+		return doc.cdxComps, info, nil
+	case strings.HasPrefix(doc.spdxVersion, "SPDX-"):
+		return parseSPDX(path, keepRaw)
+	case doc.hasArtifacts && (doc.hasSource || doc.hasDistro || doc.hasDescriptor):
+		return doc.syftResult()
+	}
+	return nil, SBOMInfo{}, ErrUnknownFormat
+}
+
+func (d *streamDoc) decode(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return ErrUnknownFormat
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		switch key {
+		case "bomFormat":
+			d.bomFormat = decodeString(dec)
+		case "$schema":
+			d.schemaURL = decodeString(dec)
+		case "spdxVersion":
+			d.spdxVersion = decodeString(dec)
+		case "payloadType":
+			d.envelope.PayloadType = decodeString(dec)
+		case "payload":
+			d.envelope.Payload = decodeString(dec)
+		case "metadata":
+			var meta cdx.Metadata
+			if err := dec.Decode(&meta); err != nil {
+				return err
+			}
+			d.cdxMeta = &meta
+		case "components":
+			err = streamArray(dec, func() error {
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return err
+				}
+				var c cdx.Component
+				if err := json.Unmarshal(raw, &c); err != nil {
+					return err
+				}
+				if !d.keepRaw {
+					raw = nil
+				}
+				d.cdxComps = append(d.cdxComps, cdxComponents(c, raw)...)
+				return nil
+			})
+		case "dependencies":
+			err = dec.Decode(&d.cdxDeps)
+		case "services":
+			err = dec.Decode(&d.cdxServices)
+		case "artifacts":
+			d.hasArtifacts = true
+			err = streamArray(dec, func() error {
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return err
+				}
+				d.syftListed++
+				comp, syftID, ok := parseSyftArtifact(raw, d.keepRaw)
+				if !ok {
+					return nil
+				}
+				if syftID != "" {
+					d.syftIDToIdx[syftID] = len(d.syftComps)
+				}
+				d.syftComps = append(d.syftComps, comp)
+				return nil
+			})
+		case "artifactRelationships":
+			err = streamArray(dec, func() error {
+				var rel syftRelationship
+				if err := dec.Decode(&rel); err != nil {
+					return err
+				}
+				d.syftRels = append(d.syftRels, rel)
+				return nil
+			})
+		case "files":
+			err = streamArray(dec, func() error {
+				d.filesCount++
+				return skipValue(dec)
+			})
+		case "source":
+			d.hasSource = true
+			err = dec.Decode(&d.syftSource)
+		case "distro":
+			d.hasDistro = true
+			err = dec.Decode(&d.syftDistro)
+		case "descriptor":
+			d.hasDescriptor = true
+			var raw json.RawMessage
+			if err = dec.Decode(&raw); err == nil {
+				_ = json.Unmarshal(raw, &d.syftDescriptor)
+			}
+		case "schema":
+			var raw json.RawMessage
+			if err = dec.Decode(&raw); err == nil {
+				var schema struct {
+					Version string `json:"version"`
+				}
+				if json.Unmarshal(raw, &schema) == nil {
+					d.syftSchema = schema.Version
+				}
+			}
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *streamDoc) syftResult() ([]Component, SBOMInfo, error) {
+	info := SBOMInfo{
+		Format:        FormatSyft,
+		ToolName:      d.syftDescriptor.Name,
+		ToolVersion:   d.syftDescriptor.Version,
+		SchemaVersion: d.syftSchema,
+		SearchScope:   d.syftDescriptor.Configuration.Search.Scope,
+		FilesCount:    d.filesCount,
+	}
+	applySyftSource(d.syftSource, &info)
+	applySyftDistro(d.syftDistro, &info)
+	linkSyftRelationships(d.syftComps, d.syftIDToIdx, d.syftRels, &info)
+	info.ParseIssues = countMismatch(d.syftListed, len(d.syftComps))
+	return d.syftComps, info, nil
+}
+
+// streamArray calls each once per element of the array at the decoder's
+// position. A null or non-array value is consumed and ignored.
+func streamArray(dec *json.Decoder, each func() error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('['):
+	case json.Delim('{'):
+		return skipRest(dec)
+	default:
+		return nil
+	}
+	for dec.More() {
+		if err := each(); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // closing ']'
+	return err
+}
+
+// skipValue consumes the next value without building it.
+func skipValue(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == json.Delim('{') || tok == json.Delim('[') {
+		return skipRest(dec)
+	}
+	return nil
+}
+
+// skipRest consumes tokens until the already-opened object or array closes.
+func skipRest(dec *json.Decoder) error {
+	for depth := 1; depth > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// decodeString decodes a string value; other value types yield "".
+func decodeString(dec *json.Decoder) string {
+	var raw json.RawMessage
+	if dec.Decode(&raw) != nil {
+		return ""
+	}
+	var s string
+	_ = json.Unmarshal(raw, &s)
+	return s
+}
@@ -0,0 +1,131 @@
+package sbom
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func withStreamThreshold(t *testing.T, n int64) {
+	t.Helper()
+	old := StreamThreshold
+	StreamThreshold = n
+	t.Cleanup(func() { StreamThreshold = old })
+}
+
+func TestParseFileStreaming_MatchesWholeFile(t *testing.T) {
+	files := []string{
+		"cyclonedx-before.json",
+		"cyclonedx-with-metadata.json",
+		"cyclonedx-empty-components.json",
+		"cyclonedx-nested.json",
+		"cyclonedx-complex-licenses.json",
+		"cyclonedx-dangling-dependency.json",
+		"spdx-sample.json",
+		"syft-sample.json",
+		"syft-with-relationships.json",
+		"syft-distro-array.json",
+		"syft-malformed-artifact.json",
+		"syft-count-mismatch.json",
+		"real-cyclonedx-alpine.json",
+		"real-cyclonedx-node.json",
+		"real-syft-alpine.json",
+		"real-syft-python.json",
+	}
+
+	for _, name := range files {
+		t.Run(name, func(t *testing.T) {
+			path := testdataPath(name)
+			wantComps, wantInfo, wantErr := ParseFileWithInfo(path)
+
+			gotComps, gotInfo, gotErr := parseFileStreaming(path, true)
+
+			if (gotErr != nil) != (wantErr != nil) {
+				t.Fatalf("error mismatch: streaming %v, whole %v", gotErr, wantErr)
+			}
+			if len(gotComps) != len(wantComps) {
+				t.Fatalf("component count: streaming %d, whole %d", len(gotComps), len(wantComps))
+			}
+			if !reflect.DeepEqual(gotComps, wantComps) {
+				for i := range gotComps {
+					if !reflect.DeepEqual(gotComps[i], wantComps[i]) {
+						t.Fatalf("component %d differs:\nstreaming %+v\nwhole     %+v", i, gotComps[i], wantComps[i])
+					}
+				}
+			}
+			if !reflect.DeepEqual(gotInfo, wantInfo) {
+				t.Errorf("info differs:\nstreaming %+v\nwhole     %+v", gotInfo, wantInfo)
+			}
+		})
+	}
+}
+
+func TestParseFileWithInfo_UsesStreamingAboveThreshold(t *testing.T) {
+	withStreamThreshold(t, 0)
+
+	comps, info, err := ParseFileWithInfo(testdataPath("syft-with-relationships.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comps) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(comps))
+	}
+	if len(comps[0].Dependencies) != 1 {
+		t.Errorf("expected dependency-of relationship to be linked, got %v", comps[0].Dependencies)
+	}
+	if info.SourceName != "alpine:latest" {
+		t.Errorf("expected source alpine:latest, got %q", info.SourceName)
+	}
+}
+
+func TestParseFileStreaming_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown format", `{"foo": [1, 2, {"bar": null}]}`},
+		{"not an object", `[1, 2, 3]`},
+		{"truncated", `{"bomFormat": "CycloneDX", "components": [{"name": "a"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sbom.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := parseFileStreaming(path, true); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseFileStreaming_SkipsUnknownAndNullArrays(t *testing.T) {
+	content := `{
+		"artifacts": [{"id": "a", "name": "pkg", "version": "1.0", "purl": "pkg:npm/pkg@1.0"}],
+		"artifactRelationships": null,
+		"files": [{"id": "f1"}, {"id": "f2", "metadata": {"nested": [1, [2]]}}],
+		"extra": {"deep": [{"x": [true, false]}]},
+		"source": {"type": "directory", "name": "/src"}
+	}`
+	path := filepath.Join(t.TempDir(), "syft.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	comps, info, err := parseFileStreaming(path, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comps) != 1 || comps[0].Name != "pkg" {
+		t.Errorf("unexpected components: %+v", comps)
+	}
+	if info.FilesCount != 2 {
+		t.Errorf("expected FilesCount=2, got %d", info.FilesCount)
+	}
+	if info.SourceName != "/src" {
+		t.Errorf("expected source /src, got %q", info.SourceName)
+	}
+}
@@ -0,0 +1,205 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CheckStructure lints a document's cross references: every dependency or
+// relationship must point at an element the document defines, and element
+// identifiers must be unique. It works on the raw document because the
+// parsers drop references they cannot resolve.
+func CheckStructure(data []byte) ([]Issue, error) {
+	if IsAttestation(data) {
+		inner, err := UnwrapAttestation(data)
+		if err != nil {
+			return nil, err
+		}
+		data = inner
+	}
+	switch {
+	case IsCycloneDX(data):
+		return checkCycloneDXStructure(data)
+	case IsSPDX(data):
+		return checkSPDXStructure(data)
+	case IsSyft(data):
+		return checkSyftStructure(data)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// refSet collects element identifiers and reports duplicates.
+type refSet struct {
+	field  string
+	seen   map[string]bool
+	issues []Issue
+}
+
+func newRefSet(field string) *refSet {
+	return &refSet{field: field, seen: make(map[string]bool)}
+}
+
+func (r *refSet) add(ref, label string) {
+	if ref == "" {
+		return
+	}
+	if r.seen[ref] {
+		r.issues = append(r.issues, Issue{
+			Code:      IssueDuplicateRef,
+			Component: label,
+			Field:     r.field,
+			Message:   fmt.Sprintf("duplicate %s %q", r.field, ref),
+		})
+		return
+	}
+	r.seen[ref] = true
+}
+
+// check reports a reference from source that matches no element.
+func (r *refSet) check(source, ref string) {
+	if ref == "" || r.seen[ref] {
+		return
+	}
+	r.issues = append(r.issues, Issue{
+		Code:      IssueDanglingDependency,
+		Component: source,
+		Field:     "dependencies",
+		Message:   fmt.Sprintf("%s references unknown %s %q", source, r.field, ref),
+	})
+}
+
+type cdxRefNode struct {
+	BOMRef     string       `json:"bom-ref"`
+	Name       string       `json:"name"`
+	Version    string       `json:"version"`
+	Components []cdxRefNode `json:"components"`
+}
+
+func checkCycloneDXStructure(data []byte) ([]Issue, error) {
+	var doc struct {
+		Metadata struct {
+			Component *cdxRefNode `json:"component"`
+		} `json:"metadata"`
+		Components   []cdxRefNode `json:"components"`
+		Services     []cdxRefNode `json:"services"`
+		Dependencies []struct {
+			Ref       string   `json:"ref"`
+			DependsOn []string `json:"dependsOn"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	refs := newRefSet("bom-ref")
+	var walk func(nodes []cdxRefNode)
+	walk = func(nodes []cdxRefNode) {
+		for _, n := range nodes {
+			refs.add(n.BOMRef, componentLabel(Component{Name: n.Name, Version: n.Version}))
+			walk(n.Components)
+		}
+	}
+	if doc.Metadata.Component != nil {
+		walk([]cdxRefNode{*doc.Metadata.Component})
+	}
+	walk(doc.Components)
+	walk(doc.Services)
+
+	for _, d := range doc.Dependencies {
+		refs.check("dependencies", d.Ref)
+		for _, dep := range d.DependsOn {
+			refs.check(d.Ref, dep)
+		}
+	}
+	return refs.issues, nil
+}
+
+func checkSPDXStructure(data []byte) ([]Issue, error) {
+	type element struct {
+		SPDXID  string `json:"SPDXID"`
+		Name    string `json:"name"`
+		Version string `json:"versionInfo"`
+	}
+	var doc struct {
+		SPDXID            string    `json:"SPDXID"`
+		DocumentDescribes []string  `json:"documentDescribes"`
+		Packages          []element `json:"packages"`
+		Files             []element `json:"files"`
+		Snippets          []element `json:"snippets"`
+		Relationships     []struct {
+			Element string `json:"spdxElementId"`
+			Related string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	refs := newRefSet("SPDXID")
+	refs.add(doc.SPDXID, "document")
+	for _, list := range [][]element{doc.Packages, doc.Files, doc.Snippets} {
+		for _, e := range list {
+			refs.add(e.SPDXID, componentLabel(Component{Name: e.Name, Version: e.Version}))
+		}
+	}
+
+	// NOASSERTION/NONE are valid targets, and DocumentRef-x:SPDXRef-y
+	// points into another document.
+	checkSPDX := func(source, ref string) {
+		if ref == "NOASSERTION" || ref == "NONE" || strings.Contains(ref, ":") {
+			return
+		}
+		refs.check(source, ref)
+	}
+	for _, ref := range doc.DocumentDescribes {
+		checkSPDX("documentDescribes", ref)
+	}
+	for _, rel := range doc.Relationships {
+		checkSPDX("relationships", rel.Element)
+		checkSPDX(rel.Element, rel.Related)
+	}
+	return refs.issues, nil
+}
+
+func checkSyftStructure(data []byte) ([]Issue, error) {
+	var doc struct {
+		Artifacts []struct {
+			ID      string `json:"id"`
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"artifacts"`
+		Files []struct {
+			ID string `json:"id"`
+		} `json:"files"`
+		Source struct {
+			ID string `json:"id"`
+		} `json:"source"`
+		Relationships []struct {
+			Parent string `json:"parent"`
+			Child  string `json:"child"`
+		} `json:"artifactRelationships"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	refs := newRefSet("id")
+	for _, a := range doc.Artifacts {
+		refs.add(a.ID, componentLabel(Component{Name: a.Name, Version: a.Version}))
+	}
+	// files and the source are relationship targets too, but only
+	// artifact IDs have to be unique for diffing
+	for _, f := range doc.Files {
+		refs.seen[f.ID] = true
+	}
+	if doc.Source.ID != "" {
+		refs.seen[doc.Source.ID] = true
+	}
+
+	for _, rel := range doc.Relationships {
+		refs.check("artifactRelationships", rel.Parent)
+		refs.check(rel.Parent, rel.Child)
+	}
+	return refs.issues, nil
+}
@@ -0,0 +1,96 @@
+package sbom
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckStructure(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string // substrings of issue messages, in order
+	}{
+		{
+			name: "cyclonedx dangling dependsOn",
+			data: `{"bomFormat":"CycloneDX","components":[{"bom-ref":"a","name":"a"}],
+				"dependencies":[{"ref":"a","dependsOn":["b"]}]}`,
+			want: []string{`a references unknown bom-ref "b"`},
+		},
+		{
+			name: "cyclonedx dangling dependency ref",
+			data: `{"bomFormat":"CycloneDX","components":[{"bom-ref":"a","name":"a"}],
+				"dependencies":[{"ref":"ghost","dependsOn":[]}]}`,
+			want: []string{`unknown bom-ref "ghost"`},
+		},
+		{
+			name: "cyclonedx nested and metadata refs resolve",
+			data: `{"bomFormat":"CycloneDX","metadata":{"component":{"bom-ref":"app","name":"app"}},
+				"components":[{"bom-ref":"a","name":"a","components":[{"bom-ref":"a/b","name":"b"}]}],
+				"dependencies":[{"ref":"app","dependsOn":["a"]},{"ref":"a","dependsOn":["a/b"]}]}`,
+		},
+		{
+			name: "cyclonedx duplicate bom-ref",
+			data: `{"bomFormat":"CycloneDX","components":[{"bom-ref":"a","name":"a","version":"1"},{"bom-ref":"a","name":"a","version":"2"}]}`,
+			want: []string{`duplicate bom-ref "a"`},
+		},
+		{
+			name: "spdx dangling relationship",
+			data: `{"spdxVersion":"SPDX-2.3","SPDXID":"SPDXRef-DOCUMENT","packages":[{"SPDXID":"SPDXRef-a","name":"a"}],
+				"relationships":[{"spdxElementId":"SPDXRef-DOCUMENT","relatedSpdxElement":"SPDXRef-a","relationshipType":"DESCRIBES"},
+				{"spdxElementId":"SPDXRef-a","relatedSpdxElement":"SPDXRef-b","relationshipType":"DEPENDS_ON"},
+				{"spdxElementId":"SPDXRef-a","relatedSpdxElement":"NOASSERTION","relationshipType":"DEPENDS_ON"},
+				{"spdxElementId":"SPDXRef-a","relatedSpdxElement":"DocumentRef-ext:SPDXRef-x","relationshipType":"DEPENDS_ON"}]}`,
+			want: []string{`SPDXRef-a references unknown SPDXID "SPDXRef-b"`},
+		},
+		{
+			name: "syft dangling child",
+			data: `{"artifacts":[{"id":"1","name":"a"}],"files":[{"id":"f1"}],"source":{"id":"src"},
+				"artifactRelationships":[{"parent":"1","child":"f1","type":"contains"},{"parent":"src","child":"1","type":"contains"},
+				{"parent":"1","child":"2","type":"dependency-of"}]}`,
+			want: []string{`1 references unknown id "2"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := CheckStructure([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("expected %d issues, got %+v", len(tt.want), issues)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(issues[i].Message, want) {
+					t.Errorf("issue %d = %q, want it to contain %q", i, issues[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckStructure_Testdata(t *testing.T) {
+	for _, name := range []string{"cyclonedx-nested.json", "spdx-sample.json", "syft-with-relationships.json", "attestation-cyclonedx.json"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(testdataPath(name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			issues, err := CheckStructure(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(issues) != 0 {
+				t.Errorf("expected a clean file, got %+v", issues)
+			}
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		if _, err := CheckStructure([]byte(`{"foo":1}`)); err == nil {
+			t.Error("expected error for unknown format")
+		}
+	})
+}
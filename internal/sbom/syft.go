@@ -2,6 +2,7 @@ package sbom
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 
 	"github.com/rezmoss/sbomlyze/internal/identity"
@@ -13,28 +14,38 @@ func ParseSyft(data []byte) ([]Component, error) {
 	return comps, err
 }
 
+// syftRelationship is an artifactRelationships entry.
+type syftRelationship struct {
+	Parent string `json:"parent"`
+	Child  string `json:"child"`
+	Type   string `json:"type"`
+}
+
+// syftDescriptor is the Syft tool descriptor.
+type syftDescriptor struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	Configuration struct {
+		Search struct {
+			Scope string `json:"scope"`
+		} `json:"search"`
+	} `json:"configuration"`
+}
+
 // ParseSyftWithInfo parses Syft JSON with metadata.
 func ParseSyftWithInfo(data []byte) ([]Component, SBOMInfo, error) {
+	return parseSyft(data, true)
+}
+
+func parseSyft(data []byte, keepRaw bool) ([]Component, SBOMInfo, error) {
 	var doc struct {
-		Artifacts             []json.RawMessage `json:"artifacts"`
-		ArtifactRelationships []struct {
-			Parent string `json:"parent"`
-			Child  string `json:"child"`
-			Type   string `json:"type"`
-		} `json:"artifactRelationships"`
-		Source     json.RawMessage `json:"source"` // RawMessage to handle missing/malformed
-		Distro     json.RawMessage `json:"distro"` // RawMessage to handle object or array
-		Files      json.RawMessage `json:"files"`
-		Descriptor struct {
-			Name          string `json:"name"`
-			Version       string `json:"version"`
-			Configuration struct {
-				Search struct {
-					Scope string `json:"scope"`
-				} `json:"search"`
-			} `json:"configuration"`
-		} `json:"descriptor"`
-		Schema struct {
+		Artifacts             []json.RawMessage  `json:"artifacts"`
+		ArtifactRelationships []syftRelationship `json:"artifactRelationships"`
+		Source                json.RawMessage    `json:"source"` // RawMessage to handle missing/malformed
+		Distro                json.RawMessage    `json:"distro"` // RawMessage to handle object or array
+		Files                 json.RawMessage    `json:"files"`
+		Descriptor            syftDescriptor     `json:"descriptor"`
+		Schema                struct {
 			Version string `json:"version"`
 		} `json:"schema"`
 	}
@@ -42,7 +53,7 @@ func ParseSyftWithInfo(data []byte) ([]Component, SBOMInfo, error) {
 		return nil, SBOMInfo{}, err
 	}
 
-	var info SBOMInfo
+	info := SBOMInfo{Format: FormatSyft}
 	info.ToolName = doc.Descriptor.Name
 	info.ToolVersion = doc.Descriptor.Version
 	info.SchemaVersion = doc.Schema.Version
@@ -55,137 +66,180 @@ func ParseSyftWithInfo(data []byte) ([]Component, SBOMInfo, error) {
 		}
 	}
 
-	if len(doc.Source) > 0 {
-		var sourceInfo struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-			Type string `json:"type"`
-			Target struct {
-				UserInput string `json:"userInput"`
-			} `json:"target"`
+	applySyftSource(doc.Source, &info)
+	applySyftDistro(doc.Distro, &info)
+
+	syftIDToIdx := make(map[string]int)
+
+	var comps []Component
+	for _, rawArtifact := range doc.Artifacts {
+		comp, syftID, ok := parseSyftArtifact(rawArtifact, keepRaw)
+		if !ok {
+			continue
 		}
-		if err := json.Unmarshal(doc.Source, &sourceInfo); err == nil {
-			info.SourceType = sourceInfo.Type
-			info.SourceName = sourceInfo.Target.UserInput
-			if info.SourceName == "" {
-				info.SourceName = sourceInfo.Name
-			}
-			info.SourceID = sourceInfo.ID
+		if syftID != "" {
+			syftIDToIdx[syftID] = len(comps)
+		}
+		comps = append(comps, comp)
+	}
+
+	linkSyftRelationships(comps, syftIDToIdx, doc.ArtifactRelationships, &info)
+	info.ParseIssues = countMismatch(len(doc.Artifacts), len(comps))
+
+	return comps, info, nil
+}
+
+// applySyftSource fills source fields from the raw Syft source block.
+func applySyftSource(raw json.RawMessage, info *SBOMInfo) {
+	if len(raw) == 0 {
+		return
+	}
+	var sourceInfo struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Type   string `json:"type"`
+		Target struct {
+			UserInput string `json:"userInput"`
+		} `json:"target"`
+	}
+	if err := json.Unmarshal(raw, &sourceInfo); err == nil {
+		info.SourceType = sourceInfo.Type
+		info.SourceName = sourceInfo.Target.UserInput
+		if info.SourceName == "" {
+			info.SourceName = sourceInfo.Name
 		}
+		info.SourceID = sourceInfo.ID
 	}
+}
 
-	if len(doc.Distro) > 0 {
-		var distroInfo struct {
+// applySyftDistro fills OS fields from the raw Syft distro block.
+func applySyftDistro(raw json.RawMessage, info *SBOMInfo) {
+	if len(raw) == 0 {
+		return
+	}
+	var distroInfo struct {
+		Name       string   `json:"name"`
+		PrettyName string   `json:"prettyName"`
+		Version    string   `json:"version"`
+		ID         string   `json:"id"`
+		IDLike     []string `json:"idLike"`
+	}
+	// Try parsing as object first
+	if err := json.Unmarshal(raw, &distroInfo); err != nil {
+		// Try parsing as array and take first element
+		var distroArray []struct {
 			Name       string   `json:"name"`
 			PrettyName string   `json:"prettyName"`
 			Version    string   `json:"version"`
 			ID         string   `json:"id"`
 			IDLike     []string `json:"idLike"`
 		}
-		// Try parsing as object first
-		if err := json.Unmarshal(doc.Distro, &distroInfo); err != nil {
-			// Try parsing as array and take first element
-			var distroArray []struct {
-				Name       string   `json:"name"`
-				PrettyName string   `json:"prettyName"`
-				Version    string   `json:"version"`
-				ID         string   `json:"id"`
-				IDLike     []string `json:"idLike"`
-			}
-			if err := json.Unmarshal(doc.Distro, &distroArray); err == nil && len(distroArray) > 0 {
-				distroInfo = distroArray[0]
-			}
-		}
-		info.OSName = distroInfo.Name
-		info.OSVersion = distroInfo.Version
-		info.OSPrettyName = distroInfo.PrettyName
-		info.OSIDLike = distroInfo.IDLike
-		if info.OSName == "" && distroInfo.ID != "" {
-			info.OSName = distroInfo.ID
+		if err := json.Unmarshal(raw, &distroArray); err == nil && len(distroArray) > 0 {
+			distroInfo = distroArray[0]
 		}
 	}
+	info.OSName = distroInfo.Name
+	info.OSVersion = distroInfo.Version
+	info.OSPrettyName = distroInfo.PrettyName
+	info.OSIDLike = distroInfo.IDLike
+	if info.OSName == "" && distroInfo.ID != "" {
+		info.OSName = distroInfo.ID
+	}
+}
 
-	syftIDToIdx := make(map[string]int)
-
-	var comps []Component
-	for _, rawArtifact := range doc.Artifacts {
-		var a struct {
-			SyftID       string          `json:"id"`
-			Name         string          `json:"name"`
-			Version      string          `json:"version"`
-			PURL         string          `json:"purl"`
-			Type         string          `json:"type"`
-			Language     string          `json:"language"`
-			FoundBy      string          `json:"foundBy"`
-			MetadataType string          `json:"metadataType"`
-			Metadata     json.RawMessage `json:"metadata"`
-			Locations    []struct {
-				Path string `json:"path"`
-			} `json:"locations"`
-			Licenses []struct {
-				Value          string `json:"value"`
-				SPDXExpression string `json:"spdxExpression"`
-			} `json:"licenses"`
-			CPEs []struct {
-				CPE string `json:"cpe"`
-			} `json:"cpes"`
-		}
-		if err := json.Unmarshal(rawArtifact, &a); err != nil {
-			continue
-		}
+// parseSyftArtifact converts one Syft artifact. ok is false if it can't be decoded.
+func parseSyftArtifact(rawArtifact json.RawMessage, keepRaw bool) (comp Component, syftID string, ok bool) {
+	var a struct {
+		SyftID       string          `json:"id"`
+		Name         string          `json:"name"`
+		Version      string          `json:"version"`
+		PURL         string          `json:"purl"`
+		Type         string          `json:"type"`
+		Language     string          `json:"language"`
+		FoundBy      string          `json:"foundBy"`
+		MetadataType string          `json:"metadataType"`
+		Metadata     json.RawMessage `json:"metadata"`
+		Locations    []struct {
+			Path string `json:"path"`
+		} `json:"locations"`
+		Licenses []struct {
+			Value          string `json:"value"`
+			SPDXExpression string `json:"spdxExpression"`
+		} `json:"licenses"`
+		CPEs []struct {
+			CPE string `json:"cpe"`
+		} `json:"cpes"`
+	}
+	if err := json.Unmarshal(rawArtifact, &a); err != nil {
+		return Component{}, "", false
+	}
 
-		comp := Component{
-			Name:     a.Name,
-			Version:  a.Version,
-			PURL:     a.PURL,
-			Type:     a.Type,
-			Language: a.Language,
-			FoundBy:  a.FoundBy,
-			Hashes:   make(map[string]string),
-			RawJSON:  rawArtifact,
+	comp = Component{
+		Name:     a.Name,
+		Version:  a.Version,
+		PURL:     a.PURL,
+		Type:     a.Type,
+		Language: a.Language,
+		FoundBy:  a.FoundBy,
+		Hashes:   make(map[string]string),
+	}
+	if keepRaw {
+		comp.RawJSON = rawArtifact
+	}
+	for _, loc := range a.Locations {
+		if loc.Path != "" {
+			comp.Locations = append(comp.Locations, loc.Path)
 		}
-		for _, loc := range a.Locations {
-			if loc.Path != "" {
-				comp.Locations = append(comp.Locations, loc.Path)
-			}
+	}
+	for _, lic := range a.Licenses {
+		val := lic.SPDXExpression
+		if val == "" {
+			val = lic.Value
 		}
-		for _, lic := range a.Licenses {
-			val := lic.SPDXExpression
-			if val == "" {
-				val = lic.Value
-			}
-			if val != "" {
-				comp.Licenses = append(comp.Licenses, val)
-			}
+		if val != "" {
+			comp.Licenses = append(comp.Licenses, val)
 		}
-		for _, cpe := range a.CPEs {
-			if cpe.CPE != "" {
-				comp.CPEs = append(comp.CPEs, cpe.CPE)
-			}
+	}
+	for _, cpe := range a.CPEs {
+		if cpe.CPE != "" {
+			comp.CPEs = append(comp.CPEs, cpe.CPE)
 		}
+	}
 
-		extractSyftHashes(a.MetadataType, a.Metadata, comp.Hashes)
-
-		comp.ID = identity.ComputeID(comp.ToIdentity())
+	extractSyftHashes(a.MetadataType, a.Metadata, comp.Hashes)
 
-		if a.SyftID != "" {
-			syftIDToIdx[a.SyftID] = len(comps)
-		}
-		comps = append(comps, comp)
-	}
+	comp.ID = identity.ComputeID(comp.ToIdentity())
+	return comp, a.SyftID, true
+}
 
+// linkSyftRelationships maps relationships between artifacts onto comps
+// and counts relationship types. "parent dependency-of child" means the
+// child depends on the parent; "parent contains child" means the parent
+// depends on the child. Relationships to files or the source are not
+// artifacts and only counted.
+func linkSyftRelationships(comps []Component, syftIDToIdx map[string]int, rels []syftRelationship, info *SBOMInfo) {
 	relCounts := make(map[string]int)
-	depMap := make(map[int][]string) // parent comp index → child comp IDs
-	for _, rel := range doc.ArtifactRelationships {
+	depMap := make(map[int][]string) // dependent comp index → dependency comp IDs
+	for _, rel := range rels {
 		if rel.Type != "" {
 			relCounts[rel.Type]++
 		}
-		if rel.Type == "dependency-of" {
-			parentIdx, parentOK := syftIDToIdx[rel.Parent]
-			childIdx, childOK := syftIDToIdx[rel.Child]
-			if parentOK && childOK {
-				depMap[parentIdx] = append(depMap[parentIdx], comps[childIdx].ID)
-			}
+		var from, to string
+		switch rel.Type {
+		case "dependency-of":
+			from, to = rel.Child, rel.Parent
+		case "contains":
+			from, to = rel.Parent, rel.Child
+		default:
+			continue
+		}
+		fromIdx, fromOK := syftIDToIdx[from]
+		toIdx, toOK := syftIDToIdx[to]
+		if !fromOK || !toOK {
+			continue
+		}
+		if depID := comps[toIdx].ID; !slices.Contains(depMap[fromIdx], depID) {
+			depMap[fromIdx] = append(depMap[fromIdx], depID)
 		}
 	}
 	for idx, deps := range depMap {
@@ -194,8 +248,6 @@ func ParseSyftWithInfo(data []byte) ([]Component, SBOMInfo, error) {
 	if len(relCounts) > 0 {
 		info.RelationshipCounts = relCounts
 	}
-
-	return comps, info, nil
 }
 
 // extractSyftHashes extracts hashes from Syft metadata.
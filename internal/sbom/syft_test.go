@@ -2,6 +2,7 @@ package sbom
 
 import (
 	"os"
+	"slices"
 	"testing"
 )
 
@@ -100,6 +101,54 @@ func TestParseSyft_Dependencies(t *testing.T) {
 	t.Error("busybox not found")
 }
 
+func TestParseSyft_ArtifactRelationships(t *testing.T) {
+	// a subset of real Syft output: "musl dependency-of zlib" means zlib
+	// depends on musl, and contains to files or from the source is no edge
+	want := map[string][]string{
+		"apk-tools":  {"pkg:apk/libcrypto3", "pkg:apk/libssl3", "pkg:apk/musl", "pkg:apk/zlib"},
+		"libssl3":    {"pkg:apk/libcrypto3", "pkg:apk/musl"},
+		"libcrypto3": {"pkg:apk/musl"},
+		"zlib":       {"pkg:apk/musl"},
+		"musl":       nil,
+	}
+	check := func(t *testing.T, comps []Component) {
+		t.Helper()
+		if len(comps) != len(want) {
+			t.Fatalf("expected %d components, got %d", len(want), len(comps))
+		}
+		for _, c := range comps {
+			deps := slices.Sorted(slices.Values(c.Dependencies))
+			if !slices.Equal(deps, want[c.Name]) {
+				t.Errorf("%s: expected dependencies %v, got %v", c.Name, want[c.Name], deps)
+			}
+		}
+	}
+
+	t.Run("in memory", func(t *testing.T) {
+		data, err := os.ReadFile(testdataPath("syft-artifact-relationships.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		comps, info, err := ParseSyftWithInfo(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, comps)
+		if info.RelationshipCounts["contains"] != 12 || info.RelationshipCounts["dependency-of"] != 8 || info.RelationshipCounts["evident-by"] != 4 {
+			t.Errorf("expected every relationship to be counted, got %v", info.RelationshipCounts)
+		}
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		withStreamThreshold(t, 0)
+		comps, _, err := ParseFileWithInfo(testdataPath("syft-artifact-relationships.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, comps)
+	})
+}
+
 func TestParseSyft_RawJSON(t *testing.T) {
 	data, err := os.ReadFile(testdataPath("syft-sample.json"))
 	if err != nil {
@@ -147,6 +196,45 @@ func TestParseSyft_MalformedArtifact(t *testing.T) {
 	}
 }
 
+func TestParseSyft_CountMismatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		wantIssue string
+	}{
+		{"all artifacts parsed", "syft-sample.json", ""},
+		{"unreadable artifacts", "syft-count-mismatch.json", "document lists 4 components but 2 could be parsed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(testdataPath(tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, info, err := ParseSyftWithInfo(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, issue := range info.ParseIssues {
+				if issue.Code == IssueCountMismatch {
+					got = append(got, issue.Message)
+				}
+			}
+			if tt.wantIssue == "" {
+				if len(got) != 0 {
+					t.Errorf("expected no %s issue, got %v", IssueCountMismatch, got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0] != tt.wantIssue {
+				t.Errorf("expected issue %q, got %v", tt.wantIssue, got)
+			}
+		})
+	}
+}
+
 func TestParseSyft_EmptyArtifacts(t *testing.T) {
 	comps, err := ParseSyft([]byte(`{"artifacts":[]}`))
 	if err != nil {
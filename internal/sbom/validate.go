@@ -0,0 +1,232 @@
+package sbom
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rezmoss/sbomlyze/internal/identity"
+)
+
+// Issue is a problem found in a parsed component.
+type Issue struct {
+	Code      string // stable machine-readable kind, one of the Issue* constants
+	Component string // name@version of the offending component
+	Field     string
+	Message   string
+}
+
+// Issue codes.
+const (
+	IssuePlaceholderName    = "placeholder_name"
+	IssueSharedID           = "shared_placeholder_id"
+	IssueVersionMismatch    = "version_mismatch"
+	IssueDanglingDependency = "dangling_dependency"
+	IssueDuplicateRef       = "duplicate_ref"
+	IssueUnknownLicenseID   = "unknown_license_id"
+	IssuePlaceholderHash    = "placeholder_hash"
+	IssueCountMismatch      = "component_count_mismatch"
+	IssueImplicitComponent  = "implicit_component"
+	IssueSelfDependency     = "self_dependency"
+)
+
+// Validate checks parsed components for inconsistencies that confuse diffing.
+func Validate(comps []Component) []Issue {
+	var issues []Issue
+	placeholderIDs := make(map[string]int)
+	for _, c := range comps {
+		if HasPlaceholderName(c) {
+			label := componentLabel(c)
+			if strings.TrimSpace(c.Name) == "" {
+				label = "<unnamed>" + label
+			}
+			issues = append(issues, Issue{
+				Code:      IssuePlaceholderName,
+				Component: label,
+				Field:     "name",
+				Message:   fmt.Sprintf("%s: empty or placeholder name", label),
+			})
+			id := c.ID
+			if id == "" {
+				id = c.ComputeID()
+			}
+			placeholderIDs[id]++
+		}
+		if purlVer, ok := purlVersionMismatch(c); ok {
+			label := componentLabel(c)
+			issues = append(issues, Issue{
+				Code:      IssueVersionMismatch,
+				Component: label,
+				Field:     "version",
+				Message:   fmt.Sprintf("%s: version %q does not match PURL version %q", label, c.Version, purlVer),
+			})
+		}
+		for _, algo := range slices.Sorted(maps.Keys(c.Hashes)) {
+			if IsPlaceholderHash(c.Hashes[algo]) {
+				label := componentLabel(c)
+				issues = append(issues, Issue{
+					Code:      IssuePlaceholderHash,
+					Component: label,
+					Field:     "hashes",
+					Message:   fmt.Sprintf("%s: placeholder %s hash %q treated as missing", label, algo, c.Hashes[algo]),
+				})
+			}
+		}
+		if slices.Contains(c.Dependencies, c.ID) {
+			label := componentLabel(c)
+			issues = append(issues, Issue{
+				Code:      IssueSelfDependency,
+				Component: label,
+				Field:     "dependencies",
+				Message:   fmt.Sprintf("%s depends on itself; the edge is ignored", label),
+			})
+		}
+		for _, lic := range c.Licenses {
+			for _, id := range UnknownLicenseIDs(lic) {
+				label := componentLabel(c)
+				issues = append(issues, Issue{
+					Code:      IssueUnknownLicenseID,
+					Component: label,
+					Field:     "licenses",
+					Message:   fmt.Sprintf("%s: unknown SPDX license ID %q", label, id),
+				})
+			}
+		}
+	}
+
+	// Placeholder components with nothing better than a name to go on all
+	// get the same ID, and the diff matches them as a single component.
+	ids := make([]string, 0, len(placeholderIDs))
+	for id, n := range placeholderIDs {
+		if n > 1 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		issues = append(issues, Issue{
+			Code:    IssueSharedID,
+			Field:   "name",
+			Message: fmt.Sprintf("%d components with placeholder names share ID %q and are diffed as one", placeholderIDs[id], id),
+		})
+	}
+
+	// one issue per missing target rather than per edge
+	implicit, _ := DanglingDependencies(comps)
+	for _, id := range slices.Sorted(maps.Keys(implicit)) {
+		dependents := "1 component"
+		if n := implicit[id]; n > 1 {
+			dependents = fmt.Sprintf("%d components", n)
+		}
+		issues = append(issues, Issue{
+			Code:    IssueImplicitComponent,
+			Field:   "dependencies",
+			Message: fmt.Sprintf("%q is a dependency of %s but is not declared as a component", id, dependents),
+		})
+	}
+	return issues
+}
+
+// DanglingDependencies returns the IDs that components depend on but no
+// component declares, with the number of components depending on each, and
+// the number of dependency edges pointing at such IDs. The parsers drop edges
+// they cannot resolve, but filtering (e.g. --drop-invalid) can remove a
+// component others depend on.
+func DanglingDependencies(comps []Component) (implicit map[string]int, edges int) {
+	known := make(map[string]bool, len(comps))
+	for _, c := range comps {
+		known[c.ID] = true
+	}
+	implicit = make(map[string]int)
+	for _, c := range comps {
+		for _, dep := range c.Dependencies {
+			if !known[dep] {
+				edges++
+			}
+		}
+		for _, dep := range slices.Compact(slices.Sorted(slices.Values(c.Dependencies))) {
+			if !known[dep] {
+				implicit[dep]++
+			}
+		}
+	}
+	return implicit, edges
+}
+
+// countMismatch reports a document that lists more component entries than
+// the parser could read.
+func countMismatch(listed, parsed int) []Issue {
+	if listed == parsed {
+		return nil
+	}
+	return []Issue{{
+		Code:    IssueCountMismatch,
+		Field:   "components",
+		Message: fmt.Sprintf("document lists %d components but %d could be parsed", listed, parsed),
+	}}
+}
+
+// HasPlaceholderName reports whether c has an empty, NOASSERTION or NONE name.
+func HasPlaceholderName(c Component) bool {
+	switch strings.ToUpper(strings.TrimSpace(c.Name)) {
+	case "", "NOASSERTION", "NONE":
+		return true
+	}
+	return false
+}
+
+// DropPlaceholderNames returns comps without placeholder-named components.
+func DropPlaceholderNames(comps []Component) []Component {
+	kept := make([]Component, 0, len(comps))
+	for _, c := range comps {
+		if !HasPlaceholderName(c) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// purlVersionMismatch returns the PURL version when it disagrees with
+// c.Version. A PURL epoch qualifier is folded in, since deb/rpm versions
+// carry the epoch as an "N:" prefix. Image PURLs are skipped: their version
+// is a digest while the field holds the tag.
+func purlVersionMismatch(c Component) (string, bool) {
+	version := strings.TrimSpace(c.Version)
+	purlVer := identity.ExtractPURLVersion(c.PURL)
+	if version == "" || purlVer == "" {
+		return "", false
+	}
+	if strings.HasPrefix(c.PURL, "pkg:oci/") || strings.HasPrefix(c.PURL, "pkg:docker/") {
+		return "", false
+	}
+	if version == purlVer {
+		return "", false
+	}
+	if epoch := purlQualifier(c.PURL, "epoch"); epoch != "" && version == epoch+":"+purlVer {
+		return "", false
+	}
+	return purlVer, true
+}
+
+func purlQualifier(purl, key string) string {
+	_, query, ok := strings.Cut(purl, "?")
+	if !ok {
+		return ""
+	}
+	query, _, _ = strings.Cut(query, "#")
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+	return values.Get(key)
+}
+
+func componentLabel(c Component) string {
+	if c.Version == "" {
+		return c.Name
+	}
+	return c.Name + "@" + c.Version
+}
@@ -0,0 +1,182 @@
+package sbom
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidate_PURLVersionMismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		comp Component
+		want bool
+	}{
+		{"matching", Component{Name: "lodash", Version: "4.17.21", PURL: "pkg:npm/lodash@4.17.21"}, false},
+		{"mismatch", Component{Name: "lodash", Version: "4.17.20", PURL: "pkg:npm/lodash@4.17.21"}, true},
+		{"encoded purl version", Component{Name: "x", Version: "1.0+build", PURL: "pkg:generic/x@1.0%2Bbuild"}, false},
+		{"epoch qualifier", Component{Name: "bash", Version: "1:5.2-1", PURL: "pkg:deb/debian/bash@5.2-1?arch=amd64&epoch=1"}, false},
+		{"epoch missing from field", Component{Name: "bash", Version: "5.2-1", PURL: "pkg:deb/debian/bash@5.2-1?epoch=1"}, false},
+		{"no purl version", Component{Name: "x", Version: "1.0", PURL: "pkg:npm/x"}, false},
+		{"no field version", Component{Name: "x", PURL: "pkg:npm/x@1.0"}, false},
+		{"oci digest", Component{Name: "alpine", Version: "3.19", PURL: "pkg:oci/alpine@sha256:abc"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := Validate([]Component{tt.comp})
+			if got := len(issues) > 0; got != tt.want {
+				t.Fatalf("Validate() issues = %v, want mismatch=%v", issues, tt.want)
+			}
+			if tt.want {
+				if issues[0].Code != IssueVersionMismatch || issues[0].Field != "version" || !strings.Contains(issues[0].Message, "4.17.21") {
+					t.Errorf("unexpected issue %+v", issues[0])
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_PlaceholderNames(t *testing.T) {
+	comps := []Component{
+		{Name: "lodash", Version: "4.17.21", ID: "pkg:npm/lodash"},
+		{Name: "", Version: "1.0"},
+		{Name: "NOASSERTION", Version: "2.0"},
+		{Name: "NOASSERTION", Version: "3.0"},
+		{Name: " NONE "},
+		{Name: "none-such-lib", Version: "1.0"},
+	}
+
+	issues := Validate(comps)
+
+	var perComponent, shared int
+	for _, issue := range issues {
+		if issue.Field != "name" {
+			t.Errorf("unexpected issue %+v", issue)
+			continue
+		}
+		if issue.Component == "" {
+			shared++
+			if issue.Code != IssueSharedID || !strings.Contains(issue.Message, "share ID") {
+				t.Errorf("unexpected shared-ID issue %+v", issue)
+			}
+		} else {
+			perComponent++
+			if issue.Code != IssuePlaceholderName {
+				t.Errorf("expected code %s, got %+v", IssuePlaceholderName, issue)
+			}
+		}
+	}
+	if perComponent != 4 {
+		t.Errorf("expected 4 placeholder-name issues, got %d: %+v", perComponent, issues)
+	}
+	// both NOASSERTION components collapse to the same ID
+	if shared != 1 {
+		t.Errorf("expected 1 shared-ID issue, got %d: %+v", shared, issues)
+	}
+}
+
+func TestValidate_PlaceholderHashes(t *testing.T) {
+	comps := []Component{
+		{Name: "zero", Version: "1.0", Hashes: map[string]string{"SHA256": "0000000000000000000000000000000000000000000000000000000000000000"}},
+		{Name: "empty", Version: "1.0", Hashes: map[string]string{"SHA256": "", "SHA1": "da39a3ee5e6b4b0d3255bfef95601890afd80709"}},
+		{Name: "real", Version: "1.0", Hashes: map[string]string{"SHA1": "da39a3ee5e6b4b0d3255bfef95601890afd80709"}},
+	}
+
+	issues := Validate(comps)
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 placeholder-hash issues, got %+v", issues)
+	}
+	for i, want := range []string{"zero@1.0", "empty@1.0"} {
+		is := issues[i]
+		if is.Code != IssuePlaceholderHash || is.Field != "hashes" || is.Component != want || !strings.Contains(is.Message, "SHA256") {
+			t.Errorf("unexpected issue %+v", is)
+		}
+	}
+}
+
+func TestValidate_DanglingDependencies(t *testing.T) {
+	comps := []Component{
+		{ID: "pkg:npm/app", Name: "app", Version: "1.0", Dependencies: []string{"pkg:npm/lib", "pkg:npm/ghost"}},
+		{ID: "pkg:npm/lib", Name: "lib", Version: "2.0"},
+	}
+
+	// the missing target is reported once, as an implicit component
+	issues := Validate(comps)
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %+v", issues)
+	}
+	is := issues[0]
+	if is.Code != IssueImplicitComponent || is.Field != "dependencies" || !strings.Contains(is.Message, "pkg:npm/ghost") {
+		t.Errorf("unexpected issue %+v", is)
+	}
+}
+
+func TestValidate_ImplicitComponents(t *testing.T) {
+	comps := []Component{
+		{ID: "pkg:npm/app", Name: "app", Version: "1.0", Dependencies: []string{"pkg:npm/lib", "pkg:npm/ghost", "pkg:npm/phantom"}},
+		{ID: "pkg:npm/lib", Name: "lib", Version: "2.0", Dependencies: []string{"pkg:npm/ghost", "pkg:npm/ghost"}},
+		{ID: "pkg:npm/other", Name: "other", Version: "3.0"},
+	}
+
+	want := map[string]int{"pkg:npm/ghost": 2, "pkg:npm/phantom": 1}
+	got, edges := DanglingDependencies(comps)
+	if !maps.Equal(got, want) || edges != 4 {
+		t.Errorf("DanglingDependencies = %v, %d, want %v, 4", got, edges, want)
+	}
+
+	var msgs []string
+	for _, is := range Validate(comps) {
+		if is.Code == IssueImplicitComponent {
+			msgs = append(msgs, is.Message)
+		}
+	}
+	wantMsgs := []string{
+		`"pkg:npm/ghost" is a dependency of 2 components but is not declared as a component`,
+		`"pkg:npm/phantom" is a dependency of 1 component but is not declared as a component`,
+	}
+	if !slices.Equal(msgs, wantMsgs) {
+		t.Errorf("implicit_component issues = %q, want %q", msgs, wantMsgs)
+	}
+
+	if got, edges := DanglingDependencies(nil); len(got) != 0 || edges != 0 {
+		t.Errorf("expected no dangling dependencies for an empty SBOM, got %v, %d", got, edges)
+	}
+}
+
+func TestValidate_SelfDependency(t *testing.T) {
+	comps := []Component{
+		{ID: "pkg:npm/app", Name: "app", Version: "1.0", Dependencies: []string{"pkg:npm/lib"}},
+		{ID: "pkg:npm/lib", Name: "lib", Version: "2.0", Dependencies: []string{"pkg:npm/lib", "pkg:npm/lib"}},
+	}
+
+	var got []Issue
+	for _, is := range Validate(comps) {
+		if is.Code == IssueSelfDependency {
+			got = append(got, is)
+		}
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected one self_dependency issue, got %+v", got)
+	}
+	if got[0].Component != "lib@2.0" || got[0].Field != "dependencies" {
+		t.Errorf("unexpected issue %+v", got[0])
+	}
+}
+
+func TestDropPlaceholderNames(t *testing.T) {
+	comps := []Component{
+		{Name: "lodash"},
+		{Name: ""},
+		{Name: "NOASSERTION"},
+		{Name: "None"},
+		{Name: "express"},
+	}
+
+	kept := DropPlaceholderNames(comps)
+
+	if len(kept) != 2 || kept[0].Name != "lodash" || kept[1].Name != "express" {
+		t.Errorf("expected [lodash express], got %+v", kept)
+	}
+}
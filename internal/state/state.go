@@ -0,0 +1,120 @@
+// Package state is the --state snapshot store: a versioned JSON file
+// holding the component inventory of each run, so a run can diff against
+// the previous one without keeping "before" files around.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rezmoss/sbomlyze/internal/identity"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+// Version is the store format written by Save. Load rejects other versions.
+const Version = 1
+
+// Store is the on-disk snapshot history, oldest first. IDScheme is the
+// identity.SchemeVersion the component IDs were computed with.
+type Store struct {
+	Version   int        `json:"version"`
+	IDScheme  int        `json:"id_scheme"`
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// Snapshot is one run's inventory.
+type Snapshot struct {
+	Label      string           `json:"label"`
+	Created    time.Time        `json:"created"`
+	Source     string           `json:"source,omitempty"` // SBOM file the run read
+	Info       sbom.SBOMInfo    `json:"info"`
+	Components []sbom.Component `json:"components"`
+}
+
+// Load reads the store at path. A missing file is an empty store. When the
+// store was written with another identity scheme, the component IDs are
+// recomputed so they match IDs computed from a fresh parse.
+func Load(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Store{Version: Version, IDScheme: identity.SchemeVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Store
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	if s.Version != Version {
+		return nil, fmt.Errorf("%s: unsupported state version %d (want %d)", path, s.Version, Version)
+	}
+	if s.IDScheme != identity.SchemeVersion {
+		for i := range s.Snapshots {
+			s.Snapshots[i].Components = reidentify(s.Snapshots[i].Components)
+		}
+		s.IDScheme = identity.SchemeVersion
+	}
+	return &s, nil
+}
+
+// reidentify recomputes component IDs with the current identity scheme and
+// rewrites dependency edges to match. Edges to IDs no component carries are
+// left as they are.
+func reidentify(comps []sbom.Component) []sbom.Component {
+	ids := make(map[string]string, len(comps))
+	for i, c := range comps {
+		id := identity.ComputeID(c.ToIdentity())
+		ids[c.ID] = id
+		comps[i].ID = id
+	}
+	for i, c := range comps {
+		for j, dep := range c.Dependencies {
+			if id, ok := ids[dep]; ok {
+				comps[i].Dependencies[j] = id
+			}
+		}
+	}
+	return comps
+}
+
+// Latest returns the most recently added snapshot.
+func (s *Store) Latest() (Snapshot, bool) {
+	if len(s.Snapshots) == 0 {
+		return Snapshot{}, false
+	}
+	return s.Snapshots[len(s.Snapshots)-1], true
+}
+
+// Add appends snap as the latest snapshot.
+func (s *Store) Add(snap Snapshot) {
+	s.Snapshots = append(s.Snapshots, snap)
+}
+
+// Save writes the store to path. It writes a temporary file first, so an
+// interrupted run leaves the previous store intact.
+func (s *Store) Save(path string) error {
+	s.Version = Version
+	s.IDScheme = identity.SchemeVersion
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
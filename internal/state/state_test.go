@@ -0,0 +1,118 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rezmoss/sbomlyze/internal/identity"
+	"github.com/rezmoss/sbomlyze/internal/sbom"
+)
+
+func TestLoad_Missing(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := s.Latest(); ok {
+		t.Error("expected empty store")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := &Store{}
+	for _, label := range []string{"build-1", "build-2"} {
+		s.Add(Snapshot{
+			Label:      label,
+			Created:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Components: []sbom.Component{{ID: "pkg:npm/lodash", Name: "lodash", Version: label}},
+		})
+	}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != Version || got.IDScheme != identity.SchemeVersion || len(got.Snapshots) != 2 {
+		t.Fatalf("expected version %d, id scheme %d with 2 snapshots, got %+v", Version, identity.SchemeVersion, got)
+	}
+	latest, ok := got.Latest()
+	if !ok || latest.Label != "build-2" || latest.Components[0].Version != "build-2" {
+		t.Errorf("unexpected latest snapshot: %+v", latest)
+	}
+}
+
+func TestLoad_RecomputesIDsFromOtherScheme(t *testing.T) {
+	fetch := sbom.Component{Name: "node-fetch", Version: "2.6.7", CPEs: []string{`cpe:2.3:a:node\.js:node-fetch:2.6.7:*:*:*:*:*:*:*`}}
+	app := sbom.Component{Name: "app", Version: "1.0", PURL: "pkg:npm/app@1.0"}
+	oldID := identity.ComputeIDv1(fetch.ToIdentity())
+	newID := identity.ComputeID(fetch.ToIdentity())
+	if oldID == newID {
+		t.Fatalf("test needs a component whose ID differs between schemes, got %q for both", oldID)
+	}
+	fetch.ID = oldID
+	app.ID = identity.ComputeIDv1(app.ToIdentity())
+	app.Dependencies = []string{oldID, "missing"}
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := &Store{Snapshots: []Snapshot{{Label: "old", Components: []sbom.Component{app, fetch}}}}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	// rewrite the store as an older sbomlyze would have left it
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), fmt.Sprintf(`"id_scheme": %d`, identity.SchemeVersion), `"id_scheme": 1`, 1))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.IDScheme != identity.SchemeVersion {
+		t.Errorf("IDScheme = %d, want %d", got.IDScheme, identity.SchemeVersion)
+	}
+	latest, _ := got.Latest()
+	if latest.Components[1].ID != newID {
+		t.Errorf("ID = %q, want %q", latest.Components[1].ID, newID)
+	}
+	if deps := latest.Components[0].Dependencies; len(deps) != 2 || deps[0] != newID || deps[1] != "missing" {
+		t.Errorf("dependencies = %v, want [%s missing]", deps, newID)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"unsupported version", `{"version": 99, "snapshots": []}`, "unsupported state version 99"},
+		{"missing version", `{"snapshots": []}`, "unsupported state version 0"},
+		{"invalid json", `{`, "decode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -225,6 +225,21 @@ func (m Model) renderHeader() string {
 	if m.sbomInfo.SourceType != "" && m.sbomInfo.SourceName == "" {
 		infoItems = append(infoItems, headerInfoStyle.Render(" "+m.sbomInfo.SourceType))
 	}
+	if m.sbomInfo.ToolName != "" {
+		toolStr := m.sbomInfo.ToolName
+		if m.sbomInfo.ToolVersion != "" {
+			toolStr += " " + m.sbomInfo.ToolVersion
+		}
+		infoItems = append(infoItems, headerInfoStyle.Render(" "+toolStr))
+	}
+	if m.sbomInfo.Timestamp != "" {
+		// Date only; the full timestamp is in the JSON output
+		date, _, _ := strings.Cut(m.sbomInfo.Timestamp, "T")
+		infoItems = append(infoItems, headerInfoStyle.Render(" "+date))
+	}
+	if len(m.sbomInfo.Authors) > 0 {
+		infoItems = append(infoItems, headerInfoStyle.Render(" by "+m.sbomInfo.Authors[0]))
+	}
 
 	var countText string
 	if m.searchQuery != "" || m.filterType != "" {
@@ -560,6 +575,18 @@ func (m Model) renderComponentDetail(c sbom.Component) string {
 		}
 	}
 
+	// Security References Section
+	if len(c.References) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(sectionTitleStyle.Render("SECURITY REFERENCES"))
+		sb.WriteString("\n")
+		for _, ref := range c.References {
+			sb.WriteString(labelStyle.Render(ref.Type))
+			sb.WriteString(dimStyle.Render(ref.Locator))
+			sb.WriteString("\n")
+		}
+	}
+
 	// Dependencies Section
 		sb.WriteString("\n")
 		sb.WriteString(sectionTitleStyle.Render(fmt.Sprintf("DEPENDENCIES (%d)", len(c.Dependencies))))
@@ -627,9 +654,6 @@ func (m Model) renderComponentJSON(c sbom.Component) string {
 
 func syntaxHighlightJSON(jsonStr string) string {
 	var result strings.Builder
-	inString := false
-	inKey := false
-	stringStart := 0
 	i := 0
 
 	runes := []rune(jsonStr)
@@ -639,42 +663,23 @@ func syntaxHighlightJSON(jsonStr string) string {
 		ch := runes[i]
 
 		switch {
-		case ch == '"' && (i == 0 || runes[i-1] != '\\'):
-			if !inString {
-				inString = true
-				stringStart = i
-				inKey = false
-				for j := i + 1; j < length; j++ {
-					if runes[j] == '"' && runes[j-1] != '\\' {
-						for k := j + 1; k < length; k++ {
-							if runes[k] == ':' {
-								inKey = true
-								break
-							} else if runes[k] != ' ' && runes[k] != '\t' && runes[k] != '\n' {
-								break
-							}
-						}
-						break
-					}
-				}
+		case ch == '"':
+			end := jsonStringEnd(runes, i)
+			strContent := string(runes[i:end])
+			if jsonIsKey(runes, end) {
+				result.WriteString(jsonKeyStyle.Render(strContent))
 			} else {
-				inString = false
-				strContent := string(runes[stringStart : i+1])
-				if inKey {
-					result.WriteString(jsonKeyStyle.Render(strContent))
-				} else {
-					result.WriteString(jsonStringStyle.Render(strContent))
-				}
-				i++
-				continue
+				result.WriteString(jsonStringStyle.Render(strContent))
 			}
+			i = end
+			continue
 
-		case !inString && (ch == '{' || ch == '}' || ch == '[' || ch == ']'):
+		case ch == '{' || ch == '}' || ch == '[' || ch == ']':
 			result.WriteString(jsonBracketStyle.Render(string(ch)))
 			i++
 			continue
 
-		case !inString && ch == ':':
+		case ch == ':':
 			result.WriteString(jsonColonStyle.Render(": "))
 			i++
 			if i < length && runes[i] == ' ' {
@@ -682,12 +687,12 @@ func syntaxHighlightJSON(jsonStr string) string {
 			}
 			continue
 
-		case !inString && ch == ',':
+		case ch == ',':
 			result.WriteString(jsonColonStyle.Render(","))
 			i++
 			continue
 
-		case !inString && (ch == 't' || ch == 'f'):
+		case ch == 't' || ch == 'f':
 			if i+4 <= length && string(runes[i:i+4]) == "true" {
 				result.WriteString(jsonBoolStyle.Render("true"))
 				i += 4
@@ -699,14 +704,14 @@ func syntaxHighlightJSON(jsonStr string) string {
 				continue
 			}
 
-		case !inString && ch == 'n':
+		case ch == 'n':
 			if i+4 <= length && string(runes[i:i+4]) == "null" {
 				result.WriteString(jsonNullStyle.Render("null"))
 				i += 4
 				continue
 			}
 
-		case !inString && (ch >= '0' && ch <= '9' || ch == '-'):
+		case ch >= '0' && ch <= '9' || ch == '-':
 			numStart := i
 			for i < length && (runes[i] >= '0' && runes[i] <= '9' || runes[i] == '.' || runes[i] == '-' || runes[i] == 'e' || runes[i] == 'E' || runes[i] == '+') {
 				i++
@@ -715,15 +720,42 @@ func syntaxHighlightJSON(jsonStr string) string {
 			continue
 		}
 
-		if !inString {
-			result.WriteRune(ch)
-		}
+		result.WriteRune(ch)
 		i++
 	}
 
 	return result.String()
 }
 
+// jsonStringEnd returns the index just past the string literal opening at
+// start. Backslash escapes (\\, \", \uXXXX) stay inside the span; an
+// unterminated string runs to the end of input.
+func jsonStringEnd(runes []rune, start int) int {
+	for j := start + 1; j < len(runes); j++ {
+		switch runes[j] {
+		case '\\':
+			j++ // skip the escaped rune
+		case '"':
+			return j + 1
+		}
+	}
+	return len(runes)
+}
+
+// jsonIsKey reports whether the next non-space rune at or after i is a colon.
+func jsonIsKey(runes []rune, i int) bool {
+	for ; i < len(runes); i++ {
+		switch runes[i] {
+		case ':':
+			return true
+		case ' ', '\t', '\n', '\r':
+		default:
+			return false
+		}
+	}
+	return false
+}
+
 func (m Model) renderHelp() string {
 	var sb strings.Builder
 
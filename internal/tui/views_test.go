@@ -0,0 +1,54 @@
+package tui
+
+import "testing"
+
+func TestJSONStringEnd(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // the string literal span
+	}{
+		{"plain", `"abc", 1`, `"abc"`},
+		{"escaped quote", `"say \"hi\"", 1`, `"say \"hi\""`},
+		{"trailing escaped backslash", `"C:\\dir\\": 1`, `"C:\\dir\\"`},
+		{"backslash then escaped quote", `"a\\\"b" x`, `"a\\\"b"`},
+		{"unicode escape", `"caf\u00e9 \u0022" x`, `"caf\u00e9 \u0022"`},
+		{"unterminated", `"abc\"`, `"abc\"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runes := []rune(tt.input)
+			end := jsonStringEnd(runes, 0)
+			if got := string(runes[:end]); got != tt.want {
+				t.Errorf("jsonStringEnd(%s) span = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONIsKey(t *testing.T) {
+	runes := []rune(`"k" : "v", "x"]`)
+	if !jsonIsKey(runes, 3) {
+		t.Error("expected key before colon")
+	}
+	if jsonIsKey(runes, 10) {
+		t.Error("expected value before comma")
+	}
+}
+
+func TestSyntaxHighlightJSON_PreservesEscapes(t *testing.T) {
+	// Styles render as plain text without a terminal, so output matches input.
+	inputs := []string{
+		`{"path": "C:\\Program Files\\", "n": 1}`,
+		`{"msg": "say \"hi\"", "ok": true}`,
+		`{"name": "caf\u00e9", "q": "\u0022quoted\u0022", "v": null}`,
+		`{"k\"ey": ["a\\", "b"]}`,
+	}
+
+	for _, in := range inputs {
+		if got := syntaxHighlightJSON(in); got != in {
+			t.Errorf("syntaxHighlightJSON(%s) = %s", in, got)
+		}
+	}
+}
@@ -593,8 +593,7 @@ func handleFilesystem(w http.ResponseWriter, r *http.Request) {
 	if dirPath == "" {
 		dirPath = "/"
 	}
-	offset := parseIntParam(r, "offset", 0)
-	limit := parseIntParam(r, "limit", 100)
+	offset, limit := pageParams(r, 100)
 	layerFilter := r.URL.Query().Get("layer")
 	componentFilter := r.URL.Query().Get("component")
 
@@ -696,38 +695,28 @@ func handleFilesystem(w http.ResponseWriter, r *http.Request) {
 		}
 
 		total = len(matches)
-		if offset < len(matches) {
-			end := offset + limit
-			if end > len(matches) {
-				end = len(matches)
-			}
-			entries = matches[offset:end]
-		}
+		start, end := pageRange(offset, limit, total)
+		entries = matches[start:end]
 	} else {
 		children := idx.DirEntries[dirPath]
 		total = len(children)
-		if offset < len(children) {
-			end := offset + limit
-			if end > len(children) {
-				end = len(children)
+		start, end := pageRange(offset, limit, total)
+		for _, child := range children[start:end] {
+			entry := FileBrowseEntry{
+				Name:  child.Name,
+				Path:  child.Path,
+				IsDir: child.IsDir,
 			}
-			for _, child := range children[offset:end] {
-				entry := FileBrowseEntry{
-					Name:  child.Name,
-					Path:  child.Path,
-					IsDir: child.IsDir,
-				}
-				if child.IsDir {
-					entry.Children = len(idx.DirEntries[child.Path])
-				} else if fi, ok := idx.PathToIdx[child.Path]; ok {
-					f := idx.Files[fi]
-					entry.FileType = f.FileType
-					entry.MimeType = f.MimeType
-					entry.Size = f.Size
-					entry.Mode = f.Mode
-				}
-				entries = append(entries, entry)
+			if child.IsDir {
+				entry.Children = len(idx.DirEntries[child.Path])
+			} else if fi, ok := idx.PathToIdx[child.Path]; ok {
+				f := idx.Files[fi]
+				entry.FileType = f.FileType
+				entry.MimeType = f.MimeType
+				entry.Size = f.Size
+				entry.Mode = f.Mode
 			}
+			entries = append(entries, entry)
 		}
 	}
 
@@ -2,13 +2,16 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/cli"
 	"github.com/rezmoss/sbomlyze/internal/sbom"
 )
 
@@ -32,22 +35,46 @@ type ComponentDetail struct {
 	Hashes       map[string]string `json:"hashes,omitempty"`
 	Dependencies []string          `json:"dependencies,omitempty"`
 	Supplier     string            `json:"supplier,omitempty"`
+	References   []sbom.Reference  `json:"references,omitempty"`
 	RawJSON      json.RawMessage   `json:"rawJson,omitempty"`
 	FileCount    int               `json:"fileCount"`
 }
 
+// limitUpload caps the request body at maxSize bytes and sets a read
+// deadline of timeout, so a slow or oversized upload cannot hold the
+// server.
+func limitUpload(maxSize int64, timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// not every ResponseWriter supports deadlines (e.g. in tests)
+		_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(timeout))
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+		next(w, r)
+	}
+}
+
+// uploadError reports a failed upload read: 413 when the body exceeded
+// the size limit, otherwise 400.
+func uploadError(w http.ResponseWriter, msg string, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "Upload too large: limit is "+strconv.FormatInt(tooLarge.Limit, 10)+" bytes", http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, msg+err.Error(), http.StatusBadRequest)
+}
+
 func handleUpload(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	if err := r.ParseMultipartForm(500 << 20); err != nil {
-		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		uploadError(w, "Failed to parse form: ", err)
 		return
 	}
 
-	file, _, err := r.FormFile("file")
+	file, header, err := r.FormFile("file")
 	if err != nil {
 		http.Error(w, "Failed to get file: "+err.Error(), http.StatusBadRequest)
 		return
@@ -56,32 +83,34 @@ func handleUpload(w http.ResponseWriter, r *http.Request) {
 
 	data, err := io.ReadAll(file)
 	if err != nil {
-		http.Error(w, "Failed to read file: "+err.Error(), http.StatusBadRequest)
+		uploadError(w, "Failed to read file: ", err)
 		return
 	}
 
-	var comps []sbom.Component
-	var info sbom.SBOMInfo
+	if sbom.IsAttestation(data) {
+		inner, err := sbom.UnwrapAttestation(data)
+		if err != nil {
+			http.Error(w, "Failed to parse SBOM: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		data = inner
+	}
 
-	if sbom.IsCycloneDX(data) {
-		comps, info, err = sbom.ParseCycloneDXWithInfo(data)
-	} else if sbom.IsSyft(data) {
-		comps, info, err = sbom.ParseSyftWithInfo(data)
-	} else if sbom.IsSPDX(data) {
-		comps, err = sbom.ParseSPDXFromBytes(data)
-	} else {
+	parseOpts := cli.DefaultParseOptions()
+	comps, info, err := parseUpload(data, header.Filename, &parseOpts)
+	if errors.Is(err, sbom.ErrUnknownFormat) {
 		http.Error(w, "Unknown SBOM format", http.StatusBadRequest)
 		return
 	}
-
 	if err != nil {
 		http.Error(w, "Failed to parse SBOM: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	comps = sbom.NormalizeComponents(comps)
-	stats := analysis.ComputeStats(comps)
-	depGraph := analysis.BuildDependencyGraph(comps)
+	graph := analysis.NewGraph(comps)
+	stats := analysis.ComputeStatsWithGraph(comps, graph)
+	depGraph := graph.Edges
 
 	relationships := info.RelationshipCounts
 	if relationships == nil {
@@ -114,6 +143,7 @@ func handleUpload(w http.ResponseWriter, r *http.Request) {
 		"success":    true,
 		"components": len(comps),
 		"info":       info,
+		"warnings":   parseOpts.Warnings,
 	}
 	if fileIdx != nil {
 		resp["filesCount"] = fileIdx.TotalFiles
@@ -123,14 +153,41 @@ func handleUpload(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// parseUpload parses an uploaded SBOM. Malformed entries the parser can
+// skip or report, such as dangling dependency refs or placeholder names,
+// are recorded as warnings in opts rather than failing the upload.
+func parseUpload(data []byte, name string, opts *cli.ParseOptions) ([]sbom.Component, sbom.SBOMInfo, error) {
+	var comps []sbom.Component
+	var info sbom.SBOMInfo
+	var err error
+
+	switch {
+	case sbom.IsCycloneDX(data):
+		comps, info, err = sbom.ParseCycloneDXWithInfo(data)
+	case sbom.IsSyft(data):
+		comps, info, err = sbom.ParseSyftWithInfo(data)
+	case sbom.IsSPDX(data):
+		comps, err = sbom.ParseSPDXFromBytes(data)
+	default:
+		return nil, sbom.SBOMInfo{}, sbom.ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, sbom.SBOMInfo{}, err
+	}
+
+	for _, issue := range append(info.ParseIssues, sbom.Validate(comps)...) {
+		opts.AddWarning(issue.Code, name, issue.Message, issue.Field)
+	}
+	return comps, info, nil
+}
+
 func handleGetTree(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	offset := parseIntParam(r, "offset", 0)
-	limit := parseIntParam(r, "limit", 200)
+	offset, limit := pageParams(r, 200)
 
 	state.mu.RLock()
 	defer state.mu.RUnlock()
@@ -150,16 +207,9 @@ func handleGetTree(w http.ResponseWriter, r *http.Request) {
 
 	if total > treeThreshold {
 		// Large dataset: return flat paginated list (no tree expansion)
-		if offset > total {
-			offset = total
-		}
-		end := offset + limit
-		if end > total {
-			end = total
-		}
-
-		nodes := make([]TreeNode, 0, end-offset)
-		for i := offset; i < end; i++ {
+		start, end := pageRange(offset, limit, total)
+		nodes := make([]TreeNode, 0, end-start)
+		for i := start; i < end; i++ {
 			c := state.Components[i]
 			nodes = append(nodes, TreeNode{
 				ID:          c.ID,
@@ -250,19 +300,85 @@ func handleGetStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if state.Stats.TotalComponents > 0 {
-		total := float64(state.Stats.TotalComponents)
-		response["coverage"] = map[string]interface{}{
-			"cpe_percent":     float64(state.Stats.WithCPEs) / total * 100,
-			"purl_percent":    float64(state.Stats.WithPURL) / total * 100,
-			"license_percent": float64(state.Stats.TotalComponents-state.Stats.WithoutLicense) / total * 100,
-			"hash_percent":    float64(state.Stats.WithHashes) / total * 100,
-		}
+		response["coverage"] = state.Stats.CoveragePercent
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+// Coverage colors for /api/summary, at the thresholds of the bundled UI's
+// coverage bars.
+const (
+	CoverageGood   = "green"  // 80% and up
+	CoverageMedium = "yellow" // 50% and up
+	CoverageLow    = "red"
+)
+
+// MetricSummary is one coverage metric, formatted for display.
+type MetricSummary struct {
+	Key     string  `json:"key"`
+	Label   string  `json:"label"`
+	Percent float64 `json:"percent"`
+	Display string  `json:"display"`
+	Color   string  `json:"color"`
+}
+
+// SummaryResponse is the /api/summary body.
+type SummaryResponse struct {
+	TotalComponents int             `json:"totalComponents"`
+	Metrics         []MetricSummary `json:"metrics"`
+}
+
+// coverageColor maps a coverage percentage to its display color.
+func coverageColor(percent float64) string {
+	switch {
+	case percent >= 80:
+		return CoverageGood
+	case percent >= 50:
+		return CoverageMedium
+	default:
+		return CoverageLow
+	}
+}
+
+// handleGetSummary returns the coverage metrics with display strings and
+// colors, for frontends that do not want to apply thresholds themselves.
+func handleGetSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	resp := SummaryResponse{TotalComponents: state.Stats.TotalComponents, Metrics: []MetricSummary{}}
+	if state.Stats.TotalComponents > 0 {
+		cov := state.Stats.CoveragePercent
+		for _, m := range []struct {
+			key, label string
+			pct        float64
+		}{
+			{"purl", "PURL Coverage", cov.PURL},
+			{"cpe", "CPE Coverage", cov.CPE},
+			{"license", "License Coverage", cov.License},
+			{"hash", "Hash Coverage", cov.Hash},
+		} {
+			resp.Metrics = append(resp.Metrics, MetricSummary{
+				Key:     m.key,
+				Label:   m.label,
+				Percent: m.pct,
+				Display: strconv.FormatFloat(m.pct, 'f', 1, 64) + "%",
+				Color:   coverageColor(m.pct),
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
 func handleGetComponent(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -295,6 +411,7 @@ func handleGetComponent(w http.ResponseWriter, r *http.Request) {
 		Hashes:       c.Hashes,
 		Dependencies: state.DepGraph[c.ID],
 		Supplier:     c.Supplier,
+		References:   c.References,
 		RawJSON:      c.RawJSON,
 	}
 
@@ -317,14 +434,20 @@ func handleSearch(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Search query required", http.StatusBadRequest)
 		return
 	}
+	offset, limit := pageParams(r, 200)
 
 	state.mu.RLock()
 	defer state.mu.RUnlock()
 
 	var results []ComponentDetail
+	total := 0
 
 	for i, searchStr := range state.SearchIndex {
 		if strings.Contains(searchStr, query) {
+			total++
+			if total <= offset || len(results) >= limit {
+				continue
+			}
 			c := state.Components[i]
 			results = append(results, ComponentDetail{
 				ID:       c.ID,
@@ -345,7 +468,7 @@ func handleSearch(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"results": results,
-		"total":   len(results),
+		"total":   total,
 	})
 }
 
@@ -361,6 +484,26 @@ func parseIntParam(r *http.Request, name string, defaultVal int) int {
 	return v
 }
 
+// maxPageLimit caps the limit parameter of paginated endpoints.
+const maxPageLimit = 1000
+
+// pageParams reads the offset and limit query parameters. A missing or
+// zero limit uses defaultLimit; larger limits are capped at maxPageLimit.
+func pageParams(r *http.Request, defaultLimit int) (offset, limit int) {
+	offset = parseIntParam(r, "offset", 0)
+	limit = parseIntParam(r, "limit", defaultLimit)
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	return offset, min(limit, maxPageLimit)
+}
+
+// pageRange returns the bounds of the page within total items.
+func pageRange(offset, limit, total int) (start, end int) {
+	start = min(offset, total)
+	return start, min(start+limit, total)
+}
+
 func buildCompIndex(comps []sbom.Component) map[string]int {
 	idx := make(map[string]int, len(comps))
 	for i, c := range comps {
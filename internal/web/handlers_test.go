@@ -8,9 +8,12 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rezmoss/sbomlyze/internal/analysis"
+	"github.com/rezmoss/sbomlyze/internal/cli"
 	"github.com/rezmoss/sbomlyze/internal/sbom"
 )
 
@@ -238,6 +241,79 @@ func TestHandleUpload_NoFile(t *testing.T) {
 	}
 }
 
+func TestHandleUpload_Warnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		wantCode string // "" means no warnings
+	}{
+		{"clean file", "cyclonedx-before.json", ""},
+		{"dangling dependency", "cyclonedx-dangling-dependency.json", sbom.IssueDanglingDependency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetState()
+			req, err := createMultipartRequest(webTestdataPath(tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handleUpload(rr, req)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			var resp struct {
+				Warnings []cli.ParseWarning `json:"warnings"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if tt.wantCode == "" {
+				if len(resp.Warnings) != 0 {
+					t.Errorf("expected no warnings, got %+v", resp.Warnings)
+				}
+				return
+			}
+			found := false
+			for _, w := range resp.Warnings {
+				if w.Code == tt.wantCode && w.File == tt.file {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected a %q warning for %s, got %+v", tt.wantCode, tt.file, resp.Warnings)
+			}
+		})
+	}
+}
+
+func TestHandleUpload_SizeLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxSize  int64
+		wantCode int
+	}{
+		{"within limit", DefaultMaxUploadSize, http.StatusOK},
+		{"over limit", 100, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetState()
+			req, err := createMultipartRequest(webTestdataPath("cyclonedx-before.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			limitUpload(tt.maxSize, time.Minute, handleUpload)(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Errorf("expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
 // --- Tree Handler Tests ---
 
 func TestHandleGetTree_WithData(t *testing.T) {
@@ -386,6 +462,85 @@ func TestHandleGetStats_CoveragePercentages(t *testing.T) {
 	}
 }
 
+func TestCoverageColor(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    string
+	}{
+		{100, CoverageGood},
+		{80, CoverageGood},
+		{79.9, CoverageMedium},
+		{50, CoverageMedium},
+		{49.9, CoverageLow},
+		{0, CoverageLow},
+	}
+	for _, tt := range tests {
+		if got := coverageColor(tt.percent); got != tt.want {
+			t.Errorf("coverageColor(%v) = %q, want %q", tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestHandleGetSummary(t *testing.T) {
+	resetState()
+	// PURL 100%, license 75%, hash 25%, CPE 0%
+	loadTestState([]sbom.Component{
+		{ID: "a", Name: "a", PURL: "pkg:npm/a@1.0", Licenses: []string{"MIT"}, Hashes: map[string]string{"SHA256": "x"}},
+		{ID: "b", Name: "b", PURL: "pkg:npm/b@1.0", Licenses: []string{"MIT"}},
+		{ID: "c", Name: "c", PURL: "pkg:npm/c@1.0", Licenses: []string{"MIT"}},
+		{ID: "d", Name: "d", PURL: "pkg:npm/d@1.0"},
+	}, sbom.SBOMInfo{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/summary", nil)
+	rr := httptest.NewRecorder()
+	handleGetSummary(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp SummaryResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.TotalComponents != 4 {
+		t.Errorf("expected 4 components, got %d", resp.TotalComponents)
+	}
+	want := map[string]MetricSummary{
+		"purl":    {Key: "purl", Label: "PURL Coverage", Percent: 100, Display: "100.0%", Color: CoverageGood},
+		"license": {Key: "license", Label: "License Coverage", Percent: 75, Display: "75.0%", Color: CoverageMedium},
+		"hash":    {Key: "hash", Label: "Hash Coverage", Percent: 25, Display: "25.0%", Color: CoverageLow},
+		"cpe":     {Key: "cpe", Label: "CPE Coverage", Percent: 0, Display: "0.0%", Color: CoverageLow},
+	}
+	if len(resp.Metrics) != len(want) {
+		t.Fatalf("expected %d metrics, got %+v", len(want), resp.Metrics)
+	}
+	for _, m := range resp.Metrics {
+		if m != want[m.Key] {
+			t.Errorf("metric %s = %+v, want %+v", m.Key, m, want[m.Key])
+		}
+	}
+}
+
+func TestHandleGetSummary_Empty(t *testing.T) {
+	resetState()
+	req := httptest.NewRequest(http.MethodGet, "/api/summary", nil)
+	rr := httptest.NewRecorder()
+	handleGetSummary(rr, req)
+
+	if got := strings.TrimSpace(rr.Body.String()); got != `{"totalComponents":0,"metrics":[]}` {
+		t.Errorf("unexpected empty summary %s", got)
+	}
+}
+
+func TestHandleGetSummary_MethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/summary", nil)
+	rr := httptest.NewRecorder()
+	handleGetSummary(rr, req)
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rr.Code)
+	}
+}
+
 // --- Component Handler Tests ---
 
 func TestHandleGetComponent_Found(t *testing.T) {
@@ -410,6 +565,26 @@ func TestHandleGetComponent_Found(t *testing.T) {
 	}
 }
 
+func TestHandleGetComponent_References(t *testing.T) {
+	resetState()
+	refs := []sbom.Reference{{Type: "advisory", Locator: "https://curl.se/docs/CVE-2023-38545.html"}}
+	loadTestState([]sbom.Component{
+		{ID: "pkg:generic/curl", Name: "curl", Version: "8.0.0", References: refs},
+	}, sbom.SBOMInfo{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/component/pkg:generic/curl", nil)
+	rr := httptest.NewRecorder()
+	handleGetComponent(rr, req)
+
+	var detail ComponentDetail
+	if err := json.Unmarshal(rr.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(detail.References) != 1 || detail.References[0] != refs[0] {
+		t.Errorf("expected references %+v, got %+v", refs, detail.References)
+	}
+}
+
 func TestHandleGetComponent_NotFound(t *testing.T) {
 	resetState()
 	loadTestState([]sbom.Component{
@@ -459,6 +634,76 @@ func TestHandleSearch_ByName(t *testing.T) {
 	}
 }
 
+func TestHandleSearch_Pagination(t *testing.T) {
+	resetState()
+	var comps []sbom.Component
+	for _, name := range []string{"lib-a", "lib-b", "lib-c", "lib-d", "lib-e", "other"} {
+		comps = append(comps, sbom.Component{ID: name, Name: name})
+	}
+	loadTestState(comps, sbom.SBOMInfo{})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"default limit", "q=lib", []string{"lib-a", "lib-b", "lib-c", "lib-d", "lib-e"}},
+		{"first page", "q=lib&limit=2", []string{"lib-a", "lib-b"}},
+		{"second page", "q=lib&limit=2&offset=2", []string{"lib-c", "lib-d"}},
+		{"last partial page", "q=lib&limit=2&offset=4", []string{"lib-e"}},
+		{"offset past end", "q=lib&offset=10", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/search?"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handleSearch(rr, req)
+
+			var resp struct {
+				Results []ComponentDetail `json:"results"`
+				Total   int               `json:"total"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.Total != 5 {
+				t.Errorf("expected total=5, got %d", resp.Total)
+			}
+			var got []string
+			for _, r := range resp.Results {
+				got = append(got, r.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPageParams(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantOffset int
+		wantLimit  int
+	}{
+		{"", 0, 50},
+		{"offset=5&limit=10", 5, 10},
+		{"limit=0", 0, 50},
+		{"limit=100000", 0, maxPageLimit},
+		{"offset=-1&limit=abc", 0, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/search?"+tt.query, nil)
+			offset, limit := pageParams(req, 50)
+			if offset != tt.wantOffset || limit != tt.wantLimit {
+				t.Errorf("pageParams(%q) = %d, %d; want %d, %d", tt.query, offset, limit, tt.wantOffset, tt.wantLimit)
+			}
+		})
+	}
+}
+
 func TestHandleSearch_ByLicense(t *testing.T) {
 	resetState()
 	loadTestState([]sbom.Component{
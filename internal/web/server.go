@@ -5,6 +5,7 @@ import (
 	"io/fs"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/rezmoss/sbomlyze/internal/analysis"
 	"github.com/rezmoss/sbomlyze/internal/sbom"
@@ -26,14 +27,37 @@ type ServerState struct {
 
 var state = &ServerState{}
 
+// Upload limits used when ServerOptions leaves them zero.
+const (
+	DefaultMaxUploadSize = 500 << 20
+	DefaultUploadTimeout = 5 * time.Minute
+)
+
+// ServerOptions configures the web server.
+type ServerOptions struct {
+	Port          int
+	MaxUploadSize int64         // bytes per upload request
+	UploadTimeout time.Duration // deadline for reading an upload request
+}
+
 // Serve starts the web server.
-func Serve(port int) error {
+func Serve(opts ServerOptions) error {
+	maxSize := opts.MaxUploadSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxUploadSize
+	}
+	timeout := opts.UploadTimeout
+	if timeout <= 0 {
+		timeout = DefaultUploadTimeout
+	}
+
 	mux := http.NewServeMux()
 
 	// API routes
-	mux.HandleFunc("/api/upload", handleUpload)
+	mux.HandleFunc("/api/upload", limitUpload(maxSize, timeout, handleUpload))
 	mux.HandleFunc("/api/tree", handleGetTree)
 	mux.HandleFunc("/api/stats", handleGetStats)
+	mux.HandleFunc("/api/summary", handleGetSummary)
 	mux.HandleFunc("/api/component/", handleGetComponent)
 	mux.HandleFunc("/api/search", handleSearch)
 	mux.HandleFunc("/api/filesystem", handleFilesystem)
@@ -47,6 +71,6 @@ func Serve(port int) error {
 	}
 	mux.Handle("/", http.FileServer(http.FS(staticFS)))
 
-	addr := fmt.Sprintf(":%d", port)
+	addr := fmt.Sprintf(":%d", opts.Port)
 	return http.ListenAndServe(addr, mux)
 }
@@ -474,6 +474,24 @@ function renderDetail(detail) {
             `;
         }
 
+        if (detail.references && detail.references.length > 0) {
+            html += `
+                <div class="detail-section">
+                    <h3>Security References</h3>
+                    <ul class="detail-list">
+                        ${detail.references.map(ref => `
+                            <li class="hash-item">
+                                <span class="hash-algo">${escapeHtml(ref.type)}</span>
+                                ${/^https?:\/\//.test(ref.locator)
+                                    ? `<a class="hash-value" href="${escapeHtml(ref.locator).replace(/"/g, '&quot;')}" target="_blank" rel="noopener noreferrer">${escapeHtml(ref.locator)}</a>`
+                                    : `<span class="hash-value">${escapeHtml(ref.locator)}</span>`}
+                            </li>
+                        `).join('')}
+                    </ul>
+                </div>
+            `;
+        }
+
         if (detail.dependencies && detail.dependencies.length > 0) {
             html += `
                 <div class="detail-section">
@@ -679,6 +697,7 @@ function renderStatBars(data, limit) {
 
 function renderCoverageBar(label, percent) {
     const safePercent = Math.min(100, Math.max(0, percent || 0));
+    // same thresholds as /api/summary
     const colorClass = safePercent >= 80 ? 'coverage-good' : safePercent >= 50 ? 'coverage-medium' : 'coverage-low';
 
     return `